@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+const defaultPort = "8080"
+
+// listenAddr resolves the address the server binds to. LISTEN_ADDR (listen),
+// when set, is used as it is, e.g. 127.0.0.1:8080 to accept only local
+// connections; otherwise the server binds to PORT (port) on all interfaces.
+// The result must be a host:port pair with a numeric port.
+func listenAddr(listen, port string) (string, error) {
+	addr := listen
+	if addr == "" {
+		if port == "" {
+			port = defaultPort
+		}
+		addr = ":" + port
+	}
+
+	_, rawPort, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if n, err := strconv.Atoi(rawPort); err != nil || n < 0 || n > 65535 {
+		return "", fmt.Errorf("invalid listen address %q: port must be a number between 0 and 65535", addr)
+	}
+	return addr, nil
+}
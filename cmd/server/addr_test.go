@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		name    string
+		listen  string
+		port    string
+		want    string
+		wantErr bool
+	}{
+		{name: "defaults", want: ":8080"},
+		{name: "port", port: "9090", want: ":9090"},
+		{name: "listen addr", listen: "127.0.0.1:8080", want: "127.0.0.1:8080"},
+		{name: "listen addr wins over port", listen: "127.0.0.1:7000", port: "9090", want: "127.0.0.1:7000"},
+		{name: "hostname", listen: "localhost:8080", want: "localhost:8080"},
+		{name: "ipv6", listen: "[::1]:8080", want: "[::1]:8080"},
+		{name: "all interfaces", listen: ":8080", want: ":8080"},
+		{name: "missing port", listen: "127.0.0.1", wantErr: true},
+		{name: "unbracketed ipv6", listen: "::1:8080", wantErr: true},
+		{name: "named port", listen: "127.0.0.1:http", wantErr: true},
+		{name: "port out of range", listen: "127.0.0.1:70000", wantErr: true},
+		{name: "invalid port", port: "eighty", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := listenAddr(tc.listen, tc.port)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("listenAddr(%q, %q) = %q, want error", tc.listen, tc.port, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("listenAddr(%q, %q) returned error: %v", tc.listen, tc.port, err)
+			}
+			if got != tc.want {
+				t.Fatalf("listenAddr(%q, %q) = %q, want %q", tc.listen, tc.port, got, tc.want)
+			}
+		})
+	}
+}
@@ -3,30 +3,73 @@ package main
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"gymshark/internal/api"
+	"gymshark/internal/logging"
+	"gymshark/internal/service"
+	"gymshark/internal/telemetry"
 )
 
-const serverTimeout = 5 * time.Second
+const (
+	serverTimeout            = 5 * time.Second
+	defaultTableCacheCeiling = 100_000
+)
 
 func main() {
+	if err := logging.Setup(); err != nil {
+		fatal("invalid LOG_LEVEL", err)
+	}
+
+	shutdownTelemetry, err := telemetry.Setup(context.Background())
+	if err != nil {
+		fatal("unable to initialize telemetry", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), serverTimeout)
+		defer cancel()
+		if err := shutdownTelemetry(ctx); err != nil {
+			slog.Warn("telemetry shutdown failed", "error", err)
+		}
+	}()
+
+	serviceConfig, err := service.ConfigFromEnv()
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
+	if err := service.SetConfig(serviceConfig); err != nil {
+		fatal("invalid configuration", err)
+	}
+
+	if dir := os.Getenv("TABLE_CACHE_DIR"); dir != "" {
+		prepareTableCache(dir)
+	}
+
+	if err := service.PrimeResultCache(); err != nil {
+		fatal("invalid PRIME_ORDERS", err)
+	}
+
 	handler, err := api.NewHandler()
 	if err != nil {
-		log.Fatalf("unable to initialize handler: %v", err)
+		fatal("unable to initialize handler", err)
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	addr, err := listenAddr(os.Getenv("LISTEN_ADDR"), os.Getenv("PORT"))
+	if err != nil {
+		fatal("invalid LISTEN_ADDR or PORT", err)
+	}
+
+	certs, err := resolveTLS(os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE"))
+	if err != nil {
+		fatal("invalid TLS configuration", err)
 	}
 
-	addr := ":" + port
 	server := &http.Server{
 		Addr:              addr,
 		Handler:           handler,
@@ -41,8 +84,8 @@ func main() {
 
 	serverErr := make(chan error, 1)
 	go func() {
-		log.Printf("server listening on %s", addr)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Info("server listening", "addr", addr, "mode", certs.mode())
+		if err := certs.serve(server); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			serverErr <- err
 		}
 		close(serverErr)
@@ -51,23 +94,49 @@ func main() {
 	select {
 	case err, ok := <-serverErr:
 		if ok && err != nil {
-			log.Fatalf("server stopped: %v", err)
+			fatal("server stopped", err)
 		}
 		return
 	case <-stopCtx.Done():
-		log.Printf("shutdown signal received")
+		slog.Info("shutdown signal received")
 	}
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), serverTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("graceful shutdown failed: %v", err)
+		fatal("graceful shutdown failed", err)
 	}
 
 	if err, ok := <-serverErr; ok && err != nil {
-		log.Fatalf("server stopped: %v", err)
+		fatal("server stopped", err)
+	}
+
+	slog.Info("server stopped")
+}
+
+// prepareTableCache loads (or builds and persists) the packing table for the
+// default pack sizes so the first optimizations skip rebuilding it.
+func prepareTableCache(dir string) {
+	ceiling := defaultTableCacheCeiling
+	if raw := os.Getenv("TABLE_CACHE_CEILING"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			fatal("invalid TABLE_CACHE_CEILING", err, "value", raw)
+		}
+		ceiling = parsed
 	}
 
-	log.Printf("server stopped")
+	loaded, err := service.PrepareTableCache(dir, ceiling)
+	if err != nil {
+		fatal("unable to prepare table cache", err)
+	}
+	slog.Info("table cache ready", "ceiling", ceiling, "loaded_from_disk", loaded)
+}
+
+// fatal logs err at error level and exits. Deferred calls do not run, as with
+// log.Fatal.
+func fatal(msg string, err error, args ...any) {
+	slog.Error(msg, append([]any{"error", err}, args...)...)
+	os.Exit(1)
 }
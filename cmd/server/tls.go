@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+)
+
+// tlsFiles holds the certificate and key the server terminates TLS with; the
+// zero value serves plain HTTP.
+type tlsFiles struct {
+	certFile string
+	keyFile  string
+}
+
+// resolveTLS reads the TLS_CERT_FILE (certFile) and TLS_KEY_FILE (keyFile)
+// settings. Both or neither must be set, so a half-configured deployment fails
+// at startup instead of silently serving plain HTTP.
+func resolveTLS(certFile, keyFile string) (tlsFiles, error) {
+	if (certFile == "") != (keyFile == "") {
+		return tlsFiles{}, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	return tlsFiles{certFile: certFile, keyFile: keyFile}, nil
+}
+
+func (f tlsFiles) enabled() bool {
+	return f.certFile != ""
+}
+
+// mode names the protocol served, for the startup log.
+func (f tlsFiles) mode() string {
+	if f.enabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// serve runs server over TLS when files are configured and plain HTTP
+// otherwise. Like ListenAndServe, it returns http.ErrServerClosed after
+// Shutdown.
+func (f tlsFiles) serve(server *http.Server) error {
+	if f.enabled() {
+		return server.ListenAndServeTLS(f.certFile, f.keyFile)
+	}
+	return server.ListenAndServe()
+}
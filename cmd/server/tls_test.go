@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResolveTLS(t *testing.T) {
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		wantMode string
+		wantErr  bool
+	}{
+		{name: "plain http", wantMode: "http"},
+		{name: "tls", certFile: "cert.pem", keyFile: "key.pem", wantMode: "https"},
+		{name: "cert only", certFile: "cert.pem", wantErr: true},
+		{name: "key only", keyFile: "key.pem", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			files, err := resolveTLS(tc.certFile, tc.keyFile)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("resolveTLS(%q, %q) returned no error", tc.certFile, tc.keyFile)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveTLS(%q, %q) returned error: %v", tc.certFile, tc.keyFile, err)
+			}
+			if got := files.mode(); got != tc.wantMode {
+				t.Fatalf("mode() = %q, want %q", got, tc.wantMode)
+			}
+		})
+	}
+}
+
+func TestServe_ShutsDownGracefully(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+
+	tests := []struct {
+		name  string
+		files tlsFiles
+	}{
+		{name: "http"},
+		{name: "https", files: tlsFiles{certFile: certFile, keyFile: keyFile}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			addr := freeAddr(t)
+			server := &http.Server{
+				Addr: addr,
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte("ok"))
+				}),
+				ReadHeaderTimeout: serverTimeout,
+			}
+
+			serveErr := make(chan error, 1)
+			go func() { serveErr <- tc.files.serve(server) }()
+
+			client := &http.Client{
+				Timeout:   time.Second,
+				Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+			}
+			url := tc.files.mode() + "://" + addr + "/"
+			var res *http.Response
+			var err error
+			for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+				if res, err = client.Get(url); err == nil {
+					break
+				}
+			}
+			if err != nil {
+				t.Fatalf("GET %s failed: %v", url, err)
+			}
+			_ = res.Body.Close()
+			if (res.TLS != nil) != tc.files.enabled() {
+				t.Fatalf("response TLS state = %v, want TLS %v", res.TLS != nil, tc.files.enabled())
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), serverTimeout)
+			defer cancel()
+			if err := server.Shutdown(ctx); err != nil {
+				t.Fatalf("Shutdown returned error: %v", err)
+			}
+			if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+				t.Fatalf("serve returned %v, want http.ErrServerClosed", err)
+			}
+		})
+	}
+}
+
+func TestServe_MissingCertificate(t *testing.T) {
+	dir := t.TempDir()
+	files := tlsFiles{certFile: filepath.Join(dir, "cert.pem"), keyFile: filepath.Join(dir, "key.pem")}
+	server := &http.Server{Addr: freeAddr(t), ReadHeaderTimeout: serverTimeout}
+
+	if err := files.serve(server); err == nil || errors.Is(err, http.ErrServerClosed) {
+		t.Fatalf("serve returned %v, want a certificate error", err)
+	}
+}
+
+// freeAddr returns a loopback address with a port nothing listens on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen returned error: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+	return addr
+}
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1 and its
+// key, returning their paths.
+func writeTestCertificate(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gymshark/internal/service"
+)
+
+// maxBatchOrders bounds the orders of one batch optimize request.
+const maxBatchOrders = 100
+
+type batchOrder struct {
+	ItemsOrdered int `json:"items_ordered"`
+}
+
+type batchRequest struct {
+	Orders []batchOrder `json:"orders"`
+	// Summary wraps the results in a batchResponse with their totals; the
+	// bare array stays the default for existing clients.
+	Summary bool `json:"summary"`
+}
+
+// batchResult is the plan of one batch order, or the error that order failed
+// with; a failed order never fails the rest of the batch.
+type batchResult struct {
+	*service.Plan
+	Error string `json:"error,omitempty"`
+}
+
+// batchSummary totals a batch: every order counts, but only planned orders
+// add items, packs and overfill.
+type batchSummary struct {
+	Orders        int `json:"orders"`
+	TotalItems    int `json:"total_items"`
+	TotalPacks    int `json:"total_packs"`
+	TotalOverfill int `json:"total_overfill"`
+	Errors        int `json:"errors"`
+}
+
+func (s *batchSummary) add(result batchResult) {
+	s.Orders++
+	if result.Plan == nil {
+		s.Errors++
+		return
+	}
+	s.TotalItems += result.TotalItems
+	s.TotalPacks += result.TotalPacks
+	s.TotalOverfill += result.Overfill
+}
+
+type batchResponse struct {
+	Results []batchResult `json:"results"`
+	Summary batchSummary  `json:"summary"`
+}
+
+func (h *handler) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req batchRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.Orders) == 0 {
+		writeError(w, http.StatusBadRequest, "orders must contain at least one order")
+		return
+	}
+	if len(req.Orders) > maxBatchOrders {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("orders must contain at most %d orders, got %d", maxBatchOrders, len(req.Orders)))
+		return
+	}
+
+	if service.CatalogReloading() {
+		w.Header().Set("Retry-After", catalogReloadRetryAfter)
+		writeErrorFor(w, http.StatusServiceUnavailable, service.ErrCatalogReloading)
+		return
+	}
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+	// One snapshot serves the whole batch, so a concurrent catalog update
+	// cannot split it across two catalogs.
+	packSizes := packSizeService.GetPackSizes()
+	limits, err := service.OrderLimitsFor(packSizes, 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to read the order limits")
+		return
+	}
+	// Batches never take the OptimizeLarge path.
+	limits.Large = 0
+
+	orders := make([]int, len(req.Orders))
+	rangeErrs := make([]error, len(req.Orders))
+	inRange := true
+	for i, order := range req.Orders {
+		orders[i] = order.ItemsOrdered
+		if rangeErrs[i] = h.config.orders.check("items_ordered", order.ItemsOrdered, limits); rangeErrs[i] != nil {
+			inRange = false
+		}
+	}
+	results := make([]batchResult, len(req.Orders))
+
+	// A valid batch is served from one shared table; when it fails, orders
+	// are retried one by one so each error lands on its own entry.
+	var summary batchSummary
+	if inRange {
+		plans, err := service.OptimizeBatchContext(r.Context(), orders, packSizes)
+		if err == nil {
+			for i := range plans {
+				service.LabelPacks(&plans[i])
+				results[i].Plan = &plans[i]
+				summary.add(results[i])
+			}
+			writeBatch(w, req.Summary, results, summary)
+			return
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			writeError(w, http.StatusServiceUnavailable, "optimization cancelled")
+			return
+		}
+	}
+
+	for i, order := range req.Orders {
+		if rangeErrs[i] != nil {
+			results[i].Error = rangeErrs[i].Error()
+			summary.add(results[i])
+			continue
+		}
+		plan, err := service.OptimizeContext(r.Context(), order.ItemsOrdered, packSizes)
+		switch {
+		case err == nil:
+			service.LabelPacks(&plan)
+			results[i].Plan = &plan
+		case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			writeError(w, http.StatusServiceUnavailable, "optimization cancelled")
+			return
+		case isValidationError(err):
+			results[i].Error = err.Error()
+		default:
+			results[i].Error = "unable to optimize pack breakdown"
+		}
+		summary.add(results[i])
+	}
+
+	writeBatch(w, req.Summary, results, summary)
+}
+
+func writeBatch(w http.ResponseWriter, withSummary bool, results []batchResult, summary batchSummary) {
+	if withSummary {
+		writeJSON(w, http.StatusOK, batchResponse{Results: results, Summary: summary})
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gymshark/internal/service"
+)
+
+func TestBatchEndpoint(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"orders":[{"items_ordered":251},{"items_ordered":0},{"items_ordered":12001}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize/batch", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+
+	var payload []struct {
+		ItemsOrdered int    `json:"items_ordered"`
+		TotalItems   int    `json:"total_items"`
+		TotalPacks   int    `json:"total_packs"`
+		Error        string `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload) != 3 {
+		t.Fatalf("got %d results, want 3", len(payload))
+	}
+	if payload[0].ItemsOrdered != 251 || payload[0].TotalItems != 500 || payload[0].Error != "" {
+		t.Fatalf("unexpected first result: %+v", payload[0])
+	}
+	if payload[1].Error == "" || payload[1].TotalItems != 0 {
+		t.Fatalf("expected an error on the invalid order, got %+v", payload[1])
+	}
+	if payload[2].TotalItems != 12250 || payload[2].TotalPacks != 4 || payload[2].Error != "" {
+		t.Fatalf("unexpected third result: %+v", payload[2])
+	}
+}
+
+func TestBatchEndpoint_OrderRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxOrder  string
+		wantRange string
+	}{
+		{name: "default range", wantRange: "items_ordered must be between 1 and 1995000"},
+		{name: "configured maximum", maxOrder: "10000", wantRange: "items_ordered must be between 1 and 10000"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("MAX_ORDER", tc.maxOrder)
+			srv := newTestHandler(t)
+
+			body := bytes.NewBufferString(`{"orders":[{"items_ordered":251},{"items_ordered":0},{"items_ordered":10000000},{"items_ordered":3000000000}]}`)
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/optimize/batch", body))
+
+			if res.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+			}
+			var payload []struct {
+				TotalItems int    `json:"total_items"`
+				Error      string `json:"error"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(payload) != 4 || payload[0].TotalItems != 500 || payload[0].Error != "" {
+				t.Fatalf("unexpected results: %+v", payload)
+			}
+			for i, result := range payload[1:] {
+				if result.Error != tc.wantRange {
+					t.Fatalf("results[%d].error = %q, want %q", i+1, result.Error, tc.wantRange)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchEndpoint_Summary(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want batchSummary
+	}{
+		{
+			name: "all planned",
+			body: `{"orders":[{"items_ordered":251},{"items_ordered":12001}],"summary":true}`,
+			want: batchSummary{Orders: 2, TotalItems: 12750, TotalPacks: 5, TotalOverfill: 498},
+		},
+		{
+			name: "mixed",
+			body: `{"orders":[{"items_ordered":251},{"items_ordered":0},{"items_ordered":12001},{"items_ordered":-5}],"summary":true}`,
+			want: batchSummary{Orders: 4, TotalItems: 12750, TotalPacks: 5, TotalOverfill: 498, Errors: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/batch", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+			}
+
+			var payload struct {
+				Results []struct {
+					TotalItems int    `json:"total_items"`
+					TotalPacks int    `json:"total_packs"`
+					Overfill   int    `json:"overfill"`
+					Error      string `json:"error"`
+				} `json:"results"`
+				Summary batchSummary `json:"summary"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.Summary != tc.want {
+				t.Fatalf("summary = %+v, want %+v", payload.Summary, tc.want)
+			}
+
+			totals := batchSummary{Orders: len(payload.Results)}
+			for _, result := range payload.Results {
+				if result.Error != "" {
+					totals.Errors++
+					continue
+				}
+				totals.TotalItems += result.TotalItems
+				totals.TotalPacks += result.TotalPacks
+				totals.TotalOverfill += result.Overfill
+			}
+			if payload.Summary != totals {
+				t.Fatalf("summary = %+v, per-line totals = %+v", payload.Summary, totals)
+			}
+		})
+	}
+}
+
+func TestBatchEndpoint_InvalidBatch(t *testing.T) {
+	tooMany := make([]string, maxBatchOrders+1)
+	for i := range tooMany {
+		tooMany[i] = `{"items_ordered":1}`
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "no orders", body: `{"orders":[]}`},
+		{name: "too many orders", body: fmt.Sprintf(`{"orders":[%s]}`, strings.Join(tooMany, ","))},
+		{name: "unknown field", body: `{"orders":[{"items":1}]}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/batch", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", res.Code, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestBatchEndpoint_CatalogReloading(t *testing.T) {
+	srv := newTestHandler(t)
+
+	end := service.BeginCatalogReload()
+	defer end()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize/batch", bytes.NewBufferString(`{"orders":[{"items_ordered":251}]}`))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", res.Code)
+	}
+}
+
+// TestBatchEndpoint_Cancelled cancels a batch while it runs: run with -race,
+// it checks the handler returns promptly with 503 and no partial results,
+// and leaves no goroutine behind.
+func TestBatchEndpoint_Cancelled(t *testing.T) {
+	// The zero order sends the batch down the one-by-one path, whose 100
+	// large tables take seconds to build without cancellation.
+	orders := []string{`{"items_ordered":0}`}
+	for i := range 99 {
+		orders = append(orders, fmt.Sprintf(`{"items_ordered":%d}`, 1_990_000-i))
+	}
+	body := `{"orders":[` + strings.Join(orders, ",") + `]}`
+
+	tests := []struct {
+		name        string
+		cancelAfter time.Duration
+	}{
+		{name: "before the batch starts"},
+		{name: "mid-batch", cancelAfter: 50 * time.Millisecond},
+	}
+
+	srv := newTestHandler(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := runtime.NumGoroutine()
+
+			ctx, cancel := context.WithCancel(t.Context())
+			var cancelled time.Time
+			timer := time.AfterFunc(tt.cancelAfter, func() {
+				cancelled = time.Now()
+				cancel()
+			})
+			defer timer.Stop()
+
+			req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/api/optimize/batch", bytes.NewBufferString(body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+			returned := time.Now()
+
+			if cancelled.IsZero() {
+				t.Fatal("batch finished before it was cancelled")
+			}
+			if elapsed := returned.Sub(cancelled); elapsed > 500*time.Millisecond {
+				t.Fatalf("batch returned %v after cancellation, want promptly", elapsed)
+			}
+			if res.Code != http.StatusServiceUnavailable {
+				t.Fatalf("status = %d, want 503: %s", res.Code, res.Body.String())
+			}
+			var payload map[string]json.RawMessage
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if _, ok := payload["error"]; !ok || len(payload) != 1 {
+				t.Fatalf("body = %s, want only an error and no partial results", res.Body.String())
+			}
+
+			// Give exiting goroutines a moment before calling them leaked.
+			deadline := time.Now().Add(time.Second)
+			for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if after := runtime.NumGoroutine(); after > before {
+				t.Fatalf("goroutines = %d after the batch, want at most %d", after, before)
+			}
+		})
+	}
+}
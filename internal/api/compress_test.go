@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gunzip(t *testing.T, body []byte) []byte {
+	t.Helper()
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("gzip.NewReader returned error: %v", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("decompress body: %v", err)
+	}
+	return decoded
+}
+
+func TestGzip_CompressesLargeBatchResponses(t *testing.T) {
+	srv := newTestHandler(t)
+
+	orders := make([]string, 50)
+	for i := range orders {
+		orders[i] = fmt.Sprintf(`{"items_ordered":%d}`, 251+i*1000)
+	}
+	body := `{"orders":[` + strings.Join(orders, ",") + `]}`
+
+	plain := httptest.NewRecorder()
+	srv.ServeHTTP(plain, httptest.NewRequest(http.MethodPost, "/api/optimize/batch", strings.NewReader(body)))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize/batch", strings.NewReader(body))
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+	if got := res.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := res.Header().Get("Vary"); !strings.Contains(got, "Accept-Encoding") {
+		t.Fatalf("Vary = %q, want Accept-Encoding", got)
+	}
+	if res.Body.Len() >= plain.Body.Len() {
+		t.Fatalf("compressed body is %d bytes, plain is %d", res.Body.Len(), plain.Body.Len())
+	}
+	if decoded := gunzip(t, res.Body.Bytes()); !bytes.Equal(decoded, plain.Body.Bytes()) {
+		t.Fatalf("decompressed body differs:\n%s\nwant:\n%s", decoded, plain.Body.String())
+	}
+}
+
+func TestGzip_OptimizeResponseRoundTrip(t *testing.T) {
+	srv := newTestHandler(t)
+
+	// Explaining 12001 lists enough gap totals to pass minGzipSize.
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", strings.NewReader(`{"items_ordered":12001,"explain":true,"fill_vs_order":true,"echo_input":true}`))
+	req.Header.Set("Accept-Encoding", "deflate, gzip;q=0.8")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+	if got := res.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+
+	var plan struct {
+		TotalItems int `json:"total_items"`
+		TotalPacks int `json:"total_packs"`
+	}
+	if err := json.Unmarshal(gunzip(t, res.Body.Bytes()), &plan); err != nil {
+		t.Fatalf("decode decompressed plan: %v", err)
+	}
+	if plan.TotalItems != 12250 || plan.TotalPacks != 4 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+}
+
+func TestGzip_SkipsSmallAndUnacceptedResponses(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+	}{
+		{name: "small body", acceptEncoding: "gzip"},
+		{name: "gzip refused", acceptEncoding: "gzip;q=0"},
+		{name: "other encodings", acceptEncoding: "br, deflate"},
+		{name: "no header"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", strings.NewReader(`{"items_ordered":251}`))
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", res.Code)
+			}
+			if got := res.Header().Get("Content-Encoding"); got != "" {
+				t.Fatalf("Content-Encoding = %q, want none", got)
+			}
+			if !strings.Contains(res.Body.String(), `"total_items":500`) {
+				t.Fatalf("unexpected body: %s", res.Body.String())
+			}
+		})
+	}
+}
+
+func TestGzip_DoesNotDoubleCompress(t *testing.T) {
+	// The handler encodes its own body, like /metrics or precompressed assets.
+	payload := strings.Repeat("already compressed ", 200)
+	var encoded bytes.Buffer
+	writer := gzip.NewWriter(&encoded)
+	_, _ = writer.Write([]byte(payload))
+	_ = writer.Close()
+
+	srv := withGzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(encoded.Bytes())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if got := string(gunzip(t, res.Body.Bytes())); got != payload {
+		t.Fatalf("expected one layer of gzip, got %q", got)
+	}
+}
+
+func TestGzip_FlushBeforeThresholdSendsPlainBody(t *testing.T) {
+	srv := withGzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("first line\n"))
+		_ = http.NewResponseController(w).Flush()
+		_, _ = w.Write([]byte(strings.Repeat("x", 2*minGzipSize)))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusAccepted || res.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("status %d, Content-Encoding %q; want 202 uncompressed", res.Code, res.Header().Get("Content-Encoding"))
+	}
+	if !strings.HasPrefix(res.Body.String(), "first line\n") || res.Body.Len() != len("first line\n")+2*minGzipSize {
+		t.Fatalf("unexpected body of %d bytes", res.Body.Len())
+	}
+}
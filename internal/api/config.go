@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gymshark/internal/service"
+)
+
+const defaultItemLabel = "items"
+
+// Values of NULL_PACK_SIZES.
+const (
+	nullPackSizesReject = "reject"
+	nullPackSizesKeep   = "keep"
+)
+
+// config holds handler settings read from the environment.
+type config struct {
+	// itemLabel names the shipped unit in human-readable formats (ITEM_LABEL).
+	itemLabel string
+	// adminToken is the bearer token admin endpoints require (ADMIN_TOKEN).
+	// Admin endpoints are disabled when it is empty.
+	adminToken string
+	// cors configures cross-origin access; it is off when no origin is allowed.
+	cors corsConfig
+	// orders is the business range of items_ordered (MIN_ORDER, MAX_ORDER).
+	orders orderRange
+	// keepCatalogOnNull makes PUT /api/pack-sizes with "pack_sizes":null
+	// keep the catalog instead of rejecting the update (NULL_PACK_SIZES=keep).
+	keepCatalogOnNull bool
+}
+
+// corsConfig holds the CORS settings (CORS_ALLOWED_ORIGINS, CORS_MAX_AGE and
+// CORS_ALLOW_CREDENTIALS).
+type corsConfig struct {
+	// allowedOrigins lists the origins allowed to call the API; "*" allows any.
+	allowedOrigins []string
+	// maxAge is how long, in seconds, browsers may cache a preflight response.
+	// Zero omits Access-Control-Max-Age.
+	maxAge int
+	// allowCredentials lets browsers send cookies and credentials. The
+	// matching origin is then echoed, as browsers require; loadConfig rejects
+	// it together with "*".
+	allowCredentials bool
+}
+
+// orderRange bounds items_ordered at the API boundary so clients get one
+// predictable 400 instead of the optimizer's separate errors. min defaults to
+// 1; a zero max means MAX_ORDER is unset, and the catalog's limits bound the
+// range either way.
+type orderRange struct {
+	min int
+	max int
+}
+
+// check returns the error for a value of the named field outside the range
+// the catalog's limits leave, if any. Orders above the int32 ceiling are only
+// served up to limits.Large.
+func (o orderRange) check(name string, value int, limits service.OrderLimits) error {
+	upper := func(limit int) int {
+		if o.max > 0 {
+			return min(o.max, limit)
+		}
+		return limit
+	}
+	exactMax, largeMax := upper(limits.Exact), upper(limits.Large)
+
+	if value >= o.min && (value <= exactMax || (value > math.MaxInt32 && value <= largeMax)) {
+		return nil
+	}
+	if largeMax > math.MaxInt32 {
+		return fmt.Errorf("%s must be between %d and %d, or between %d and %d", name, o.min, exactMax, int64(math.MaxInt32)+1, largeMax)
+	}
+	return fmt.Errorf("%s must be between %d and %d", name, o.min, exactMax)
+}
+
+// allowsOrigin reports whether origin may call the API from a browser.
+func (c corsConfig) allowsOrigin(origin string) bool {
+	return slices.Contains(c.allowedOrigins, "*") || slices.Contains(c.allowedOrigins, origin)
+}
+
+func loadConfig() (config, error) {
+	cfg := config{
+		itemLabel: defaultItemLabel,
+	}
+
+	if label := os.Getenv("ITEM_LABEL"); label != "" {
+		cfg.itemLabel = label
+	}
+	cfg.adminToken = os.Getenv("ADMIN_TOKEN")
+
+	if raw := os.Getenv("CORS_ALLOWED_ORIGINS"); raw != "" {
+		for _, origin := range strings.Split(raw, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				cfg.cors.allowedOrigins = append(cfg.cors.allowedOrigins, origin)
+			}
+		}
+	}
+	if raw := os.Getenv("CORS_MAX_AGE"); raw != "" {
+		maxAge, err := strconv.Atoi(raw)
+		if err != nil || maxAge < 0 {
+			return config{}, fmt.Errorf("invalid CORS_MAX_AGE %q: must be a non-negative number of seconds", raw)
+		}
+		cfg.cors.maxAge = maxAge
+	}
+	if raw := os.Getenv("CORS_ALLOW_CREDENTIALS"); raw != "" {
+		allow, err := strconv.ParseBool(raw)
+		if err != nil {
+			return config{}, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS %q: %w", raw, err)
+		}
+		cfg.cors.allowCredentials = allow
+	}
+	if cfg.cors.allowCredentials && slices.Contains(cfg.cors.allowedOrigins, "*") {
+		// Echoing any origin with credentials would let every site read
+		// responses on behalf of signed-in users.
+		return config{}, fmt.Errorf("CORS_ALLOW_CREDENTIALS requires an explicit CORS_ALLOWED_ORIGINS list, not %q", "*")
+	}
+	orders, err := loadOrderRange()
+	if err != nil {
+		return config{}, err
+	}
+	cfg.orders = orders
+	switch raw := os.Getenv("NULL_PACK_SIZES"); raw {
+	case "", nullPackSizesReject:
+	case nullPackSizesKeep:
+		cfg.keepCatalogOnNull = true
+	default:
+		return config{}, fmt.Errorf("invalid NULL_PACK_SIZES %q: must be %q or %q", raw, nullPackSizesReject, nullPackSizesKeep)
+	}
+
+	return cfg, nil
+}
+
+func loadOrderRange() (orderRange, error) {
+	orders := orderRange{min: 1}
+	if raw := os.Getenv("MIN_ORDER"); raw != "" {
+		minOrder, err := strconv.Atoi(raw)
+		if err != nil || minOrder <= 0 {
+			return orderRange{}, fmt.Errorf("invalid MIN_ORDER %q: must be a positive number of items", raw)
+		}
+		orders.min = minOrder
+	}
+	if raw := os.Getenv("MAX_ORDER"); raw != "" {
+		maxOrder, err := strconv.Atoi(raw)
+		if err != nil || maxOrder < orders.min {
+			return orderRange{}, fmt.Errorf("invalid MAX_ORDER %q: must be a number of items of at least %d", raw, orders.min)
+		}
+		orders.max = maxOrder
+	}
+	return orders, nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// isCSVUpload reports whether a pack sizes update carries a CSV body.
+func isCSVUpload(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "text/csv"
+}
+
+// csvDelimiter returns the ?delimiter= rune for CSV imports, a comma by
+// default. It must be a single rune that can separate CSV fields, e.g. ";"
+// or a tab ("%09") for European locales.
+func csvDelimiter(r *http.Request) (rune, error) {
+	raw := r.URL.Query().Get("delimiter")
+	if raw == "" {
+		return ',', nil
+	}
+
+	delimiter, size := utf8.DecodeRuneInString(raw)
+	if size != len(raw) || delimiter == utf8.RuneError || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+		return 0, fmt.Errorf("delimiter must be a single character other than a quote or line break, got %q", raw)
+	}
+	return delimiter, nil
+}
+
+// decodePackSizesCSV reads pack sizes from CSV, any number per row; blank
+// fields are skipped.
+func decodePackSizesCSV(body io.ReadCloser, delimiter rune) ([]int, error) {
+	defer body.Close()
+
+	reader := csv.NewReader(body)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	packSizes := []int{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return packSizes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		for _, field := range record {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			size, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CSV pack size %q", field)
+			}
+			packSizes = append(packSizes, size)
+		}
+	}
+}
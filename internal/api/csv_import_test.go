@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPackSizesEndpoint_CSVImport(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		body      string
+		status    int
+		wantSizes []int
+	}{
+		{name: "comma default", body: "250,500\n1000\n", status: http.StatusOK, wantSizes: []int{1000, 500, 250}},
+		{name: "semicolon", query: "?delimiter=%3B", body: "250;500;1000\n2000; 5000\n", status: http.StatusOK, wantSizes: []int{5000, 2000, 1000, 500, 250}},
+		{name: "tab", query: "?delimiter=%09", body: "250\t500\r\n750\r\n", status: http.StatusOK, wantSizes: []int{750, 500, 250}},
+		{name: "trailing delimiter", query: "?delimiter=%3B", body: "250;500;\n", status: http.StatusOK, wantSizes: []int{500, 250}},
+		{name: "wrong delimiter", query: "?delimiter=%3B", body: "250,500\n", status: http.StatusBadRequest},
+		{name: "multi-character delimiter", query: "?delimiter=%3B%3B", body: "250;;500\n", status: http.StatusBadRequest},
+		{name: "quote delimiter", query: `?delimiter="`, body: "250\n", status: http.StatusBadRequest},
+		{name: "invalid size", query: "?delimiter=%3B", body: "250;0\n", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/pack-sizes"+tc.query, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload packSizesResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(payload.PackSizes, tc.wantSizes) {
+				t.Fatalf("pack_sizes = %v, want %v", payload.PackSizes, tc.wantSizes)
+			}
+		})
+	}
+}
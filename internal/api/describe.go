@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gymshark/internal/service"
+)
+
+// planPhrases holds the wording of a plan description in one language.
+type planPhrases struct {
+	// itemLabel replaces the default item label; a configured ITEM_LABEL is
+	// used as is.
+	itemLabel string
+	pack      string
+	packs     string
+	and       string
+	exact     string // total, label, packs, order
+	overship  string // total, label, packs, order, overfill
+}
+
+const defaultLanguage = "en"
+
+var planLanguages = map[string]planPhrases{
+	"en": {
+		itemLabel: "items",
+		pack:      "%d pack of %d",
+		packs:     "%d packs of %d",
+		and:       "and",
+		exact:     "We'll ship %d %s (%s) to fulfill your order of %d exactly.",
+		overship:  "We'll ship %d %s (%s) to fulfill your order of %d, overshipping by %d to avoid smaller packs.",
+	},
+	"pt": {
+		itemLabel: "itens",
+		pack:      "%d pacote de %d",
+		packs:     "%d pacotes de %d",
+		and:       "e",
+		exact:     "Enviaremos %d %s (%s) para atender exatamente ao seu pedido de %d.",
+		overship:  "Enviaremos %d %s (%s) para atender ao seu pedido de %d, excedendo em %d para evitar pacotes menores.",
+	},
+	"es": {
+		itemLabel: "artículos",
+		pack:      "%d paquete de %d",
+		packs:     "%d paquetes de %d",
+		and:       "y",
+		exact:     "Enviaremos %d %s (%s) para cumplir exactamente con su pedido de %d.",
+		overship:  "Enviaremos %d %s (%s) para cumplir con su pedido de %d, excediendo en %d para evitar paquetes más pequeños.",
+	},
+}
+
+// negotiateLanguage returns the first language in Accept-Language that has
+// plan phrases, matching on the primary subtag (pt-BR uses pt), or English.
+func negotiateLanguage(r *http.Request) string {
+	for _, accepted := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(accepted), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := planLanguages[primary]; ok {
+			return primary
+		}
+	}
+	return defaultLanguage
+}
+
+// describePlan renders plan as one customer-facing sentence in lang.
+func describePlan(plan service.Plan, lang, label string) string {
+	phrases := planLanguages[lang]
+	if label == defaultItemLabel {
+		label = phrases.itemLabel
+	}
+
+	ordered := plan.ItemsOrdered
+	if plan.OriginalItemsOrdered > 0 {
+		ordered = plan.OriginalItemsOrdered
+	}
+
+	parts := make([]string, 0, len(plan.Packs))
+	for _, pack := range plan.Packs {
+		format := phrases.packs
+		if pack.Count == 1 {
+			format = phrases.pack
+		}
+		parts = append(parts, fmt.Sprintf(format, pack.Count, pack.Size))
+	}
+	packs := parts[len(parts)-1]
+	if len(parts) > 1 {
+		packs = strings.Join(parts[:len(parts)-1], ", ") + " " + phrases.and + " " + packs
+	}
+
+	if overfill := plan.TotalItems - ordered; overfill > 0 {
+		return fmt.Sprintf(phrases.overship, plan.TotalItems, label, packs, ordered, overfill)
+	}
+	return fmt.Sprintf(phrases.exact, plan.TotalItems, label, packs, ordered)
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gymshark/internal/service"
+)
+
+func TestDescribePlan(t *testing.T) {
+	tests := []struct {
+		name  string
+		plan  service.Plan
+		lang  string
+		label string
+		want  string
+	}{
+		{
+			name:  "overshipped single pack",
+			plan:  service.Plan{ItemsOrdered: 251, TotalItems: 500, TotalPacks: 1, Packs: []service.PackBreakdown{{Size: 500, Count: 1}}},
+			lang:  "en",
+			label: defaultItemLabel,
+			want:  "We'll ship 500 items (1 pack of 500) to fulfill your order of 251, overshipping by 249 to avoid smaller packs.",
+		},
+		{
+			name: "exact with several sizes",
+			plan: service.Plan{ItemsOrdered: 12250, TotalItems: 12250, TotalPacks: 4, Packs: []service.PackBreakdown{
+				{Size: 5000, Count: 2}, {Size: 2000, Count: 1}, {Size: 250, Count: 1},
+			}},
+			lang:  "en",
+			label: "bottles",
+			want:  "We'll ship 12250 bottles (2 packs of 5000, 1 pack of 2000 and 1 pack of 250) to fulfill your order of 12250 exactly.",
+		},
+		{
+			name:  "snapped order describes the original",
+			plan:  service.Plan{ItemsOrdered: 500, OriginalItemsOrdered: 251, TotalItems: 500, TotalPacks: 1, Packs: []service.PackBreakdown{{Size: 500, Count: 1}}},
+			lang:  "pt",
+			label: defaultItemLabel,
+			want:  "Enviaremos 500 itens (1 pacote de 500) para atender ao seu pedido de 251, excedendo em 249 para evitar pacotes menores.",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := describePlan(tc.plan, tc.lang, tc.label); got != tc.want {
+				t.Fatalf("describePlan() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: "en"},
+		{header: "pt-BR,pt;q=0.9,en;q=0.8", want: "pt"},
+		{header: "de-DE, es;q=0.5", want: "es"},
+		{header: "fr", want: "en"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.header, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", nil)
+			req.Header.Set("Accept-Language", tc.header)
+			if got := negotiateLanguage(req); got != tc.want {
+				t.Fatalf("negotiateLanguage(%q) = %q, want %q", tc.header, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_Describe(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251,"describe":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload struct {
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := "We'll ship 500 items (1 pack of 500) to fulfill your order of 251, overshipping by 249 to avoid smaller packs."
+	if payload.Description != want {
+		t.Fatalf("description = %q, want %q", payload.Description, want)
+	}
+}
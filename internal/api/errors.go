@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"gymshark/internal/service"
+)
+
+// Machine-readable error codes for the service errors clients most often
+// react to. Other errors are coded by their HTTP status (see statusCode).
+const (
+	codeInvalidItemsOrdered  = "INVALID_ITEMS_ORDERED"
+	codeInvalidPackSizes     = "INVALID_PACK_SIZES"
+	codePackSizeTooLarge     = "PACK_SIZE_TOO_LARGE"
+	codeTooManyPackSizes     = "TOO_MANY_PACK_SIZES"
+	codeOptimizationTooLarge = "OPTIMIZATION_TOO_LARGE"
+	codeCatalogReloading     = "CATALOG_RELOADING"
+)
+
+// errorCodes maps sentinel service errors to their codes, checked in order.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{service.ErrInvalidItemsOrdered, codeInvalidItemsOrdered},
+	{service.ErrPackSizeTooLarge, codePackSizeTooLarge},
+	{service.ErrTooManyPackSizes, codeTooManyPackSizes},
+	{service.ErrInvalidPackSizes, codeInvalidPackSizes},
+	{service.ErrOptimizationTooLarge, codeOptimizationTooLarge},
+	{service.ErrCatalogReloading, codeCatalogReloading},
+}
+
+// apiError is the body of every error response:
+// {"error":{"code":"INVALID_ITEMS_ORDERED","message":"..."}}. The message is
+// for display; clients switch on the code, which is stable.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Error apiError `json:"error"`
+	// Status is the plan status of an optimize request that has no plan,
+	// e.g. service.PlanStatusInfeasibleExact.
+	Status string `json:"status,omitempty"`
+}
+
+// errorCode returns the code of a sentinel service error wrapped in err, or
+// "" when there is none.
+func errorCode(err error) string {
+	for _, mapping := range errorCodes {
+		if errors.Is(err, mapping.err) {
+			return mapping.code
+		}
+	}
+	return ""
+}
+
+// statusCode derives a code from an HTTP status, e.g. METHOD_NOT_ALLOWED.
+func statusCode(status int) string {
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+func newAPIError(status int, err error) apiError {
+	code := errorCode(err)
+	if code == "" {
+		code = statusCode(status)
+	}
+	return apiError{Code: code, Message: err.Error()}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: apiError{Code: statusCode(status), Message: message}})
+}
+
+// writeErrorFor is writeError with the code taken from err when it wraps a
+// sentinel service error.
+func writeErrorFor(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: newAPIError(status, err)})
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gymshark/internal/service"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "invalid items ordered", err: service.ErrInvalidItemsOrdered, want: codeInvalidItemsOrdered},
+		{name: "wrapped", err: fmt.Errorf("orders[2]: %w", service.ErrInvalidItemsOrdered), want: codeInvalidItemsOrdered},
+		{name: "invalid pack sizes", err: service.ErrInvalidPackSizes, want: codeInvalidPackSizes},
+		{name: "pack size too large", err: service.ErrPackSizeTooLarge, want: codePackSizeTooLarge},
+		{name: "optimization too large", err: service.ErrOptimizationTooLarge, want: codeOptimizationTooLarge},
+		{name: "catalog reloading", err: service.ErrCatalogReloading, want: codeCatalogReloading},
+		{name: "unmapped", err: errors.New("unexpected EOF"), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(tt.err); got != tt.want {
+				t.Fatalf("errorCode(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	srv := newTestHandler(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		want       apiError
+	}{
+		{
+			name:       "sentinel error",
+			method:     http.MethodGet,
+			target:     "/api/optimize?items_ordered=0",
+			wantStatus: http.StatusBadRequest,
+			want:       apiError{Code: codeInvalidItemsOrdered, Message: service.ErrInvalidItemsOrdered.Error()},
+		},
+		{
+			name:       "coded by status",
+			method:     http.MethodPut,
+			target:     "/api/optimize",
+			wantStatus: http.StatusMethodNotAllowed,
+			want:       apiError{Code: "METHOD_NOT_ALLOWED", Message: "method not allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", res.Code, tt.wantStatus)
+			}
+			var payload errorResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.Error != tt.want {
+				t.Fatalf("error = %+v, want %+v", payload.Error, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// writeJSONWithETag writes data like writeJSON with a strong ETag derived from
+// the encoded body, so it changes whenever the body does. A GET or HEAD whose
+// If-None-Match lists that ETag gets 304 Not Modified without a body.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, data any) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(data); err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to encode response")
+		return
+	}
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:])[:32] + `"`
+
+	w.Header().Set("ETag", etag)
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body.Bytes())
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for: header is
+// "*" or a list of entity tags, each possibly prefixed with W/.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPackSizesEndpoint_ConditionalGet(t *testing.T) {
+	srv := newTestHandler(t)
+
+	get := func(query, ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/pack-sizes"+query, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, req)
+		return res
+	}
+
+	first := get("", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d, ETag = %q; want 200 with an ETag", first.Code, etag)
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		ifNoneMatch string
+		status      int
+	}{
+		{name: "matching", ifNoneMatch: etag, status: http.StatusNotModified},
+		{name: "weak match in a list", ifNoneMatch: `"other", W/` + etag, status: http.StatusNotModified},
+		{name: "wildcard", ifNoneMatch: "*", status: http.StatusNotModified},
+		{name: "stale", ifNoneMatch: `"stale"`, status: http.StatusOK},
+		{name: "other representation", query: "?limit=2", ifNoneMatch: etag, status: http.StatusOK},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res := get(tc.query, tc.ifNoneMatch)
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d", res.Code, tc.status)
+			}
+			if tc.status == http.StatusNotModified && res.Body.Len() != 0 {
+				t.Fatalf("expected an empty 304 body, got %q", res.Body.String())
+			}
+		})
+	}
+
+	// Any update changes the ETag, even one storing the same sizes: the
+	// version in the body moves.
+	req := httptest.NewRequest(http.MethodPut, "/api/pack-sizes", bytes.NewBufferString(`{"pack_sizes":[250,500,1000,2000,5000]}`))
+	req.Header.Set("If-None-Match", etag)
+	update := httptest.NewRecorder()
+	srv.ServeHTTP(update, req)
+	if update.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200", update.Code)
+	}
+
+	after := get("", etag)
+	if after.Code != http.StatusOK || after.Header().Get("ETag") == etag {
+		t.Fatalf("status = %d, ETag = %q; want 200 with a new ETag", after.Code, after.Header().Get("ETag"))
+	}
+	if after.Header().Get("ETag") != update.Header().Get("ETag") {
+		t.Fatalf("GET ETag %q differs from the PUT response's %q", after.Header().Get("ETag"), update.Header().Get("ETag"))
+	}
+}
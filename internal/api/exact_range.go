@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gymshark/internal/service"
+)
+
+// exactRangeFlushEvery is how many streamed totals are written between flushes.
+const exactRangeFlushEvery = 256
+
+type exactRangeResponse struct {
+	PackSizes []int `json:"pack_sizes"`
+	From      int   `json:"from"`
+	To        int   `json:"to"`
+	Totals    []int `json:"totals"`
+}
+
+type exactTotalLine struct {
+	Total int `json:"total"`
+}
+
+func (h *handler) handleExactRange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	from, err := queryInt(r, "from", 1)
+	if err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+	to, err := queryInt(r, "to", 0)
+	if err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+	packSizes := packSizeService.GetPackSizes()
+
+	if r.URL.Query().Get("stream") == "true" || r.Header.Get("Accept") == "application/x-ndjson" {
+		h.streamExactRange(w, r, from, to, packSizes)
+		return
+	}
+
+	totals, err := service.ExactTotals(from, to, packSizes)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to compute exact totals")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, exactRangeResponse{
+		PackSizes: packSizes,
+		From:      from,
+		To:        to,
+		Totals:    totals,
+	})
+}
+
+// streamExactRange writes each exact total as an NDJSON line as soon as the
+// DP pass finds it, flushing every exactRangeFlushEvery lines (http.Flusher). The response
+// starts with the first line, so invalid ranges still get a 400.
+func (h *handler) streamExactRange(w http.ResponseWriter, r *http.Request, from, to int, packSizes []int) {
+	// The controller reaches the Flusher through middleware wrappers.
+	controller := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	written := 0
+	err := service.StreamExactTotals(r.Context(), from, to, packSizes, func(total int) error {
+		if !started {
+			start()
+		}
+		if err := encoder.Encode(exactTotalLine{Total: total}); err != nil {
+			return err
+		}
+		written++
+		if written%exactRangeFlushEvery == 0 {
+			_ = controller.Flush()
+		}
+		return nil
+	})
+
+	switch {
+	case started:
+		// Once streaming, errors can only come from the client going away.
+	case err == nil:
+		start()
+	case isValidationError(err):
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	default:
+		writeError(w, http.StatusInternalServerError, "unable to compute exact totals")
+		return
+	}
+
+	_ = controller.Flush()
+}
+
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer, got %q", name, raw)
+	}
+	return value, nil
+}
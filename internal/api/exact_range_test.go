@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"gymshark/internal/service"
+)
+
+func TestExactRangeEndpoint_Batch(t *testing.T) {
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pack-sizes/exact-range?from=200&to=1000", nil)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+
+	var payload exactRangeResponse
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !reflect.DeepEqual(payload.Totals, []int{250, 500, 750, 1000}) {
+		t.Fatalf("totals = %v, want [250 500 750 1000]", payload.Totals)
+	}
+}
+
+func TestExactRangeEndpoint_StreamMatchesBatch(t *testing.T) {
+	srv := httptest.NewServer(newTestHandler(t))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/api/pack-sizes/exact-range?from=1&to=20000&stream=true")
+	if err != nil {
+		t.Fatalf("GET returned error: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.StatusCode)
+	}
+	if got := res.Header.Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("Content-Type = %q, want application/x-ndjson", got)
+	}
+
+	var streamed []int
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		var line exactTotalLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		streamed = append(streamed, line.Total)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read stream: %v", err)
+	}
+
+	batch, err := service.ExactTotals(1, 20000, testDefaultPackSizes)
+	if err != nil {
+		t.Fatalf("ExactTotals returned error: %v", err)
+	}
+	if !reflect.DeepEqual(streamed, batch) {
+		t.Fatalf("streamed %d totals, batch has %d", len(streamed), len(batch))
+	}
+}
+
+func TestExactRangeEndpoint_InvalidRange(t *testing.T) {
+	srv := newTestHandler(t)
+
+	for _, target := range []string{
+		"/api/pack-sizes/exact-range?from=10&to=5",
+		"/api/pack-sizes/exact-range?to=5000000&stream=true",
+		"/api/pack-sizes/exact-range?to=abc",
+	} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, req)
+
+		if res.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", target, res.Code)
+		}
+	}
+}
+
+// The bounds are pack totals, not orders: MIN_ORDER and MAX_ORDER never
+// apply, only the service's own limit does.
+func TestExactRangeEndpoint_IgnoresOrderRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+	}{
+		{name: "from below MIN_ORDER", target: "/api/pack-sizes/exact-range?from=1&to=500", wantStatus: http.StatusOK},
+		{name: "to above MAX_ORDER", target: "/api/pack-sizes/exact-range?from=250&to=5000&stream=true", wantStatus: http.StatusOK},
+		{name: "to above the exact-range limit", target: "/api/pack-sizes/exact-range?to=1000001", wantStatus: http.StatusBadRequest},
+		{name: "zero from", target: "/api/pack-sizes/exact-range?from=0&to=500", wantStatus: http.StatusBadRequest},
+	}
+
+	t.Setenv("MIN_ORDER", "100")
+	t.Setenv("MAX_ORDER", "1000")
+	srv := newTestHandler(t)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			if res.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.wantStatus, res.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gymshark/internal/service"
+)
+
+// planFields lists the top-level JSON field names of a plan, derived from the
+// struct tags so new fields are selectable without touching this file.
+var planFields = jsonFieldNames(reflect.TypeFor[service.Plan]())
+
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseFields reads the comma-separated ?fields= allowlist. It returns nil
+// when the parameter is absent and an error naming the first unknown field.
+func parseFields(r *http.Request) ([]string, error) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(raw, ",")
+	for i, field := range fields {
+		fields[i] = strings.TrimSpace(field)
+		if !slices.Contains(planFields, fields[i]) {
+			return nil, fmt.Errorf("unknown field %q in fields", fields[i])
+		}
+	}
+	return fields, nil
+}
+
+// projectFields marshals v and keeps only the requested top-level fields.
+// Requested fields the value omits (omitempty) stay omitted.
+func projectFields(v any, fields []string) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			projected[field] = value
+		}
+	}
+	return projected, nil
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestOptimizeEndpoint_FieldsProjection(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":12001}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize?fields=total_items,total_packs", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+
+	var payload map[string]int
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := map[string]int{"total_items": 12250, "total_packs": 4}
+	if !reflect.DeepEqual(payload, want) {
+		t.Fatalf("payload = %v, want %v", payload, want)
+	}
+}
+
+func TestOptimizeEndpoint_FieldsRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{name: "unknown field", target: "/api/optimize?fields=total_items,price"},
+		{name: "non-JSON format", target: "/api/optimize?fields=total_items&format=csv"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewBufferString(`{"items_ordered":1}`))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", res.Code)
+			}
+		})
+	}
+}
+
+func TestJSONFieldNames_CoversPlanTags(t *testing.T) {
+	for _, name := range []string{"items_ordered", "total_items", "total_packs", "packs", "explanation"} {
+		if !slices.Contains(planFields, name) {
+			t.Fatalf("planFields = %v, missing %q", planFields, name)
+		}
+	}
+}
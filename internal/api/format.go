@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gymshark/internal/service"
+)
+
+const (
+	formatJSON   = "json"
+	formatText   = "text"
+	formatCSV    = "csv"
+	formatJSONLD = "jsonld"
+	// formatMap is JSON with packs as a size->count object. It has no media
+	// type of its own, so it is only selectable with ?format=map.
+	formatMap = "map"
+)
+
+// formatMediaTypes maps negotiable media types to response formats.
+var formatMediaTypes = map[string]string{
+	"application/json":    formatJSON,
+	"text/plain":          formatText,
+	"text/csv":            formatCSV,
+	"application/ld+json": formatJSONLD,
+}
+
+// negotiateFormat picks the optimize response format. An explicit ?format=
+// wins; otherwise the first recognized media type in Accept is used, and JSON
+// is the default.
+func negotiateFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		switch format {
+		case formatJSON, formatText, formatCSV, formatJSONLD, formatMap:
+			return format, nil
+		}
+		return "", fmt.Errorf("unsupported format %q", format)
+	}
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		if format, ok := formatMediaTypes[mediaType]; ok {
+			return format, nil
+		}
+	}
+
+	return formatJSON, nil
+}
+
+// downloadFilenames are the attachment names of the downloadable formats.
+var downloadFilenames = map[string]string{
+	formatCSV:  "plan.csv",
+	formatText: "plan.txt",
+}
+
+// negotiateDownload reads ?download=true, which serves a CSV or text plan as
+// an attachment; it returns the attachment's filename, or "" to display it.
+func negotiateDownload(r *http.Request, format string) (string, error) {
+	if !r.URL.Query().Has("download") {
+		return "", nil
+	}
+	download, err := strconv.ParseBool(r.URL.Query().Get("download"))
+	if err != nil {
+		return "", errors.New("download must be true or false")
+	}
+	if !download {
+		return "", nil
+	}
+	filename, ok := downloadFilenames[format]
+	if !ok {
+		return "", errors.New("download is only supported for the csv and text formats")
+	}
+	return filename, nil
+}
+
+// writePlan renders plan in the negotiated format. label names the unit of
+// items in human-readable formats; JSON field names never change.
+func writePlan(w http.ResponseWriter, format string, plan service.Plan, label string) {
+	switch format {
+	case formatText:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(renderPlanText(plan, label)))
+	case formatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_ = writePlanCSV(w, plan, label)
+	case formatJSONLD:
+		writePlanJSONLD(w, plan, label)
+	case formatMap:
+		writeJSON(w, http.StatusOK, newPlanMapView(plan))
+	default:
+		writeJSON(w, http.StatusOK, plan)
+	}
+}
+
+// planMapView is a plan whose top-level packs are keyed by size. JSON objects
+// are unordered, so clients must not rely on the key order.
+type planMapView struct {
+	service.Plan
+	// Packs shadows Plan.Packs in the encoded output.
+	Packs map[string]int `json:"packs"`
+}
+
+func newPlanMapView(plan service.Plan) planMapView {
+	packs := make(map[string]int, len(plan.Packs))
+	for _, pack := range plan.Packs {
+		packs[strconv.Itoa(pack.Size)] = pack.Count
+	}
+	return planMapView{Plan: plan, Packs: packs}
+}
+
+func renderPlanText(plan service.Plan, label string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Ordered: %d %s\n", plan.ItemsOrdered, label)
+	fmt.Fprintf(&b, "Total: %d %s in %d pack(s)\n", plan.TotalItems, label, plan.TotalPacks)
+	for _, pack := range plan.Packs {
+		fmt.Fprintf(&b, "%d x %d %s\n", pack.Count, pack.Size, label)
+	}
+	return b.String()
+}
+
+func writePlanCSV(w http.ResponseWriter, plan service.Plan, label string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"pack_size", "count", label}); err != nil {
+		return err
+	}
+	for _, pack := range plan.Packs {
+		if err := writer.Write([]string{
+			strconv.Itoa(pack.Size),
+			strconv.Itoa(pack.Count),
+			strconv.Itoa(pack.Size * pack.Count),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
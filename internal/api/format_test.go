@@ -0,0 +1,221 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gymshark/internal/service"
+)
+
+func TestOptimizeEndpoint_TextFormatUsesItemLabel(t *testing.T) {
+	t.Setenv("ITEM_LABEL", "bottles")
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize?format=text", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	if got := res.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+		t.Fatalf("Content-Type = %q, want text/plain", got)
+	}
+
+	want := "Ordered: 251 bottles\nTotal: 500 bottles in 1 pack(s)\n1 x 500 bottles\n"
+	if res.Body.String() != want {
+		t.Fatalf("body = %q, want %q", res.Body.String(), want)
+	}
+}
+
+func TestOptimizeEndpoint_CSVFormatFromAcceptHeader(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":501}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	req.Header.Set("Accept", "text/csv")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	want := "pack_size,count,items\n500,1,500\n250,1,250\n"
+	if res.Body.String() != want {
+		t.Fatalf("body = %q, want %q", res.Body.String(), want)
+	}
+}
+
+func TestOptimizeEndpoint_Download(t *testing.T) {
+	tests := []struct {
+		name            string
+		target          string
+		accept          string
+		wantStatus      int
+		wantDisposition string
+	}{
+		{name: "csv", target: "/api/optimize?format=csv&download=true", wantStatus: http.StatusOK, wantDisposition: `attachment; filename="plan.csv"`},
+		{name: "text", target: "/api/optimize?format=text&download=true", wantStatus: http.StatusOK, wantDisposition: `attachment; filename="plan.txt"`},
+		{name: "csv from Accept", target: "/api/optimize?download=1", accept: "text/csv", wantStatus: http.StatusOK, wantDisposition: `attachment; filename="plan.csv"`},
+		{name: "not requested", target: "/api/optimize?format=csv&download=false", wantStatus: http.StatusOK},
+		{name: "json", target: "/api/optimize?download=true", wantStatus: http.StatusBadRequest},
+		{name: "invalid value", target: "/api/optimize?format=csv&download=yes", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewBufferString(`{"items_ordered":501}`))
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.wantStatus, res.Body.String())
+			}
+			if got := res.Header().Get("Content-Disposition"); got != tc.wantDisposition {
+				t.Fatalf("Content-Disposition = %q, want %q", got, tc.wantDisposition)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_DefaultsToJSON(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	req.Header.Set("Accept", "*/*")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if got := res.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", got)
+	}
+}
+
+func TestOptimizeEndpoint_UnsupportedFormat(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize?format=xml", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+}
+
+func TestOptimizeEndpoint_JSONLDFormat(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":501}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	req.Header.Set("Accept", "application/ld+json")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	if got := res.Header().Get("Content-Type"); got != "application/ld+json" {
+		t.Fatalf("Content-Type = %q, want application/ld+json", got)
+	}
+
+	var doc struct {
+		Context     map[string]string `json:"@context"`
+		Type        string            `json:"@type"`
+		TotalItems  int               `json:"totalItems"`
+		TotalPacks  int               `json:"totalPacks"`
+		OrderedItem []struct {
+			Type          string `json:"@type"`
+			OrderQuantity int    `json:"orderQuantity"`
+			PackSize      int    `json:"packSize"`
+			OrderedItem   struct {
+				Type string `json:"@type"`
+				Name string `json:"name"`
+			} `json:"orderedItem"`
+		} `json:"orderedItem"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if doc.Context["@vocab"] != "https://schema.org/" || doc.Context["totalItems"] == "" {
+		t.Fatalf("@context = %v, want schema.org vocab with plan terms", doc.Context)
+	}
+	if doc.Type != "Order" || doc.TotalItems != 750 || doc.TotalPacks != 2 {
+		t.Fatalf("document = %+v, want Order of 750 items in 2 packs", doc)
+	}
+	if len(doc.OrderedItem) != 2 {
+		t.Fatalf("orderedItem = %+v, want 2 entries", doc.OrderedItem)
+	}
+	first := doc.OrderedItem[0]
+	if first.Type != "OrderItem" || first.PackSize != 500 || first.OrderQuantity != 1 ||
+		first.OrderedItem.Type != "Product" || first.OrderedItem.Name != "Pack of 500 items" {
+		t.Fatalf("first orderedItem = %+v", first)
+	}
+}
+
+func TestOptimizeEndpoint_MapFormatRoundTrips(t *testing.T) {
+	srv := newTestHandler(t)
+
+	counts := func(format string) map[int]int {
+		t.Helper()
+		body := bytes.NewBufferString(`{"items_ordered":12001}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/optimize"+format, body)
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, req)
+		if res.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", res.Code)
+		}
+
+		got := make(map[int]int)
+		if format == "" {
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			for _, pack := range payload.Packs {
+				got[pack.Size] = pack.Count
+			}
+			return got
+		}
+
+		var payload struct {
+			TotalItems int            `json:"total_items"`
+			Packs      map[string]int `json:"packs"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if payload.TotalItems != 12250 {
+			t.Fatalf("total_items = %d, want 12250", payload.TotalItems)
+		}
+		for size, count := range payload.Packs {
+			parsed, err := strconv.Atoi(size)
+			if err != nil {
+				t.Fatalf("pack key %q is not a size: %v", size, err)
+			}
+			got[parsed] = count
+		}
+		return got
+	}
+
+	array, mapped := counts(""), counts("?format=map")
+	if !reflect.DeepEqual(array, mapped) {
+		t.Fatalf("map counts = %v, array counts = %v", mapped, array)
+	}
+}
@@ -1,46 +1,288 @@
 package api
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"gymshark/internal/service"
 	"gymshark/internal/webassets"
 )
 
 type optimizeRequest struct {
-	ItemsOrdered int `json:"items_ordered"`
+	ItemsOrdered int    `json:"items_ordered"`
+	Explain      bool   `json:"explain"`
+	Usage        bool   `json:"usage"`
+	SnapToExact  bool   `json:"snap_to_exact"`
+	SortBy       string `json:"sort_by"`
+	Idempotent   bool   `json:"idempotent"`
+	Describe     bool   `json:"describe"`
+	Timestamp    bool   `json:"timestamp"`
+	NearestExact bool   `json:"nearest_exact"`
+	Savings      bool   `json:"savings"`
+	FillVsOrder  bool   `json:"fill_vs_order"`
+	EchoInput    bool   `json:"echo_input"`
+	Objective    string `json:"objective"`
+	// PreferExactWithin is a pointer so an explicit zero can be rejected.
+	PreferExactWithin   *int `json:"prefer_exact_within"`
+	MaxItemsPerShipment *int `json:"max_items_per_shipment"`
+	MaxTotal            *int `json:"max_total"`
+	MaxOverfill         *int `json:"max_overfill"`
+	MaxPacks            *int `json:"max_packs"`
+	PalletCapacity      *int `json:"pallet_capacity"`
+	SwitchPenalty       *int `json:"switch_penalty"`
+	DisplayUnit         *int `json:"display_unit"`
+	ItemWeight          *int `json:"item_weight"`
+	MaxShipmentWeight   *int `json:"max_shipment_weight"`
+	// Breakdown is a pointer so only an explicit false drops the packs.
+	Breakdown *bool `json:"breakdown"`
 }
 
 type packSizesPayload struct {
 	PackSizes []int `json:"pack_sizes"`
 }
 
+// packSizesUpdate is the body of PUT /api/pack-sizes, which tells a missing
+// pack_sizes from null and from an empty array.
+type packSizesUpdate struct {
+	PackSizes packSizesField `json:"pack_sizes"`
+}
+
+type packSizesField struct {
+	sizes   []int
+	present bool
+	null    bool
+}
+
+func (f *packSizesField) UnmarshalJSON(data []byte) error {
+	f.present = true
+	if string(data) == "null" {
+		f.null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.sizes)
+}
+
+// check rejects the three shapes that carry no pack sizes, each with its own
+// message.
+func (f packSizesField) check() error {
+	switch {
+	case !f.present:
+		return fmt.Errorf("%w: pack_sizes is missing", service.ErrInvalidPackSizes)
+	case f.null:
+		return fmt.Errorf("%w: pack_sizes is null", service.ErrInvalidPackSizes)
+	case len(f.sizes) == 0:
+		return fmt.Errorf("%w: pack_sizes is empty", service.ErrInvalidPackSizes)
+	}
+	return nil
+}
+
+// packSizesPatch is the body of PATCH /api/pack-sizes.
+type packSizesPatch struct {
+	Add []int `json:"add"`
+}
+
+type packSizesResponse struct {
+	PackSizes []int  `json:"pack_sizes"`
+	Version   uint64 `json:"version"`
+	// MaxPackSizes is the most distinct sizes an update may hold.
+	MaxPackSizes int `json:"max_pack_sizes"`
+	// The paging fields are only set when the request asked for a page.
+	Total  *int `json:"total,omitempty"`
+	Limit  *int `json:"limit,omitempty"`
+	Offset *int `json:"offset,omitempty"`
+	// Detail is only set with ?detail=true.
+	Detail *service.CatalogDetail `json:"detail,omitempty"`
+}
+
+// maxPackSizesPageLimit caps the limit of a paginated pack size listing.
+const maxPackSizesPageLimit = 500
+
+type tieredCostRequest struct {
+	ItemsOrdered int                   `json:"items_ordered"`
+	Pricing      []service.PackPricing `json:"pricing"`
+}
+
+type alternativesRequest struct {
+	ItemsOrdered int   `json:"items_ordered"`
+	N            int   `json:"n"`
+	PackSizes    []int `json:"pack_sizes"`
+}
+
+type alternativesResponse struct {
+	Plans []service.Plan `json:"plans"`
+}
+
+type costRequest struct {
+	ItemsOrdered int                    `json:"items_ordered"`
+	Packs        []service.PackWithCost `json:"packs"`
+}
+
+type costResponse struct {
+	Plan      service.Plan `json:"plan"`
+	TotalCost float64      `json:"total_cost"`
+}
+
+type inventoryRequest struct {
+	ItemsOrdered int         `json:"items_ordered"`
+	Inventory    map[int]int `json:"inventory"`
+}
+
+type matrixRequest struct {
+	Orders   []int   `json:"orders"`
+	Catalogs [][]int `json:"catalogs"`
+}
+
+type twoTierRequest struct {
+	ItemsOrdered int   `json:"items_ordered"`
+	PrimarySizes []int `json:"primary_sizes"`
+	FillerSizes  []int `json:"filler_sizes"`
+}
+
+type cartRequest struct {
+	Lines []service.CartLine `json:"lines"`
+}
+
+type forecastRequest struct {
+	Periods []service.ForecastPeriod `json:"periods"`
+}
+
+// catalogValidationError is the 400 body of a rejected catalog update: the
+// usual error message plus every violation, for form validation.
+type catalogValidationError struct {
+	Error apiError `json:"error"`
+	service.CatalogValidation
+}
+
+type migrationRequest struct {
+	ItemsOrdered int   `json:"items_ordered"`
+	OldPackSizes []int `json:"old_pack_sizes"`
+	NewPackSizes []int `json:"new_pack_sizes"`
+}
+
+type pruneSuggestRequest struct {
+	Orders []int `json:"orders"`
+}
+
+type minimalExactRequest struct {
+	Targets []int `json:"targets"`
+	MinSize int   `json:"min_size"`
+}
+
+type coverageRequest struct {
+	MaxOrder    int   `json:"max_order"`
+	MaxOverfill int   `json:"max_overfill"`
+	PackSizes   []int `json:"pack_sizes"`
+}
+
+type lintRequest struct {
+	PackSizes []int `json:"pack_sizes"`
+	SampleMax int   `json:"sample_max"`
+}
+
+type suggestExactRequest struct {
+	ItemsOrdered int   `json:"items_ordered"`
+	PackSizes    []int `json:"pack_sizes"`
+}
+
+// maxTableEntriesHeader lets admins raise the DP table limit of one optimize
+// request, up to the service's hard ceiling.
+const maxTableEntriesHeader = "X-Max-Table-Entries"
+
+// catalogReloadRetryAfter is the Retry-After, in seconds, of optimize
+// requests rejected during a catalog reload; reload windows are brief.
+const catalogReloadRetryAfter = "1"
+
 type handler struct {
-	static http.Handler
+	static      http.Handler
+	config      config
+	maintenance atomic.Pointer[maintenanceMode]
+	// ready reports why the service cannot serve requests yet, or nil.
+	ready func() error
 }
 
+// NewHandler returns the API handler, with Go runtime and process metrics
+// alongside its own on /metrics.
 func NewHandler() (http.Handler, error) {
+	return NewHandlerWithRegistry(newProcessRegistry())
+}
+
+// NewHandlerWithRegistry is NewHandler with the handler's metrics registered
+// on, and /metrics served from, registry.
+func NewHandlerWithRegistry(registry *prometheus.Registry) (http.Handler, error) {
 	staticFiles, err := fs.Sub(webassets.FS, "static")
 	if err != nil {
 		return nil, err
 	}
 
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := newMetrics(registry)
+	if err != nil {
+		return nil, err
+	}
+
 	h := &handler{
 		static: http.FileServer(http.FS(staticFiles)),
+		config: cfg,
+		ready:  packSizeServiceReady,
 	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/health", h.handleHealth)
+	mux.HandleFunc("/api/health/live", h.handleHealth)
+	mux.HandleFunc("/api/health/ready", h.handleReady)
+	mux.Handle("/metrics", metricsHandler(registry))
 	mux.HandleFunc("/api/pack-sizes", h.handlePackSizes)
+	mux.HandleFunc("/api/pack-sizes/{size}", h.handlePackSize)
+	mux.HandleFunc("/api/pack-sizes/suggest-exact", h.handleSuggestExact)
+	mux.HandleFunc("/api/pack-sizes/prune-suggest", h.handlePruneSuggest)
+	mux.HandleFunc("/api/pack-sizes/minimal-exact", h.handleMinimalExact)
+	mux.HandleFunc("/api/pack-sizes/usage", h.handlePackUsage)
+	mux.HandleFunc("/api/pack-sizes/skus", h.handlePackSKUs)
+	mux.HandleFunc("/api/pack-sizes/exact-range", h.handleExactRange)
+	mux.HandleFunc("/api/pack-sizes/coverage", h.handleCoverage)
+	mux.HandleFunc("/api/pack-sizes/lint", h.handleLint)
+	mux.HandleFunc("/api/pack-sizes/subscribe", h.handleSubscribe)
+	mux.HandleFunc("/api/pack-sizes/profiles", h.handleProfiles)
+	mux.HandleFunc("/api/pack-sizes/profiles/{name}", h.handleProfile)
+	mux.HandleFunc("/api/pack-sizes/profiles/{name}/activate", h.handleActivateProfile)
 	mux.HandleFunc("/api/optimize", h.handleOptimize)
+	mux.HandleFunc("/api/optimize/batch", h.handleBatch)
+	mux.HandleFunc("/api/optimize/migration", h.handleMigration)
+	mux.HandleFunc("/api/optimize/forecast", h.handleForecast)
+	mux.HandleFunc("/api/optimize/simulate", h.handleSimulate)
+	mux.HandleFunc("/api/optimize/cart", h.handleCart)
+	mux.HandleFunc("/api/optimize/tiered-cost", h.handleTieredCost)
+	mux.HandleFunc("/api/optimize/cost", h.handleCost)
+	mux.HandleFunc("/api/optimize/inventory", h.handleInventory)
+	mux.HandleFunc("/api/optimize/two-tier", h.handleTwoTier)
+	mux.HandleFunc("/api/optimize/alternatives", h.handleAlternatives)
+	mux.HandleFunc("/api/optimize/matrix", h.handleMatrix)
+	mux.HandleFunc("/api/admin/flush-cache", requireAdmin(cfg.adminToken, h.handleFlushCache))
+	mux.HandleFunc("/api/admin/maintenance", requireAdmin(cfg.adminToken, h.handleMaintenance))
 	mux.HandleFunc("/", h.handleStatic)
-	return mux, nil
+	return withTracing(withRequestLogging(withGzip(withCORS(cfg.cors, withMetrics(m, withMaintenance(&h.maintenance, mux)))))), nil
 }
 
+// handleHealth is the liveness probe, served on /api/health/live and its
+// /api/health alias: it answers ok whenever the process is up.
 func (h *handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -50,33 +292,340 @@ func (h *handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// handleReady is the readiness probe: ok once the pack size service
+// initialized, 503 with the reason otherwise.
+func (h *handler) handleReady(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if err := h.ready(); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "reason": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// packSizeServiceReady reports the pack size service's initialization error,
+// initializing it on first use.
+func packSizeServiceReady() error {
+	_, err := service.GetPackSizeService()
+	return err
+}
+
 func (h *handler) handleOptimize(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
+	format, err := negotiateFormat(r)
+	if err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+	fields, err := parseFields(r)
+	if err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+	if fields != nil && format != formatJSON {
+		writeError(w, http.StatusBadRequest, "fields is only supported for JSON responses")
+		return
+	}
+	filename, err := negotiateDownload(r, format)
+	if err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
 	var req optimizeRequest
-	if err := decodeJSON(r.Body, &req); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+	if r.Method == http.MethodGet {
+		req, err = optimizeQuery(r)
+	} else {
+		err = decodeJSON(r.Body, &req)
+	}
+	if err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+	opts, err := req.options()
+	if err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
 		return
 	}
+	if opts.SkipBreakdown && format != formatJSON {
+		writeError(w, http.StatusBadRequest, "breakdown=false is only supported for JSON responses")
+		return
+	}
+	if value := r.Header.Get(maxTableEntriesHeader); value != "" {
+		if !authorizeAdmin(w, r, h.config.adminToken) {
+			return
+		}
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, maxTableEntriesHeader+" must be an integer")
+			return
+		}
+		opts.MaxTableEntries = limit
+	}
+	limits, ok := h.orderLimits(w, opts.MaxTableEntries)
+	if !ok {
+		return
+	}
+	if err := h.config.orders.check("items_ordered", req.ItemsOrdered, limits); err != nil {
+		writeOrderRangeError(w, err)
+		return
+	}
+
+	span := trace.SpanFromContext(r.Context())
+	span.SetAttributes(attribute.Int("items_ordered", req.ItemsOrdered))
+
+	optimize := service.OptimizeWithOptions
+	if req.Idempotent {
+		optimize = service.OptimizeIdempotent
+	}
+
+	// Orders beyond the int32 ceiling go through OptimizeLarge, which has no
+	// options; the configured catalog still applies.
+	if req.ItemsOrdered > math.MaxInt32 {
+		if opts != (service.Options{}) || req.Idempotent {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("options are not supported for items_ordered above %d", math.MaxInt32))
+			return
+		}
+		optimize = optimizeLarge
+	}
 
-	plan, err := service.Optimize(req.ItemsOrdered)
+	plan, err := optimize(r.Context(), req.ItemsOrdered, opts)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidItemsOrdered) || errors.Is(err, service.ErrInvalidPackSizes) || errors.Is(err, service.ErrOptimizationTooLarge) {
-			writeError(w, http.StatusBadRequest, err.Error())
+		if errors.Is(err, service.ErrCatalogReloading) {
+			w.Header().Set("Retry-After", catalogReloadRetryAfter)
+			writeErrorFor(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			// The client is usually gone; the status is for logs and traces.
+			writeError(w, http.StatusServiceUnavailable, "optimization cancelled")
+			return
+		}
+		if isUnsatisfiableOrder(err) {
+			writeErrorFor(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if errors.Is(err, service.ErrOverfillExceeded) {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				Error:  newAPIError(http.StatusBadRequest, err),
+				Status: service.PlanStatusInfeasibleExact,
+			})
+			return
+		}
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
 			return
 		}
 		writeError(w, http.StatusInternalServerError, "unable to optimize pack breakdown")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, plan)
+	if req.Describe {
+		plan.Description = describePlan(plan, negotiateLanguage(r), h.config.itemLabel)
+	}
+
+	span.SetAttributes(attribute.Int("total_packs", plan.TotalPacks))
+	if fields != nil {
+		projected, err := projectFields(plan, fields)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "unable to encode plan")
+			return
+		}
+		writeJSON(w, http.StatusOK, projected)
+		return
+	}
+	if filename != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	writePlan(w, format, plan, h.config.itemLabel)
+}
+
+// optimizeQuery reads the GET form of an optimize request, which only takes
+// items_ordered, for browsers and quick debugging.
+func optimizeQuery(r *http.Request) (optimizeRequest, error) {
+	if !r.URL.Query().Has("items_ordered") {
+		return optimizeRequest{}, errors.New("items_ordered is required")
+	}
+	itemsOrdered, err := queryInt(r, "items_ordered", 0)
+	if err != nil {
+		return optimizeRequest{}, err
+	}
+	if itemsOrdered <= 0 {
+		return optimizeRequest{}, service.ErrInvalidItemsOrdered
+	}
+	return optimizeRequest{ItemsOrdered: itemsOrdered}, nil
+}
+
+// orderLimits returns the limits of the configured catalog for a table of up
+// to tableLimit entries (zero for the default), answering the request when
+// they cannot be computed.
+func (h *handler) orderLimits(w http.ResponseWriter, tableLimit int) (service.OrderLimits, bool) {
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return service.OrderLimits{}, false
+	}
+	limits, err := service.OrderLimitsFor(packSizeService.GetPackSizes(), tableLimit)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return service.OrderLimits{}, false
+		}
+		writeError(w, http.StatusInternalServerError, "unable to read the order limits")
+		return service.OrderLimits{}, false
+	}
+	return limits, true
+}
+
+// writeOrderRangeError answers 400 for an order outside orderRange.
+func writeOrderRangeError(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusBadRequest, errorResponse{Error: apiError{Code: codeInvalidItemsOrdered, Message: err.Error()}})
+}
+
+// optimizeLarge serves an order beyond the int32 ceiling from the configured
+// catalog; opts is always empty here.
+func optimizeLarge(_ context.Context, itemsOrdered int, _ service.Options) (service.Plan, error) {
+	if service.CatalogReloading() {
+		return service.Plan{}, service.ErrCatalogReloading
+	}
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		return service.Plan{}, err
+	}
+	return service.OptimizeLarge(int64(itemsOrdered), packSizeService.GetPackSizes())
+}
+
+// options validates the optional request fields and maps them to service options.
+func (req optimizeRequest) options() (service.Options, error) {
+	opts := service.Options{
+		Explain:      req.Explain,
+		Usage:        req.Usage,
+		SnapToExact:  req.SnapToExact,
+		Timestamp:    req.Timestamp,
+		NearestExact: req.NearestExact,
+		Savings:      req.Savings,
+		FillVsOrder:  req.FillVsOrder,
+		EchoInput:    req.EchoInput,
+		Objective:    req.Objective,
+	}
+
+	if req.PreferExactWithin != nil {
+		if *req.PreferExactWithin <= 0 {
+			return service.Options{}, errors.New("prefer_exact_within must be greater than zero")
+		}
+		opts.PreferExactWithin = *req.PreferExactWithin
+	}
+	if req.MaxItemsPerShipment != nil {
+		if *req.MaxItemsPerShipment <= 0 {
+			return service.Options{}, errors.New("max_items_per_shipment must be greater than zero")
+		}
+		opts.MaxItemsPerShipment = *req.MaxItemsPerShipment
+	}
+	if req.ItemWeight != nil || req.MaxShipmentWeight != nil {
+		if req.ItemWeight == nil || req.MaxShipmentWeight == nil {
+			return service.Options{}, errors.New("item_weight and max_shipment_weight must be set together")
+		}
+		if *req.ItemWeight <= 0 || *req.MaxShipmentWeight <= 0 {
+			return service.Options{}, errors.New("item_weight and max_shipment_weight must be greater than zero")
+		}
+		opts.ItemWeight = *req.ItemWeight
+		opts.MaxShipmentWeight = *req.MaxShipmentWeight
+	}
+	if req.MaxTotal != nil {
+		if *req.MaxTotal < req.ItemsOrdered {
+			return service.Options{}, errors.New("max_total must be at least items_ordered")
+		}
+		opts.MaxTotal = *req.MaxTotal
+	}
+	if req.MaxPacks != nil {
+		if *req.MaxPacks <= 0 {
+			return service.Options{}, errors.New("max_packs must be greater than zero")
+		}
+		opts.MaxPacks = *req.MaxPacks
+	}
+	if req.MaxOverfill != nil {
+		if *req.MaxOverfill < 0 {
+			return service.Options{}, errors.New("max_overfill must not be negative")
+		}
+		opts.LimitOverfill = true
+		opts.MaxOverfill = *req.MaxOverfill
+	}
+	if req.PalletCapacity != nil {
+		if *req.PalletCapacity <= 0 {
+			return service.Options{}, errors.New("pallet_capacity must be greater than zero")
+		}
+		opts.PalletCapacity = *req.PalletCapacity
+	}
+	if req.DisplayUnit != nil {
+		if *req.DisplayUnit <= 0 {
+			return service.Options{}, errors.New("display_unit must be greater than zero")
+		}
+		opts.DisplayUnit = *req.DisplayUnit
+	}
+	if req.SwitchPenalty != nil {
+		if *req.SwitchPenalty <= 0 {
+			return service.Options{}, errors.New("switch_penalty must be greater than zero")
+		}
+		if opts.MaxItemsPerShipment > 0 || opts.MaxShipmentWeight > 0 {
+			// Split shipments are each planned for the fewest packs.
+			return service.Options{}, errors.New("switch_penalty cannot be combined with max_items_per_shipment or max_shipment_weight")
+		}
+		opts.SwitchPenalty = *req.SwitchPenalty
+	}
+
+	if req.Breakdown != nil && !*req.Breakdown {
+		if req.Describe {
+			return service.Options{}, errors.New("breakdown=false cannot be combined with describe")
+		}
+		opts.SkipBreakdown = true
+	}
+
+	switch req.SortBy {
+	case "", "size":
+	case "count":
+		opts.SortByCount = true
+	default:
+		return service.Options{}, fmt.Errorf("sort_by must be \"size\" or \"count\", got %q", req.SortBy)
+	}
+
+	return opts, nil
+}
+
+func (h *handler) handleMigration(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req migrationRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	migration, err := service.CompareCatalogMigration(req.ItemsOrdered, req.OldPackSizes, req.NewPackSizes)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to compare catalogs")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, migration)
 }
 
 func (h *handler) handlePackSizes(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut && r.Method != http.MethodPatch {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
@@ -87,58 +636,701 @@ func (h *handler) handlePackSizes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Method == http.MethodPatch {
+		patchCatalog(w, r, packSizeService)
+		return
+	}
+
 	if r.Method == http.MethodGet {
-		writeJSON(w, http.StatusOK, packSizesPayload{
-			PackSizes: packSizeService.GetPackSizes(),
-		})
+		paged := r.URL.Query().Has("limit") || r.URL.Query().Has("offset")
+		if r.URL.Query().Has("detail") {
+			detail, err := strconv.ParseBool(r.URL.Query().Get("detail"))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "detail must be true or false")
+				return
+			}
+			if detail && paged {
+				writeError(w, http.StatusBadRequest, "detail is not supported with limit or offset")
+				return
+			}
+			if detail {
+				writeCatalogDetail(w, r, packSizeService)
+				return
+			}
+		}
+		if paged {
+			writeCatalogPage(w, r, packSizeService)
+			return
+		}
+		writeCatalog(w, r, packSizeService)
 		return
 	}
 
-	var req packSizesPayload
-	if err := decodeJSON(r.Body, &req); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+	var req packSizesUpdate
+	if isCSVUpload(r) {
+		delimiter, err := csvDelimiter(r)
+		if err != nil {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		if req.PackSizes.sizes, err = decodePackSizesCSV(r.Body, delimiter); err != nil {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		req.PackSizes.present = true
+	} else if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := packSizeService.SetPackSizes(req.PackSizes); err != nil {
-		if errors.Is(err, service.ErrInvalidPackSizes) {
-			writeError(w, http.StatusBadRequest, err.Error())
+	if req.PackSizes.null && h.config.keepCatalogOnNull {
+		writeCatalog(w, r, packSizeService)
+		return
+	}
+	err = req.PackSizes.check()
+	if err == nil {
+		err = packSizeService.SetPackSizes(req.PackSizes.sizes)
+	}
+	if err != nil {
+		if isValidationError(err) {
+			writeJSON(w, http.StatusBadRequest, catalogValidationError{
+				Error:             newAPIError(http.StatusBadRequest, err),
+				CatalogValidation: service.ValidatePackSizes(req.PackSizes.sizes),
+			})
 			return
 		}
 		writeError(w, http.StatusInternalServerError, "unable to update pack sizes")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, packSizesPayload{
-		PackSizes: packSizeService.GetPackSizes(),
-	})
+	writeCatalog(w, r, packSizeService)
 }
 
-func (h *handler) handleStatic(w http.ResponseWriter, r *http.Request) {
-	h.static.ServeHTTP(w, r)
+// patchCatalog adds the sizes of a PATCH body to the catalog; sizes already
+// configured are ignored.
+func patchCatalog(w http.ResponseWriter, r *http.Request, packSizeService service.PackSizeService) {
+	var req packSizesPatch
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := packSizeService.AddPackSizes(req.Add); err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to update pack sizes")
+		return
+	}
+
+	writeCatalog(w, r, packSizeService)
 }
 
-func decodeJSON(body io.ReadCloser, dst any) error {
-	defer body.Close()
+// handlePackSize serves a single pack size; only DELETE is supported, which
+// retires the size and returns the updated catalog.
+func (h *handler) handlePackSize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
 
-	decoder := json.NewDecoder(body)
-	decoder.DisallowUnknownFields()
-	if err := decoder.Decode(dst); err != nil {
-		return err
+	size, err := strconv.Atoi(r.PathValue("size"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("pack size must be an integer, got %q", r.PathValue("size")))
+		return
 	}
-	if err := decoder.Decode(&struct{}{}); err != io.EOF {
-		return errors.New("request body must contain one JSON object")
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
 	}
 
-	return nil
-}
+	if err := packSizeService.RemovePackSize(size); err != nil {
+		if errors.Is(err, service.ErrPackSizeNotFound) {
+			writeErrorFor(w, http.StatusNotFound, err)
+			return
+		}
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to update pack sizes")
+		return
+	}
 
-func writeJSON(w http.ResponseWriter, status int, data any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(data)
+	writeCatalog(w, r, packSizeService)
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"error": message})
+// writeCatalog writes the catalog with an ETag, so pollers can revalidate it
+// with If-None-Match (see writeJSONWithETag).
+func writeCatalog(w http.ResponseWriter, r *http.Request, packSizeService service.PackSizeService) {
+	packSizes, version := packSizeService.GetCatalog()
+	writeJSONWithETag(w, r, packSizesResponse{
+		PackSizes:    packSizes,
+		Version:      version,
+		MaxPackSizes: service.MaxPackSizes(),
+	})
+}
+
+// writeCatalogDetail writes the catalog with each size's primary order range
+// (see service.DescribePackSizes), with an ETag like writeCatalog.
+func writeCatalogDetail(w http.ResponseWriter, r *http.Request, packSizeService service.PackSizeService) {
+	packSizes, version := packSizeService.GetCatalog()
+	detail, err := service.DescribePackSizes(packSizes)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to describe pack sizes")
+		return
+	}
+	writeJSONWithETag(w, r, packSizesResponse{
+		PackSizes:    packSizes,
+		Version:      version,
+		MaxPackSizes: service.MaxPackSizes(),
+		Detail:       &detail,
+	})
+}
+
+// writeCatalogPage writes one page of the pack sizes (largest first) plus the
+// total count. limit defaults to and is clamped at maxPackSizesPageLimit; an
+// offset past the end yields an empty page. Pages carry an ETag like
+// writeCatalog.
+func writeCatalogPage(w http.ResponseWriter, r *http.Request, packSizeService service.PackSizeService) {
+	limit, err := queryInt(r, "limit", maxPackSizesPageLimit)
+	if err != nil || limit <= 0 {
+		writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "offset must be a non-negative integer")
+		return
+	}
+	limit = min(limit, maxPackSizesPageLimit)
+
+	packSizes, version := packSizeService.GetCatalog()
+	total := len(packSizes)
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	writeJSONWithETag(w, r, packSizesResponse{
+		PackSizes:    packSizes[start:end],
+		Version:      version,
+		MaxPackSizes: service.MaxPackSizes(),
+		Total:        &total,
+		Limit:        &limit,
+		Offset:       &offset,
+	})
+}
+
+func (h *handler) handlePackUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	report, err := service.PackUsage()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (h *handler) handleSuggestExact(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req suggestExactRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizes := req.PackSizes
+	if packSizes == nil {
+		packSizeService, err := service.GetPackSizeService()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+			return
+		}
+		packSizes = packSizeService.GetPackSizes()
+	}
+
+	suggestion, err := service.SuggestExactPackSize(req.ItemsOrdered, packSizes)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to suggest pack size")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, suggestion)
+}
+
+func (h *handler) handleCoverage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req coverageRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizes := req.PackSizes
+	if packSizes == nil {
+		packSizeService, err := service.GetPackSizeService()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+			return
+		}
+		packSizes = packSizeService.GetPackSizes()
+	}
+
+	report, err := service.CheckCoverage(req.MaxOrder, req.MaxOverfill, packSizes)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to check coverage")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (h *handler) handleLint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req lintRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizes := req.PackSizes
+	if packSizes == nil {
+		packSizeService, err := service.GetPackSizeService()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+			return
+		}
+		packSizes = packSizeService.GetPackSizes()
+	}
+
+	lint, err := service.LintPackSizes(packSizes, req.SampleMax)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to lint pack sizes")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lint)
+}
+
+func (h *handler) handlePruneSuggest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req pruneSuggestRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+
+	suggestion, err := service.SuggestPrunablePackSizes(req.Orders, packSizeService.GetPackSizes())
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to analyze pack sizes")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, suggestion)
+}
+
+func (h *handler) handleMinimalExact(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req minimalExactRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	catalog, err := service.SuggestMinimalExactCatalog(req.Targets, req.MinSize)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to compute a minimal catalog")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, catalog)
+}
+
+func (h *handler) handleForecast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req forecastRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+
+	forecast, err := service.PlanForecast(req.Periods, packSizeService.GetPackSizes())
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to plan forecast")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, forecast)
+}
+
+func (h *handler) handleCart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req cartRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+
+	cart, err := service.OptimizeCart(req.Lines, r.URL.Query().Get("objective"), packSizeService.GetPackSizes())
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to optimize cart")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cart)
+}
+
+func (h *handler) handleFlushCache(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, service.FlushCaches())
+}
+
+func (h *handler) handleTieredCost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req tieredCostRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	plan, err := service.OptimizeTieredCost(req.ItemsOrdered, req.Pricing)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to optimize tiered cost")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, plan)
+}
+
+func (h *handler) handleCost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req costRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	plan, totalCost, err := service.OptimizeByCost(req.ItemsOrdered, req.Packs)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to optimize cost")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, costResponse{Plan: plan, TotalCost: totalCost})
+}
+
+func (h *handler) handleInventory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req inventoryRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	plan, err := service.OptimizeWithInventory(req.ItemsOrdered, req.Inventory)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to optimize within inventory")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, plan)
+}
+
+func (h *handler) handleTwoTier(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req twoTierRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	plan, err := service.OptimizeTwoTier(req.ItemsOrdered, req.PrimarySizes, req.FillerSizes)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to optimize two-tier catalog")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, plan)
+}
+
+func (h *handler) handleAlternatives(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req alternativesRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizes := req.PackSizes
+	if packSizes == nil {
+		packSizeService, err := service.GetPackSizeService()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+			return
+		}
+		packSizes = packSizeService.GetPackSizes()
+	}
+
+	plans, err := service.OptimizeN(req.ItemsOrdered, packSizes, req.N)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to list alternative plans")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, alternativesResponse{Plans: plans})
+}
+
+func (h *handler) handleMatrix(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req matrixRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	matrix, err := service.CompareMatrix(req.Orders, req.Catalogs)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to compare catalogs")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, matrix)
+}
+
+func (h *handler) handleStatic(w http.ResponseWriter, r *http.Request) {
+	h.static.ServeHTTP(w, r)
+}
+
+// byteOrderMark is tolerated around JSON bodies; some clients write one.
+const byteOrderMark = '\uFEFF'
+
+func decodeJSON(body io.ReadCloser, dst any) error {
+	defer body.Close()
+
+	reader := bufio.NewReader(body)
+	offset := int64(0)
+	if r, size, err := reader.ReadRune(); err == nil {
+		if r == byteOrderMark {
+			offset = int64(size)
+		} else {
+			_ = reader.UnreadRune()
+		}
+	}
+
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return err
+	}
+	offset += decoder.InputOffset()
+	return checkTrailingJSON(bufio.NewReader(io.MultiReader(decoder.Buffered(), reader)), offset)
+}
+
+// checkTrailingJSON accepts whitespace and byte order marks after a decoded
+// JSON value and rejects anything else, naming what was found and where;
+// offset is the byte offset of rest in the body.
+func checkTrailingJSON(rest *bufio.Reader, offset int64) error {
+	for {
+		r, size, err := rest.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == byteOrderMark:
+			offset += int64(size)
+		case strings.ContainsRune(`{["-0123456789tfn`, r):
+			return fmt.Errorf("request body must contain one JSON value, found another at byte %d", offset)
+		default:
+			return fmt.Errorf("request body has unexpected %q after the JSON value at byte %d", r, offset)
+		}
+	}
+}
+
+// isValidationError reports whether err was caused by invalid client input.
+func isValidationError(err error) bool {
+	return errors.Is(err, service.ErrInvalidItemsOrdered) ||
+		errors.Is(err, service.ErrInvalidPackSizes) ||
+		errors.Is(err, service.ErrPackSizeTooLarge) ||
+		errors.Is(err, service.ErrTooManyPackSizes) ||
+		errors.Is(err, service.ErrOptimizationTooLarge) ||
+		errors.Is(err, service.ErrInvalidOrderDistribution) ||
+		errors.Is(err, service.ErrInvalidShipmentCap) ||
+		errors.Is(err, service.ErrTooManyShipments) ||
+		errors.Is(err, service.ErrInvalidShipmentWeight) ||
+		errors.Is(err, service.ErrShipmentTooLight) ||
+		errors.Is(err, service.ErrMaxTotalUnreachable) ||
+		errors.Is(err, service.ErrOverfillExceeded) ||
+		errors.Is(err, service.ErrTooManyPacks) ||
+		errors.Is(err, service.ErrInvalidForecast) ||
+		errors.Is(err, service.ErrInvalidSimulation) ||
+		errors.Is(err, service.ErrInvalidExactRange) ||
+		errors.Is(err, service.ErrInvalidPricing) ||
+		errors.Is(err, service.ErrInvalidCoverage) ||
+		errors.Is(err, service.ErrInvalidLintSample) ||
+		errors.Is(err, service.ErrInvalidCart) ||
+		errors.Is(err, service.ErrInvalidCartObjective) ||
+		errors.Is(err, service.ErrInvalidObjective) ||
+		errors.Is(err, service.ErrInvalidTwoTier) ||
+		errors.Is(err, service.ErrInvalidAlternatives) ||
+		errors.Is(err, service.ErrTooManyAlternatives) ||
+		errors.Is(err, service.ErrInvalidMatrix) ||
+		errors.Is(err, service.ErrInvalidPackCost) ||
+		errors.Is(err, service.ErrInvalidInventory) ||
+		errors.Is(err, service.ErrInsufficientInventory) ||
+		errors.Is(err, service.ErrInvalidExactTargets) ||
+		errors.Is(err, service.ErrInvalidTableLimit) ||
+		errors.Is(err, service.ErrInvalidProfileName) ||
+		errors.Is(err, service.ErrInvalidPackSKUs)
+}
+
+// isUnsatisfiableOrder reports whether err rejects a well-formed optimize
+// request on its values; those answer 422 so clients can tell them from
+// malformed requests (400). The order range check runs first and answers 400
+// for every order the catalog's table cannot serve, so only work bounds the
+// options add on top of the table (e.g. switch_penalty on an order an admin
+// allowed with X-Max-Table-Entries) still get here.
+func isUnsatisfiableOrder(err error) bool {
+	return errors.Is(err, service.ErrOptimizationTooLarge)
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
 }
@@ -2,10 +2,17 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"gymshark/internal/service"
 )
@@ -60,15 +67,16 @@ func TestOptimizeEndpoint(t *testing.T) {
 	}
 
 	var payload struct {
-		ItemsOrdered int `json:"items_ordered"`
-		TotalItems   int `json:"total_items"`
-		TotalPacks   int `json:"total_packs"`
+		ItemsOrdered int  `json:"items_ordered"`
+		TotalItems   int  `json:"total_items"`
+		TotalPacks   int  `json:"total_packs"`
+		Optimal      bool `json:"optimal"`
 	}
 	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
 		t.Fatalf("decode response: %v", err)
 	}
 
-	if payload.ItemsOrdered != 251 || payload.TotalItems != 500 || payload.TotalPacks != 1 {
+	if payload.ItemsOrdered != 251 || payload.TotalItems != 500 || payload.TotalPacks != 1 || !payload.Optimal {
 		t.Fatalf("unexpected optimize response: %+v", payload)
 	}
 }
@@ -123,14 +131,515 @@ func TestOptimizeEndpoint_RejectsPackSizesField(t *testing.T) {
 func TestOptimizeEndpoint_InvalidItemsOrdered(t *testing.T) {
 	srv := newTestHandler(t)
 
-	body := bytes.NewBufferString(`{"items_ordered":0}`)
-	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "zero", body: `{"items_ordered":0}`, wantStatus: http.StatusBadRequest},
+		{name: "negative", body: `{"items_ordered":-1}`, wantStatus: http.StatusBadRequest},
+		{name: "table too large", body: `{"items_ordered":10000000}`, wantStatus: http.StatusBadRequest},
+		{name: "malformed JSON", body: `{"items_ordered":`, wantStatus: http.StatusBadRequest},
+		{name: "wrong type", body: `{"items_ordered":"251"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tt.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tt.wantStatus, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_OrderRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		minOrder    string
+		maxOrder    string
+		request     *http.Request
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name: "below the minimum", minOrder: "10", maxOrder: "1000000",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":5}`)),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 10 and 1000000",
+		},
+		{
+			name: "non-positive", minOrder: "10", maxOrder: "1000000",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":0}`)),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 10 and 1000000",
+		},
+		{
+			name: "above the maximum", minOrder: "10", maxOrder: "1000000",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":1000001}`)),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 10 and 1000000",
+		},
+		{
+			name: "beyond the int32 ceiling", maxOrder: "1000000",
+			request:    httptest.NewRequest(http.MethodGet, "/api/optimize?items_ordered=3000000000", nil),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 1 and 1000000",
+		},
+		{
+			name: "minimum only", minOrder: "10",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":5}`)),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 10 and 1995000",
+		},
+		{
+			name: "maximum above the catalog's", maxOrder: "5000000",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":2000000}`)),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 1 and 1995000",
+		},
+		{
+			name:       "default non-positive",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":0}`)),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 1 and 1995000",
+		},
+		{
+			name:       "default too large for the table",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":10000000}`)),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 1 and 1995000",
+		},
+		{
+			name:       "default beyond the int32 ceiling",
+			request:    httptest.NewRequest(http.MethodGet, "/api/optimize?items_ordered=3000000000", nil),
+			wantStatus: http.StatusBadRequest, wantMessage: "items_ordered must be between 1 and 1995000",
+		},
+		{
+			name:       "default within the range",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":1995000}`)),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "within the range", minOrder: "10", maxOrder: "1000000",
+			request:    httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":1000000}`)),
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("MIN_ORDER", tc.minOrder)
+			t.Setenv("MAX_ORDER", tc.maxOrder)
+			srv := newTestHandler(t)
+
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, tc.request)
+
+			if res.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.wantStatus, res.Body.String())
+			}
+			if tc.wantStatus == http.StatusOK {
+				return
+			}
+			var payload errorResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			want := apiError{Code: codeInvalidItemsOrdered, Message: tc.wantMessage}
+			if payload.Error != want {
+				t.Fatalf("error = %+v, want %+v", payload.Error, want)
+			}
+		})
+	}
+}
+
+func TestNewHandler_InvalidOrderRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		minOrder string
+		maxOrder string
+	}{
+		{name: "zero minimum", minOrder: "0"},
+		{name: "non-numeric maximum", maxOrder: "lots"},
+		{name: "maximum below minimum", minOrder: "100", maxOrder: "10"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("MIN_ORDER", tc.minOrder)
+			t.Setenv("MAX_ORDER", tc.maxOrder)
+
+			if _, err := NewHandler(); err == nil {
+				t.Fatal("expected NewHandler to reject the order range")
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_Get(t *testing.T) {
+	srv := newTestHandler(t)
+
+	post := httptest.NewRecorder()
+	srv.ServeHTTP(post, httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":251}`)))
+	get := httptest.NewRecorder()
+	srv.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/api/optimize?items_ordered=251", nil))
+
+	if get.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", get.Code, get.Body.String())
+	}
+	if get.Body.String() != post.Body.String() {
+		t.Fatalf("GET body = %s, want the POST body %s", get.Body.String(), post.Body.String())
+	}
+}
+
+func TestOptimizeEndpoint_GetInvalidQuery(t *testing.T) {
+	srv := newTestHandler(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantErr    string
+	}{
+		{name: "missing", query: "", wantStatus: http.StatusBadRequest, wantErr: "items_ordered is required"},
+		{name: "not an integer", query: "?items_ordered=abc", wantStatus: http.StatusBadRequest, wantErr: `items_ordered must be an integer, got "abc"`},
+		{name: "zero", query: "?items_ordered=0", wantStatus: http.StatusBadRequest, wantErr: service.ErrInvalidItemsOrdered.Error()},
+		{name: "negative", query: "?items_ordered=-5", wantStatus: http.StatusBadRequest, wantErr: service.ErrInvalidItemsOrdered.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/optimize"+tt.query, nil))
+
+			if res.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", res.Code, tt.wantStatus)
+			}
+			var payload errorResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.Error.Message != tt.wantErr {
+				t.Fatalf("error = %q, want %q", payload.Error.Message, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_BeyondInt32(t *testing.T) {
+	srv := newTestHandler(t)
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	if err := packSizeService.SetPackSizes([]int{250_000, 500_000, 1_000_000}); err != nil {
+		t.Fatalf("SetPackSizes returned error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantTotal   int
+		wantMessage string
+	}{
+		{name: "plain order", body: `{"items_ordered":3000000001}`, wantStatus: http.StatusOK, wantTotal: 3_000_250_000},
+		{name: "options rejected", body: `{"items_ordered":3000000001,"explain":true}`, wantStatus: http.StatusBadRequest},
+		{name: "idempotent rejected", body: `{"items_ordered":3000000001,"idempotent":true}`, wantStatus: http.StatusBadRequest},
+		{
+			name: "between the table and the ceiling", body: `{"items_ordered":2000000}`, wantStatus: http.StatusBadRequest,
+			wantMessage: "items_ordered must be between 1 and 1000000, or between 2147483648 and 499999000000",
+		},
+		{
+			name: "above the scaled table", body: `{"items_ordered":500000000000}`, wantStatus: http.StatusBadRequest,
+			wantMessage: "items_ordered must be between 1 and 1000000, or between 2147483648 and 499999000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tt.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tt.wantStatus, res.Body.String())
+			}
+			if tt.wantMessage != "" {
+				var payload errorResponse
+				if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if payload.Error.Message != tt.wantMessage {
+					t.Fatalf("error = %q, want %q", payload.Error.Message, tt.wantMessage)
+				}
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var payload struct {
+				ItemsOrdered int `json:"items_ordered"`
+				TotalItems   int `json:"total_items"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.ItemsOrdered != 3_000_000_001 || payload.TotalItems != tt.wantTotal {
+				t.Fatalf("unexpected optimize response: %+v", payload)
+			}
+		})
+	}
+}
+
+func TestPackSizesEndpoint_Detail(t *testing.T) {
+	srv := newTestHandler(t)
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	if err := packSizeService.SetPackSizes([]int{250, 500}); err != nil {
+		t.Fatalf("SetPackSizes returned error: %v", err)
+	}
+
 	res := httptest.NewRecorder()
-	srv.ServeHTTP(res, req)
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/pack-sizes?detail=true", nil))
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+	var payload packSizesResponse
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Detail == nil || payload.Detail.ScanMax != 5000 || len(payload.Detail.Sizes) != 2 {
+		t.Fatalf("unexpected detail: %+v", payload.Detail)
+	}
+	small := payload.Detail.Sizes[1]
+	if small.Size != 250 || *small.PrimaryMinOrder != 1 || *small.PrimaryMaxOrder != 250 {
+		t.Fatalf("unexpected detail for 250: %+v", small)
+	}
+
+	for _, query := range []string{"?detail=maybe", "?detail=true&limit=1"} {
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/pack-sizes"+query, nil))
+		if res.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", query, res.Code)
+		}
+	}
+}
+
+func TestPackSizesEndpoint_Patch(t *testing.T) {
+	srv := newTestHandler(t)
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	if err := packSizeService.SetPackSizes([]int{250, 500}); err != nil {
+		t.Fatalf("SetPackSizes returned error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantSizes  []int
+	}{
+		{name: "new size", body: `{"add":[1500]}`, wantStatus: http.StatusOK, wantSizes: []int{1500, 500, 250}},
+		{name: "duplicate is a no-op", body: `{"add":[500]}`, wantStatus: http.StatusOK, wantSizes: []int{1500, 500, 250}},
+		{name: "invalid size", body: `{"add":[-1]}`, wantStatus: http.StatusBadRequest},
+		{name: "nothing to add", body: `{"add":[]}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown field", body: `{"pack_sizes":[1500]}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/api/pack-sizes", bytes.NewBufferString(tt.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tt.wantStatus, res.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var payload packSizesResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(payload.PackSizes, tt.wantSizes) {
+				t.Fatalf("pack_sizes = %v, want %v", payload.PackSizes, tt.wantSizes)
+			}
+		})
+	}
+}
+
+func TestPackSizesEndpoint_MaxPackSizes(t *testing.T) {
+	srv := newTestHandler(t)
+
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/pack-sizes", nil))
+	var catalog packSizesResponse
+	if err := json.NewDecoder(res.Body).Decode(&catalog); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if catalog.MaxPackSizes != 50 {
+		t.Fatalf("max_pack_sizes = %d, want 50", catalog.MaxPackSizes)
+	}
+
+	sizes := make([]string, catalog.MaxPackSizes+1)
+	for i := range sizes {
+		sizes[i] = strconv.Itoa(i + 1)
+	}
+	body := bytes.NewBufferString(`{"pack_sizes":[` + strings.Join(sizes, ",") + `]}`)
+	res = httptest.NewRecorder()
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodPut, "/api/pack-sizes", body))
 
 	if res.Code != http.StatusBadRequest {
 		t.Fatalf("status = %d, want 400", res.Code)
 	}
+	var payload catalogValidationError
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := []service.PackSizeViolation{{Rule: service.RuleTooMany, Limit: 50}}
+	if payload.Error.Code != codeTooManyPackSizes || !reflect.DeepEqual(payload.Violations, want) {
+		t.Fatalf("error = %+v, violations = %+v", payload.Error, payload.Violations)
+	}
+}
+
+func TestPackSizeEndpoint_Delete(t *testing.T) {
+	srv := newTestHandler(t)
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	if err := packSizeService.SetPackSizes([]int{250, 500}); err != nil {
+		t.Fatalf("SetPackSizes returned error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantSizes  []int
+	}{
+		{name: "unknown size", method: http.MethodDelete, path: "/api/pack-sizes/300", wantStatus: http.StatusNotFound},
+		{name: "not an integer", method: http.MethodDelete, path: "/api/pack-sizes/abc", wantStatus: http.StatusBadRequest},
+		{name: "other method", method: http.MethodGet, path: "/api/pack-sizes/500", wantStatus: http.StatusMethodNotAllowed},
+		{name: "configured size", method: http.MethodDelete, path: "/api/pack-sizes/500", wantStatus: http.StatusOK, wantSizes: []int{250}},
+		{name: "last size", method: http.MethodDelete, path: "/api/pack-sizes/250", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if res.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tt.wantStatus, res.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var payload packSizesResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(payload.PackSizes, tt.wantSizes) {
+				t.Fatalf("pack_sizes = %v, want %v", payload.PackSizes, tt.wantSizes)
+			}
+		})
+	}
+
+	if sizes := packSizeService.GetPackSizes(); !reflect.DeepEqual(sizes, []int{250}) {
+		t.Fatalf("configured pack sizes = %v, want [250]", sizes)
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "trailing whitespace", body: "{\"items_ordered\":251} \r\n\t"},
+		{name: "leading byte order mark", body: "\uFEFF{\"items_ordered\":251}"},
+		{name: "trailing byte order mark", body: "{\"items_ordered\":251}\n\uFEFF"},
+		{name: "second object", body: `{"items_ordered":251} {"items_ordered":1}`, wantErr: "request body must contain one JSON value, found another at byte 22"},
+		{name: "second object after byte order mark", body: "\uFEFF{\"items_ordered\":251}[]", wantErr: "request body must contain one JSON value, found another at byte 24"},
+		{name: "trailing comment", body: "{\"items_ordered\":251}\n// retry", wantErr: `request body has unexpected '/' after the JSON value at byte 22`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req optimizeRequest
+			err := decodeJSON(io.NopCloser(strings.NewReader(tt.body)), &req)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if req.ItemsOrdered != 251 {
+				t.Fatalf("items_ordered = %d, want 251", req.ItemsOrdered)
+			}
+		})
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{name: "liveness", method: http.MethodGet, path: "/api/health/live", status: http.StatusOK},
+		{name: "liveness alias", method: http.MethodGet, path: "/api/health", status: http.StatusOK},
+		{name: "readiness", method: http.MethodGet, path: "/api/health/ready", status: http.StatusOK},
+		{name: "readiness wrong method", method: http.MethodPost, path: "/api/health/ready", status: http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, httptest.NewRequest(tc.method, tc.path, nil))
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d", res.Code, tc.status)
+			}
+			if tc.status == http.StatusOK && strings.TrimSpace(res.Body.String()) != `{"status":"ok"}` {
+				t.Fatalf("body = %q, want ok", res.Body.String())
+			}
+		})
+	}
+}
+
+func TestReadyEndpoint_InitializationFailed(t *testing.T) {
+	h := &handler{ready: func() error { return service.ErrInvalidPackSizes }}
+
+	res := httptest.NewRecorder()
+	h.handleReady(res, httptest.NewRequest(http.MethodGet, "/api/health/ready", nil))
+
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", res.Code)
+	}
+	var payload struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Status != "unavailable" || payload.Reason != service.ErrInvalidPackSizes.Error() {
+		t.Fatalf("payload = %+v, want unavailable with the init error", payload)
+	}
 }
 
 func TestPackSizesEndpoint_Get(t *testing.T) {
@@ -212,6 +721,64 @@ func TestPackSizesEndpoint_UpdateInvalid(t *testing.T) {
 	}
 }
 
+func TestPackSizesEndpoint_UpdateWithoutSizes(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "missing", mode: "reject", body: `{}`, wantStatus: http.StatusBadRequest, wantMessage: "pack_sizes is missing"},
+		{name: "null", mode: "reject", body: `{"pack_sizes":null}`, wantStatus: http.StatusBadRequest, wantMessage: "pack_sizes is null"},
+		{name: "empty", mode: "reject", body: `{"pack_sizes":[]}`, wantStatus: http.StatusBadRequest, wantMessage: "pack_sizes is empty"},
+		{name: "missing keeping null", mode: "keep", body: `{}`, wantStatus: http.StatusBadRequest, wantMessage: "pack_sizes is missing"},
+		{name: "null keeping null", mode: "keep", body: `{"pack_sizes":null}`, wantStatus: http.StatusOK},
+		{name: "empty keeping null", mode: "keep", body: `{"pack_sizes":[]}`, wantStatus: http.StatusBadRequest, wantMessage: "pack_sizes is empty"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NULL_PACK_SIZES", tc.mode)
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/pack-sizes", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.wantStatus, res.Body.String())
+			}
+			if tc.wantStatus == http.StatusOK {
+				var payload packSizesResponse
+				if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if want := []int{5000, 2000, 1000, 500, 250}; !reflect.DeepEqual(payload.PackSizes, want) {
+					t.Fatalf("pack_sizes = %v, want the unchanged catalog %v", payload.PackSizes, want)
+				}
+				return
+			}
+
+			var payload errorResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.Error.Code != codeInvalidPackSizes || !strings.HasSuffix(payload.Error.Message, tc.wantMessage) {
+				t.Fatalf("error = %+v, want %s ending in %q", payload.Error, codeInvalidPackSizes, tc.wantMessage)
+			}
+		})
+	}
+}
+
+func TestNewHandler_InvalidNullPackSizes(t *testing.T) {
+	t.Setenv("NULL_PACK_SIZES", "ignore")
+
+	if _, err := NewHandler(); err == nil {
+		t.Fatal("expected NewHandler to reject an unknown NULL_PACK_SIZES")
+	}
+}
+
 func TestStaticRootServesIndex(t *testing.T) {
 	srv := newTestHandler(t)
 
@@ -226,3 +793,1740 @@ func TestStaticRootServesIndex(t *testing.T) {
 		t.Fatalf("expected html body, got: %q", res.Body.String())
 	}
 }
+
+func TestOptimizeEndpoint_Explain(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251,"explain":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload struct {
+		Explanation *struct {
+			GapTotals          []int `json:"gap_totals"`
+			GapTotalsTruncated bool  `json:"gap_totals_truncated"`
+		} `json:"explanation"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if payload.Explanation == nil {
+		t.Fatal("expected explanation in response")
+	}
+	if len(payload.Explanation.GapTotals) == 0 || payload.Explanation.GapTotals[0] != 251 {
+		t.Fatalf("unexpected gap totals: %v", payload.Explanation.GapTotals)
+	}
+	if !payload.Explanation.GapTotalsTruncated {
+		t.Fatal("expected gap totals to be truncated for a 249 item gap")
+	}
+}
+
+func TestOptimizeEndpoint_Usage(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251,"usage":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload struct {
+		Usage *struct {
+			TableBytes int `json:"table_bytes"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if payload.Usage == nil || payload.Usage.TableBytes <= 0 {
+		t.Fatalf("expected usage with table bytes, got %+v", payload.Usage)
+	}
+}
+
+func TestPackSizesEndpoint_UpdateAboveMaxPackSize(t *testing.T) {
+	srv := newTestHandler(t)
+
+	updateBody := bytes.NewBufferString(`{"pack_sizes":[250,5000000]}`)
+	updateReq := httptest.NewRequest(http.MethodPut, "/api/pack-sizes", updateBody)
+	updateRes := httptest.NewRecorder()
+	srv.ServeHTTP(updateRes, updateReq)
+
+	if updateRes.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", updateRes.Code)
+	}
+	if !bytes.Contains(updateRes.Body.Bytes(), []byte("exceeds max pack size")) {
+		t.Fatalf("unexpected error body: %q", updateRes.Body.String())
+	}
+}
+
+func TestSuggestExactEndpoint(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":300,"pack_sizes":[250,500]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/suggest-exact", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload struct {
+		Feasible      bool `json:"feasible"`
+		SuggestedSize int  `json:"suggested_size"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if !payload.Feasible || payload.SuggestedSize != 300 {
+		t.Fatalf("unexpected suggestion: %+v", payload)
+	}
+}
+
+func TestSuggestExactEndpoint_DefaultsToConfiguredPackSizes(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":1250}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/suggest-exact", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	if !bytes.Contains(res.Body.Bytes(), []byte(`"already_exact":true`)) {
+		t.Fatalf("expected already exact suggestion, got %q", res.Body.String())
+	}
+}
+
+func TestSuggestExactEndpoint_InvalidOrder(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":0}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/suggest-exact", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+}
+
+func TestCoverageEndpoint(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"max_order":1000,"max_overfill":100,"pack_sizes":[250,500]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/coverage", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload struct {
+		Covered        bool `json:"covered"`
+		FailingOrders  int  `json:"failing_orders"`
+		WorstOffenders []struct {
+			ItemsOrdered int `json:"items_ordered"`
+			Overfill     int `json:"overfill"`
+		} `json:"worst_offenders"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if payload.Covered || payload.FailingOrders != 596 {
+		t.Fatalf("unexpected coverage: %+v", payload)
+	}
+	if len(payload.WorstOffenders) == 0 || payload.WorstOffenders[0].ItemsOrdered != 1 || payload.WorstOffenders[0].Overfill != 249 {
+		t.Fatalf("unexpected worst offenders: %+v", payload.WorstOffenders)
+	}
+}
+
+func TestCoverageEndpoint_InvalidRange(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"max_order":0,"max_overfill":100}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/coverage", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+}
+
+func TestLintEndpoint(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"pack_sizes":[250,500,1000,250]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/lint", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload service.CatalogLint
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	rules := make(map[string]string)
+	for _, finding := range payload.Findings {
+		rules[fmt.Sprintf("%d %s", finding.Size, finding.Rule)] = finding.Severity
+	}
+	if rules["500 redundant_multiple"] != service.SeverityAdvisory {
+		t.Fatalf("expected 500 flagged as an advisory redundant multiple, got %+v", payload.Findings)
+	}
+	if rules["250 duplicate"] != service.SeverityWarning {
+		t.Fatalf("expected a duplicate warning for 250, got %+v", payload.Findings)
+	}
+}
+
+func TestLintEndpoint_InvalidSample(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"sample_max":-1}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/lint", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+}
+
+func TestMigrationEndpoint(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":300,"old_pack_sizes":[250,500],"new_pack_sizes":[250,300,500]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize/migration", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload struct {
+		OldPlan struct {
+			TotalItems int `json:"total_items"`
+		} `json:"old_plan"`
+		NewPlan struct {
+			TotalItems int `json:"total_items"`
+		} `json:"new_plan"`
+		NewDominates bool `json:"new_dominates"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if payload.OldPlan.TotalItems != 500 || payload.NewPlan.TotalItems != 300 || !payload.NewDominates {
+		t.Fatalf("unexpected migration response: %+v", payload)
+	}
+}
+
+func TestMigrationEndpoint_InvalidCatalog(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":300,"old_pack_sizes":[250],"new_pack_sizes":[]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize/migration", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+	if !bytes.Contains(res.Body.Bytes(), []byte("new catalog")) {
+		t.Fatalf("expected error to name the new catalog, got %q", res.Body.String())
+	}
+}
+
+func TestMinimalExactEndpoint(t *testing.T) {
+	srv := newTestHandler(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantSizes  []int
+	}{
+		{name: "minimal catalog", body: `{"targets":[4,6,9],"min_size":2}`, wantStatus: http.StatusOK, wantSizes: []int{4, 3}},
+		{name: "no targets", body: `{"targets":[]}`, wantStatus: http.StatusBadRequest},
+		{name: "search too large", body: `{"targets":[2999,4993,4999],"min_size":1000}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/minimal-exact", bytes.NewBufferString(tt.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tt.wantStatus, res.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var payload struct {
+				Feasible  bool  `json:"feasible"`
+				PackSizes []int `json:"pack_sizes"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !payload.Feasible || !reflect.DeepEqual(payload.PackSizes, tt.wantSizes) {
+				t.Fatalf("unexpected response: %+v", payload)
+			}
+		})
+	}
+}
+
+func TestPruneSuggestEndpoint(t *testing.T) {
+	srv := newTestHandler(t)
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	if err := packSizeService.SetPackSizes([]int{1000, 2000, 5000}); err != nil {
+		t.Fatalf("SetPackSizes returned error: %v", err)
+	}
+
+	body := bytes.NewBufferString(`{"orders":[1000,5000,6000]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/prune-suggest", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload struct {
+		UnusedPackSizes []int `json:"unused_pack_sizes"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.UnusedPackSizes) != 1 || payload.UnusedPackSizes[0] != 2000 {
+		t.Fatalf("unused_pack_sizes = %v, want [2000]", payload.UnusedPackSizes)
+	}
+}
+
+func TestPruneSuggestEndpoint_EmptyDistribution(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"orders":[]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pack-sizes/prune-suggest", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+}
+
+func TestOptimizeEndpoint_SnapToExact(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251,"snap_to_exact":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload struct {
+		ItemsOrdered         int `json:"items_ordered"`
+		OriginalItemsOrdered int `json:"original_items_ordered"`
+		TotalItems           int `json:"total_items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if payload.ItemsOrdered != 500 || payload.OriginalItemsOrdered != 251 || payload.TotalItems != 500 {
+		t.Fatalf("unexpected snapped response: %+v", payload)
+	}
+}
+
+func TestOptimizeEndpoint_SortByCount(t *testing.T) {
+	srv := newTestHandler(t)
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	if err := packSizeService.SetPackSizes([]int{6, 10}); err != nil {
+		t.Fatalf("SetPackSizes returned error: %v", err)
+	}
+
+	body := bytes.NewBufferString(`{"items_ordered":28,"sort_by":"count"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	if !bytes.Contains(res.Body.Bytes(), []byte(`"packs":[{"size":6,"count":3},{"size":10,"count":1}]`)) {
+		t.Fatalf("expected count-sorted packs, got %q", res.Body.String())
+	}
+}
+
+func TestOptimizeEndpoint_InvalidSortBy(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":28,"sort_by":"weight"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+}
+
+func TestOptimizeEndpoint_PreferExactWithin(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		order  int
+	}{
+		{name: "inside window", body: `{"items_ordered":300,"prefer_exact_within":200}`, status: http.StatusOK, order: 500},
+		{name: "outside window", body: `{"items_ordered":300,"prefer_exact_within":100}`, status: http.StatusOK, order: 300},
+		{name: "invalid window", body: `{"items_ordered":300,"prefer_exact_within":0}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d", res.Code, tc.status)
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload struct {
+				ItemsOrdered int `json:"items_ordered"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.ItemsOrdered != tc.order {
+				t.Fatalf("items_ordered = %d, want %d", payload.ItemsOrdered, tc.order)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_MaxItemsPerShipment(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":6001,"max_items_per_shipment":5000}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+
+	var payload service.Plan
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(payload.Shipments) != 2 || payload.Shipments[0].TotalItems != 5000 || payload.Shipments[1].TotalItems != 1250 {
+		t.Fatalf("shipments = %+v, want 5000 then 1250", payload.Shipments)
+	}
+
+	// Every shipment is a plan of its own; the top level is their aggregate.
+	ordered, items, packs := 0, 0, 0
+	for i, shipment := range payload.Shipments {
+		if shipment.TotalItems > 5000 || len(shipment.Packs) == 0 || len(shipment.Shipments) != 0 {
+			t.Fatalf("shipment %d is not a single plan under the cap: %+v", i, shipment)
+		}
+		if shipment.TotalPacks != service.TotalPhysicalPacks(shipment.Packs) || shipment.Overfill != shipment.TotalItems-shipment.ItemsOrdered {
+			t.Fatalf("shipment %d totals do not match its packs: %+v", i, shipment)
+		}
+		ordered += shipment.ItemsOrdered
+		items += shipment.TotalItems
+		packs += shipment.TotalPacks
+	}
+	if ordered != 6001 || payload.ItemsOrdered != 6001 {
+		t.Fatalf("shipments order %d items, plan %d; want 6001", ordered, payload.ItemsOrdered)
+	}
+	if payload.TotalItems != items || payload.TotalPacks != packs || payload.Overfill != items-6001 {
+		t.Fatalf("aggregate = %+v, want the sum of its shipments (%d items, %d packs)", payload, items, packs)
+	}
+	want := []service.PackBreakdown{{Size: 5000, Count: 1}, {Size: 1000, Count: 1}, {Size: 250, Count: 1}}
+	if !reflect.DeepEqual(payload.Packs, want) {
+		t.Fatalf("packs = %+v, want %+v", payload.Packs, want)
+	}
+}
+
+func TestOptimizeEndpoint_MaxItemsPerShipmentBelowPackSizes(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":300,"max_items_per_shipment":100}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+}
+
+func TestOptimizeEndpoint_ShipmentWeight(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		shipments int
+	}{
+		{name: "weight splits", body: `{"items_ordered":1000,"item_weight":2,"max_shipment_weight":1000}`, status: http.StatusOK, shipments: 2},
+		{name: "weight without item weight", body: `{"items_ordered":1000,"max_shipment_weight":1000}`, status: http.StatusBadRequest},
+		{name: "zero item weight", body: `{"items_ordered":1000,"item_weight":0,"max_shipment_weight":1000}`, status: http.StatusBadRequest},
+		{name: "too light for any pack", body: `{"items_ordered":1000,"item_weight":10,"max_shipment_weight":1000}`, status: http.StatusBadRequest},
+		{name: "with switch penalty", body: `{"items_ordered":1000,"item_weight":2,"max_shipment_weight":1000,"switch_penalty":1}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(payload.Shipments) != tc.shipments || payload.Weight != payload.TotalItems*2 {
+				t.Fatalf("shipments = %d, weight = %d, want %d shipments", len(payload.Shipments), payload.Weight, tc.shipments)
+			}
+		})
+	}
+}
+
+func TestPackUsageEndpoint_CountsOptimizations(t *testing.T) {
+	srv := newTestHandler(t)
+
+	for _, body := range []string{`{"items_ordered":251}`, `{"items_ordered":12001}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(body))
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, req)
+		if res.Code != http.StatusOK {
+			t.Fatalf("optimize status = %d, want 200", res.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pack-sizes/usage", nil)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload service.PackUsageReport
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Optimizations != 2 {
+		t.Fatalf("optimizations = %d, want 2", payload.Optimizations)
+	}
+
+	packs := make(map[int]int)
+	for _, usage := range payload.PackSizes {
+		packs[usage.Size] = usage.Packs
+	}
+	// 251 ships 1x500; 12001 ships 2x5000, 1x2000 and 1x250.
+	want := map[int]int{5000: 2, 2000: 1, 1000: 0, 500: 1, 250: 1}
+	if !reflect.DeepEqual(packs, want) {
+		t.Fatalf("packs per size = %v, want %v", packs, want)
+	}
+}
+
+func TestOptimizeEndpoint_MaxTotal(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "reachable within cap", body: `{"items_ordered":251,"max_total":500}`, status: http.StatusOK},
+		{name: "infeasible cap", body: `{"items_ordered":251,"max_total":499}`, status: http.StatusBadRequest},
+		{name: "cap below order", body: `{"items_ordered":251,"max_total":250}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_CatalogReloading(t *testing.T) {
+	srv := newTestHandler(t)
+
+	end := service.BeginCatalogReload()
+	defer end()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":251}`))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", res.Code)
+	}
+	if got := res.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("Retry-After = %q, want 1", got)
+	}
+
+	end()
+	res = httptest.NewRecorder()
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":251}`)))
+	if res.Code != http.StatusOK {
+		t.Fatalf("status after reload = %d, want 200", res.Code)
+	}
+}
+
+func TestOptimizeEndpoint_Cancelled(t *testing.T) {
+	srv := newTestHandler(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":1000000}`)).WithContext(ctx)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503: %s", res.Code, res.Body.String())
+	}
+}
+
+func TestOptimizeEndpoint_MaxPacks(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "within cap", body: `{"items_ordered":12001,"max_packs":4}`, status: http.StatusOK},
+		{name: "exceeded", body: `{"items_ordered":12001,"max_packs":3}`, status: http.StatusBadRequest},
+		{name: "zero", body: `{"items_ordered":12001,"max_packs":0}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_MaxOverfill(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "within limit", body: `{"items_ordered":251,"max_overfill":249}`, status: http.StatusOK},
+		{name: "exceeded", body: `{"items_ordered":251,"max_overfill":0}`, status: http.StatusBadRequest},
+		{name: "negative", body: `{"items_ordered":251,"max_overfill":-1}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_Status(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		httpStatus int
+		want       string
+	}{
+		{name: "optimal", body: `{"items_ordered":251}`, httpStatus: http.StatusOK, want: "optimal"},
+		{name: "optimal with options", body: `{"items_ordered":12001,"sort_by":"count","max_packs":10,"objective":"fewest_sizes"}`, httpStatus: http.StatusOK, want: "optimal"},
+		{name: "fits under the shipment cap", body: `{"items_ordered":501,"max_items_per_shipment":1000}`, httpStatus: http.StatusOK, want: "optimal"},
+		{name: "split by the shipment cap", body: `{"items_ordered":6001,"max_items_per_shipment":5000}`, httpStatus: http.StatusOK, want: "capped"},
+		{name: "split by the weight cap", body: `{"items_ordered":6001,"item_weight":2,"max_shipment_weight":10000}`, httpStatus: http.StatusOK, want: "capped"},
+		{name: "exact only", body: `{"items_ordered":251,"max_overfill":0}`, httpStatus: http.StatusBadRequest, want: "infeasible_exact"},
+		{name: "overfill limit", body: `{"items_ordered":251,"max_overfill":100}`, httpStatus: http.StatusBadRequest, want: "infeasible_exact"},
+		{name: "other validation errors have none", body: `{"items_ordered":251,"max_total":300}`, httpStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.httpStatus {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.httpStatus, res.Body.String())
+			}
+			var payload struct {
+				Status string `json:"status"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.Status != tc.want {
+				t.Fatalf("status field = %q, want %q", payload.Status, tc.want)
+			}
+		})
+	}
+}
+
+func TestPackSizesEndpoint_StructuredValidationBody(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"pack_sizes":[500,0,-3,2000000,250,500]}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/pack-sizes", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+
+	var payload struct {
+		Error      apiError `json:"error"`
+		Normalized []int    `json:"normalized"`
+		Violations []struct {
+			Value *int   `json:"value"`
+			Rule  string `json:"rule"`
+			Limit int    `json:"limit"`
+		} `json:"violations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if payload.Error.Message == "" || payload.Error.Code != codeInvalidPackSizes {
+		t.Fatalf("unexpected error: %+v", payload.Error)
+	}
+	if !reflect.DeepEqual(payload.Normalized, []int{500, 250}) {
+		t.Fatalf("normalized = %v, want [500 250]", payload.Normalized)
+	}
+
+	var rules []string
+	for _, violation := range payload.Violations {
+		if violation.Value == nil {
+			t.Fatalf("violation %+v has no value", violation)
+		}
+		rules = append(rules, fmt.Sprintf("%s:%d", violation.Rule, *violation.Value))
+	}
+	want := []string{"zero:0", "negative:-3", "over_max:2000000", "duplicate:500"}
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("violations = %v, want %v", rules, want)
+	}
+	if payload.Violations[2].Limit != 1_000_000 {
+		t.Fatalf("over_max limit = %d, want 1000000", payload.Violations[2].Limit)
+	}
+}
+
+func TestForecastEndpoint_AggregatesPackDemand(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"periods":[{"label":"week 1","items_ordered":251},{"label":"week 2","items_ordered":501}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize/forecast", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+
+	var payload service.ForecastPlan
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	demand := make(map[int]int)
+	for _, pack := range payload.PackDemand {
+		demand[pack.Size] = pack.Count
+	}
+	if demand[500] != 2 || demand[250] != 1 || payload.TotalItems != 1250 {
+		t.Fatalf("unexpected forecast: %+v", payload)
+	}
+	if len(payload.Periods) != 2 || payload.Periods[0].Label != "week 1" {
+		t.Fatalf("periods = %+v", payload.Periods)
+	}
+}
+
+func TestCartEndpoint_Objectives(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		wantValue int
+	}{
+		{name: "default sums overfill", query: "", status: http.StatusOK, wantValue: 259},
+		{name: "minimax takes the worst line", query: "?objective=minimax_overfill", status: http.StatusOK, wantValue: 249},
+		{name: "unknown objective", query: "?objective=median", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			body := bytes.NewBufferString(`{"lines":[{"label":"tees","items_ordered":251},{"label":"socks","items_ordered":490}]}`)
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/cart"+tc.query, body)
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.CartPlan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.ObjectiveValue != tc.wantValue || len(payload.Lines) != 2 {
+				t.Fatalf("unexpected cart: %+v", payload)
+			}
+		})
+	}
+}
+
+func TestForecastEndpoint_EmptyForecast(t *testing.T) {
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize/forecast", bytes.NewBufferString(`{"periods":[]}`))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", res.Code)
+	}
+}
+
+func TestOptimizeEndpoint_SwitchPenalty(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		wantPacks int
+	}{
+		{name: "penalty applied", body: `{"items_ordered":12001,"switch_penalty":10}`, status: http.StatusOK, wantPacks: 7},
+		{name: "zero penalty", body: `{"items_ordered":12001,"switch_penalty":0}`, status: http.StatusBadRequest},
+		{name: "combined with shipments", body: `{"items_ordered":12001,"switch_penalty":10,"max_items_per_shipment":5000}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.TotalItems != 12250 || payload.TotalPacks != tc.wantPacks {
+				t.Fatalf("unexpected plan: %+v", payload)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_Objective(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultObj    string
+		body          string
+		status        int
+		wantObjective string
+		wantPacks     int
+	}{
+		{name: "neither", body: `{"items_ordered":12001,"echo_input":true}`, status: http.StatusOK, wantObjective: "fewest_packs", wantPacks: 4},
+		{name: "server default only", defaultObj: "fewest_sizes", body: `{"items_ordered":12001,"echo_input":true}`, status: http.StatusOK, wantObjective: "fewest_sizes", wantPacks: 49},
+		{name: "request-specified", body: `{"items_ordered":12001,"echo_input":true,"objective":"fewest_sizes"}`, status: http.StatusOK, wantObjective: "fewest_sizes", wantPacks: 49},
+		{name: "request overrides server default", defaultObj: "fewest_sizes", body: `{"items_ordered":12001,"echo_input":true,"objective":"fewest_packs"}`, status: http.StatusOK, wantObjective: "fewest_packs", wantPacks: 4},
+		{name: "invalid objective", defaultObj: "fewest_sizes", body: `{"items_ordered":12001,"objective":"cheapest"}`, status: http.StatusBadRequest},
+		{name: "combined with switch penalty", body: `{"items_ordered":12001,"objective":"fewest_sizes","switch_penalty":10}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := service.DefaultConfig()
+			cfg.DefaultObjective = tc.defaultObj
+			if err := service.SetConfig(cfg); err != nil {
+				t.Fatalf("SetConfig returned error: %v", err)
+			}
+			t.Cleanup(func() {
+				if err := service.SetConfig(service.DefaultConfig()); err != nil {
+					t.Fatalf("restore config: %v", err)
+				}
+			})
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.TotalItems != 12250 || payload.TotalPacks != tc.wantPacks {
+				t.Fatalf("unexpected plan: %+v", payload)
+			}
+			if payload.Input == nil || payload.Input.Objective != tc.wantObjective {
+				t.Fatalf("input = %+v, want objective %q", payload.Input, tc.wantObjective)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_Breakdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		body      string
+		status    int
+		wantPacks bool
+	}{
+		{name: "default", target: "/api/optimize", body: `{"items_ordered":12001}`, status: http.StatusOK, wantPacks: true},
+		{name: "explicit true", target: "/api/optimize", body: `{"items_ordered":12001,"breakdown":true}`, status: http.StatusOK, wantPacks: true},
+		{name: "totals only", target: "/api/optimize", body: `{"items_ordered":12001,"breakdown":false}`, status: http.StatusOK},
+		{name: "with describe", target: "/api/optimize", body: `{"items_ordered":12001,"breakdown":false,"describe":true}`, status: http.StatusBadRequest},
+		{name: "csv format", target: "/api/optimize?format=csv", body: `{"items_ordered":12001,"breakdown":false}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			if got := bytes.Contains(res.Body.Bytes(), []byte(`"packs"`)); got != tc.wantPacks {
+				t.Fatalf("packs present = %t, want %t: %s", got, tc.wantPacks, res.Body.String())
+			}
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.TotalItems != 12250 || payload.TotalPacks != 4 || payload.Overfill != 249 {
+				t.Fatalf("unexpected plan: %+v", payload)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_NearestExact(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251,"nearest_exact":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	if !bytes.Contains(res.Body.Bytes(), []byte(`"nearest_exact_below":250,"nearest_exact_above":500`)) {
+		t.Fatalf("expected nearest exact totals 250 and 500, got %q", res.Body.String())
+	}
+}
+
+func TestOptimizeEndpoint_DisplayUnit(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "rounded display total", body: `{"items_ordered":12001,"display_unit":1000}`, status: http.StatusOK},
+		{name: "zero unit", body: `{"items_ordered":12001,"display_unit":0}`, status: http.StatusBadRequest},
+		{name: "negative unit", body: `{"items_ordered":12001,"display_unit":-5}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.TotalItems != 12250 || payload.DisplayTotalItems == nil || *payload.DisplayTotalItems != 12000 {
+				t.Fatalf("unexpected totals: total_items=%d display_total_items=%v", payload.TotalItems, payload.DisplayTotalItems)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_OverfillMetrics(t *testing.T) {
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":251}`))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	if !bytes.Contains(res.Body.Bytes(), []byte(`"overfill":249,"waste_percent":49.8,"overfill_source":500`)) {
+		t.Fatalf("expected overfill metrics, got %q", res.Body.String())
+	}
+}
+
+func TestOptimizeEndpoint_Savings(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":12001,"savings":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	if !bytes.Contains(res.Body.Bytes(), []byte(`"packs_saved_vs_naive":45`)) {
+		t.Fatalf("expected 45 packs saved, got %q", res.Body.String())
+	}
+}
+
+func TestOptimizeEndpoint_FillVsOrder(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251,"fill_vs_order":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	if !bytes.Contains(res.Body.Bytes(), []byte(`"pack_fill":[{"size":500,"count":1,"fill_vs_order":50.2}]`)) {
+		t.Fatalf("expected the 500 pack to be 50.2%% toward the order, got %q", res.Body.String())
+	}
+}
+
+func TestOptimizeEndpoint_EchoInput(t *testing.T) {
+	srv := newTestHandler(t)
+
+	versionRes := httptest.NewRecorder()
+	srv.ServeHTTP(versionRes, httptest.NewRequest(http.MethodGet, "/api/pack-sizes", nil))
+	var catalog struct {
+		Version uint64 `json:"version"`
+	}
+	if err := json.NewDecoder(versionRes.Body).Decode(&catalog); err != nil {
+		t.Fatalf("decode pack sizes: %v", err)
+	}
+
+	body := bytes.NewBufferString(`{"items_ordered":251,"echo_input":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload service.Plan
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := service.PlanInput{
+		ItemsOrdered:         251,
+		ResolvedItemsOrdered: 251,
+		Objective:            service.ObjectiveFewestPacks,
+		CatalogVersion:       catalog.Version,
+		PackSizes:            []int{5000, 2000, 1000, 500, 250},
+	}
+	if payload.Input == nil || !reflect.DeepEqual(*payload.Input, want) {
+		t.Fatalf("input = %+v, want %+v", payload.Input, want)
+	}
+}
+
+func TestOptimizeEndpoint_MaxTableEntries(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	srv := newTestHandler(t)
+
+	tests := []struct {
+		name   string
+		order  int
+		auth   string
+		limit  string
+		status int
+	}{
+		{name: "rejected by default", order: 3_000_000, status: http.StatusBadRequest},
+		{name: "raised by an admin", order: 3_000_000, auth: "Bearer secret", limit: "3100000", status: http.StatusOK},
+		{name: "raised limit still too small", order: 3_000_000, auth: "Bearer secret", limit: "2500000", status: http.StatusBadRequest},
+		{name: "above the ceiling", order: 3_000_000, auth: "Bearer secret", limit: "10000001", status: http.StatusBadRequest},
+		{name: "not an integer", order: 3_000_000, auth: "Bearer secret", limit: "lots", status: http.StatusBadRequest},
+		{name: "without admin token", order: 3_000_000, limit: "3100000", status: http.StatusUnauthorized},
+		{name: "with wrong admin token", order: 3_000_000, auth: "Bearer nope", limit: "3100000", status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body := bytes.NewBufferString(fmt.Sprintf(`{"items_ordered":%d}`, tc.order))
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+			if tc.auth != "" {
+				req.Header.Set("Authorization", tc.auth)
+			}
+			if tc.limit != "" {
+				req.Header.Set("X-Max-Table-Entries", tc.limit)
+			}
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.TotalItems != tc.order {
+				t.Fatalf("total_items = %d, want %d", payload.TotalItems, tc.order)
+			}
+		})
+	}
+}
+
+// An order the range accepts can still exceed an option's own work bound;
+// that is the one case answering 422.
+func TestOptimizeEndpoint_UnsatisfiableOrder(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":3000000,"switch_penalty":10}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Max-Table-Entries", "3100000")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422: %s", res.Code, res.Body.String())
+	}
+	var payload errorResponse
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Error.Code != "OPTIMIZATION_TOO_LARGE" {
+		t.Fatalf("code = %q, want OPTIMIZATION_TOO_LARGE", payload.Error.Code)
+	}
+}
+
+func TestOptimizeEndpoint_MaxTableEntriesDisabledWithoutAdminToken(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "")
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":3000000}`))
+	req.Header.Set("X-Max-Table-Entries", "3100000")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", res.Code)
+	}
+}
+
+func TestOptimizeEndpoint_Algorithm(t *testing.T) {
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":251}`))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	var payload service.Plan
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	// 2000 does not divide 5000, so the default catalog needs the DP.
+	if payload.Algorithm != service.AlgorithmDP {
+		t.Fatalf("algorithm = %q, want %q", payload.Algorithm, service.AlgorithmDP)
+	}
+}
+
+func TestOptimizeEndpoint_Timestamp(t *testing.T) {
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251,"timestamp":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var payload service.Plan
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if _, err := time.Parse(time.RFC3339, payload.ComputedAt); err != nil {
+		t.Fatalf("computed_at %q is not RFC3339: %v", payload.ComputedAt, err)
+	}
+}
+
+func TestOptimizeEndpoint_PalletCapacity(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "valid capacity", body: `{"items_ordered":12001,"pallet_capacity":5000}`, status: http.StatusOK},
+		{name: "zero capacity", body: `{"items_ordered":12001,"pallet_capacity":0}`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload struct {
+				Pallets struct {
+					FullPallets int `json:"full_pallets"`
+					Remainder   []struct {
+						Size  int `json:"size"`
+						Count int `json:"count"`
+					} `json:"remainder"`
+				} `json:"pallets"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.Pallets.FullPallets != 2 || len(payload.Pallets.Remainder) != 2 {
+				t.Fatalf("pallets = %+v, want 2 full pallets and 2 remainder sizes", payload.Pallets)
+			}
+		})
+	}
+}
+
+func TestOptimizeEndpoint_IdempotentRetriesAreIdentical(t *testing.T) {
+	srv := newTestHandler(t)
+
+	var bodies []string
+	for range 2 {
+		body := bytes.NewBufferString(`{"items_ordered":12001,"usage":true,"idempotent":true}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, req)
+
+		if res.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", res.Code)
+		}
+		bodies = append(bodies, res.Body.String())
+	}
+
+	if bodies[0] != bodies[1] {
+		t.Fatalf("retry body = %s, want %s", bodies[1], bodies[0])
+	}
+}
+
+func TestPackSizesEndpoint_VersionIncreasesOnUpdate(t *testing.T) {
+	srv := newTestHandler(t)
+
+	version := func(method, body string) uint64 {
+		t.Helper()
+		req := httptest.NewRequest(method, "/api/pack-sizes", bytes.NewBufferString(body))
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, req)
+		if res.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want 200", method, res.Code)
+		}
+
+		var payload struct {
+			Version uint64 `json:"version"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return payload.Version
+	}
+
+	before := version(http.MethodGet, "")
+	after := version(http.MethodPut, `{"pack_sizes":[250,500,1000,2000,5000]}`)
+	if after != before+1 {
+		t.Fatalf("version after update = %d, want %d", after, before+1)
+	}
+}
+
+func TestFlushCacheEndpoint_ClearsStoredResults(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":12001,"idempotent":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/admin/flush-cache", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	var report service.FlushReport
+	if err := json.NewDecoder(res.Body).Decode(&report); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if report.IdempotentResults == 0 {
+		t.Fatalf("report = %+v, want stored results cleared", report)
+	}
+}
+
+func TestTieredCostEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		cost   int64
+	}{
+		{
+			name:   "discount tier applies",
+			body:   `{"items_ordered":1000,"pricing":[{"size":250,"tiers":[{"min_count":1,"unit_cost":100},{"min_count":4,"unit_cost":60}]},{"size":500,"tiers":[{"min_count":1,"unit_cost":150}]}]}`,
+			status: http.StatusOK,
+			cost:   240,
+		},
+		{
+			name:   "invalid tiers",
+			body:   `{"items_ordered":1000,"pricing":[{"size":250,"tiers":[]}]}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/tiered-cost", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.TieredCostPlan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.TotalCost != tc.cost {
+				t.Fatalf("total_cost = %d, want %d", payload.TotalCost, tc.cost)
+			}
+		})
+	}
+}
+
+func TestCostEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		packs  []service.PackBreakdown
+		cost   float64
+	}{
+		{
+			name:   "small packs cheaper",
+			body:   `{"items_ordered":4500,"packs":[{"size":1000,"unit_cost":10},{"size":5000,"unit_cost":60}]}`,
+			status: http.StatusOK,
+			packs:  []service.PackBreakdown{{Size: 1000, Count: 5}},
+			cost:   50,
+		},
+		{
+			name:   "negative cost",
+			body:   `{"items_ordered":4500,"packs":[{"size":1000,"unit_cost":-1}]}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/cost", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload costResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(payload.Plan.Packs, tc.packs) || payload.TotalCost != tc.cost {
+				t.Fatalf("packs = %v, total_cost = %v, want %v and %v", payload.Plan.Packs, payload.TotalCost, tc.packs, tc.cost)
+			}
+		})
+	}
+}
+
+func TestInventoryEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		packs  []service.PackBreakdown
+	}{
+		{
+			name:   "limited size",
+			body:   `{"items_ordered":1000,"inventory":{"250":10,"500":1}}`,
+			status: http.StatusOK,
+			packs:  []service.PackBreakdown{{Size: 500, Count: 1}, {Size: 250, Count: 2}},
+		},
+		{
+			name:   "insufficient inventory",
+			body:   `{"items_ordered":1001,"inventory":{"250":2,"500":1}}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/inventory", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(payload.Packs, tc.packs) {
+				t.Fatalf("packs = %v, want %v", payload.Packs, tc.packs)
+			}
+		})
+	}
+}
+
+func TestTwoTierEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		status      int
+		total       int
+		fillerPacks int
+	}{
+		{
+			name:        "filler trims overfill",
+			body:        `{"items_ordered":510,"primary_sizes":[300,200],"filler_sizes":[50]}`,
+			status:      http.StatusOK,
+			total:       550,
+			fillerPacks: 1,
+		},
+		{
+			name:   "filler repeats a primary size",
+			body:   `{"items_ordered":510,"primary_sizes":[300,200],"filler_sizes":[200]}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/two-tier", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.Plan
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if payload.TotalItems != tc.total || payload.FillerPacks != tc.fillerPacks {
+				t.Fatalf("total_items = %d, filler_packs = %d, want %d and %d", payload.TotalItems, payload.FillerPacks, tc.total, tc.fillerPacks)
+			}
+		})
+	}
+}
+
+func TestAlternativesEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		totals []int
+	}{
+		{
+			name:   "configured sizes",
+			body:   `{"items_ordered":500,"n":3}`,
+			status: http.StatusOK,
+			totals: []int{500, 500, 750},
+		},
+		{
+			name:   "request sizes",
+			body:   `{"items_ordered":500,"n":5,"pack_sizes":[500]}`,
+			status: http.StatusOK,
+			totals: []int{500},
+		},
+		{
+			name:   "zero plans",
+			body:   `{"items_ordered":500,"n":0}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/alternatives", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload alternativesResponse
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			totals := make([]int, 0, len(payload.Plans))
+			for _, plan := range payload.Plans {
+				totals = append(totals, plan.TotalItems)
+			}
+			if !reflect.DeepEqual(totals, tc.totals) {
+				t.Fatalf("totals = %v, want %v", totals, tc.totals)
+			}
+		})
+	}
+}
+
+func TestMatrixEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		cells  [][]service.MatrixCell
+	}{
+		{
+			name:   "two by two",
+			body:   `{"orders":[251,500],"catalogs":[[250,500,1000],[200,300]]}`,
+			status: http.StatusOK,
+			cells: [][]service.MatrixCell{
+				{{TotalItems: 500, Overfill: 249, TotalPacks: 1}, {TotalItems: 300, Overfill: 49, TotalPacks: 1}},
+				{{TotalItems: 500, Overfill: 0, TotalPacks: 1}, {TotalItems: 500, Overfill: 0, TotalPacks: 2}},
+			},
+		},
+		{
+			name:   "invalid catalog",
+			body:   `{"orders":[251],"catalogs":[[250],[-1]]}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/optimize/matrix", bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload service.PlanMatrix
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(payload.Cells, tc.cells) {
+				t.Fatalf("cells = %+v, want %+v", payload.Cells, tc.cells)
+			}
+		})
+	}
+}
+
+func TestPackSizesEndpoint_Pagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		wantSizes []int
+		wantLimit int
+	}{
+		{name: "first page", query: "?limit=2&offset=0", status: http.StatusOK, wantSizes: []int{5000, 2000}, wantLimit: 2},
+		{name: "last partial page", query: "?limit=2&offset=4", status: http.StatusOK, wantSizes: []int{250}, wantLimit: 2},
+		{name: "offset past end", query: "?limit=2&offset=5", status: http.StatusOK, wantSizes: []int{}, wantLimit: 2},
+		{name: "limit clamped", query: "?limit=100000", status: http.StatusOK, wantSizes: []int{5000, 2000, 1000, 500, 250}, wantLimit: maxPackSizesPageLimit},
+		{name: "zero limit", query: "?limit=0", status: http.StatusBadRequest},
+		{name: "negative offset", query: "?offset=-1", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/pack-sizes"+tc.query, nil)
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d", res.Code, tc.status)
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			var payload struct {
+				PackSizes []int `json:"pack_sizes"`
+				Total     int   `json:"total"`
+				Limit     int   `json:"limit"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(payload.PackSizes, tc.wantSizes) {
+				t.Fatalf("pack_sizes = %v, want %v", payload.PackSizes, tc.wantSizes)
+			}
+			if payload.Total != 5 || payload.Limit != tc.wantLimit {
+				t.Fatalf("total = %d, limit = %d, want 5 and %d", payload.Total, payload.Limit, tc.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"gymshark/internal/service"
+)
+
+// planVocabulary is the IRI prefix for plan terms schema.org has no
+// equivalent for.
+const planVocabulary = "urn:pack-optimizer:"
+
+// planContext is the JSON-LD context for plans. Unprefixed terms resolve
+// against schema.org; plan totals and pack sizes use planVocabulary.
+var planContext = map[string]string{
+	"@vocab":       "https://schema.org/",
+	"po":           planVocabulary,
+	"itemsOrdered": "po:itemsOrdered",
+	"totalItems":   "po:totalItems",
+	"totalPacks":   "po:totalPacks",
+	"packSize":     "po:packSize",
+}
+
+// planDocument maps a plan onto a schema.org Order whose orderedItem list holds
+// one OrderItem per pack size.
+type planDocument struct {
+	Context      map[string]string `json:"@context"`
+	Type         string            `json:"@type"`
+	ItemsOrdered int               `json:"itemsOrdered"`
+	TotalItems   int               `json:"totalItems"`
+	TotalPacks   int               `json:"totalPacks"`
+	OrderedItem  []planOrderItem   `json:"orderedItem"`
+}
+
+type planOrderItem struct {
+	Type          string      `json:"@type"`
+	OrderQuantity int         `json:"orderQuantity"`
+	PackSize      int         `json:"packSize"`
+	OrderedItem   planProduct `json:"orderedItem"`
+}
+
+type planProduct struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+func newPlanDocument(plan service.Plan, label string) planDocument {
+	items := make([]planOrderItem, 0, len(plan.Packs))
+	for _, pack := range plan.Packs {
+		items = append(items, planOrderItem{
+			Type:          "OrderItem",
+			OrderQuantity: pack.Count,
+			PackSize:      pack.Size,
+			OrderedItem: planProduct{
+				Type: "Product",
+				Name: fmt.Sprintf("Pack of %d %s", pack.Size, label),
+			},
+		})
+	}
+
+	return planDocument{
+		Context:      planContext,
+		Type:         "Order",
+		ItemsOrdered: plan.ItemsOrdered,
+		TotalItems:   plan.TotalItems,
+		TotalPacks:   plan.TotalPacks,
+		OrderedItem:  items,
+	}
+}
+
+func writePlanJSONLD(w http.ResponseWriter, plan service.Plan, label string) {
+	w.Header().Set("Content-Type", "application/ld+json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(newPlanDocument(plan, label))
+}
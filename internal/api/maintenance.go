@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultMaintenanceMessage is returned when maintenance is enabled without
+// a message.
+const defaultMaintenanceMessage = "service is under maintenance"
+
+// maintenanceRetryAfter is the Retry-After, in seconds, of requests rejected
+// during maintenance; deployments take minutes rather than seconds.
+const maintenanceRetryAfter = "60"
+
+type maintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+type maintenanceResponse struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty"`
+}
+
+// maintenanceMode is the current maintenance state; nil means disabled.
+type maintenanceMode struct {
+	message string
+}
+
+func (h *handler) handleMaintenance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req maintenanceRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !req.Enabled {
+		h.maintenance.Store(nil)
+		writeJSON(w, http.StatusOK, maintenanceResponse{})
+		return
+	}
+
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+	h.maintenance.Store(&maintenanceMode{message: message})
+	writeJSON(w, http.StatusOK, maintenanceResponse{Enabled: true, Message: message})
+}
+
+// withMaintenance answers API requests with 503 and the maintenance message
+// while maintenance is enabled. Health probes stay up, admin endpoints stay
+// up so maintenance can be turned off, and static assets are served as usual.
+func withMaintenance(mode *atomic.Pointer[maintenanceMode], next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := mode.Load()
+		if current == nil || !strings.HasPrefix(r.URL.Path, "/api/") ||
+			r.URL.Path == "/api/health" || strings.HasPrefix(r.URL.Path, "/api/health/") ||
+			strings.HasPrefix(r.URL.Path, "/api/admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Retry-After", maintenanceRetryAfter)
+		writeError(w, http.StatusServiceUnavailable, current.message)
+	})
+}
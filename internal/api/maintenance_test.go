@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	srv := newTestHandler(t)
+
+	setMaintenance := func(body string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/maintenance", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, req)
+		if res.Code != http.StatusOK {
+			t.Fatalf("maintenance toggle status = %d, want 200: %s", res.Code, res.Body.String())
+		}
+	}
+	optimize := func() *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":251}`)))
+		return res
+	}
+
+	setMaintenance(`{"enabled":true,"message":"deploying"}`)
+
+	res := optimize()
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("optimize status = %d, want 503", res.Code)
+	}
+	var payload errorResponse
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Error.Message != "deploying" {
+		t.Fatalf("error = %q, want the maintenance message", payload.Error.Message)
+	}
+	if got := res.Header().Get("Retry-After"); got != maintenanceRetryAfter {
+		t.Fatalf("Retry-After = %q, want %s", got, maintenanceRetryAfter)
+	}
+
+	for _, path := range []string{"/api/health", "/api/health/live", "/api/health/ready"} {
+		health := httptest.NewRecorder()
+		srv.ServeHTTP(health, httptest.NewRequest(http.MethodGet, path, nil))
+		if health.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want 200", path, health.Code)
+		}
+	}
+
+	setMaintenance(`{"enabled":false}`)
+	if res := optimize(); res.Code != http.StatusOK {
+		t.Fatalf("optimize status after maintenance = %d, want 200", res.Code)
+	}
+}
+
+func TestMaintenanceMode_DefaultMessage(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/maintenance", bytes.NewBufferString(`{"enabled":true}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	var payload maintenanceResponse
+	if err := json.NewDecoder(res.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !payload.Enabled || payload.Message != defaultMaintenanceMessage {
+		t.Fatalf("unexpected response: %+v", payload)
+	}
+}
+
+func TestMaintenanceMode_RequiresAdmin(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "secret")
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/maintenance", bytes.NewBufferString(`{"enabled":true}`))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", res.Code)
+	}
+
+	res = httptest.NewRecorder()
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/pack-sizes", nil))
+	if res.Code != http.StatusOK {
+		t.Fatalf("pack sizes status = %d, want 200 without maintenance", res.Code)
+	}
+}
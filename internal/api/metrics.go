@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"gymshark/internal/service"
+)
+
+// metrics holds the Prometheus collectors of one handler. They live on the
+// handler's own registry, so several handlers (as in tests) never collide.
+type metrics struct {
+	optimizeRequests  *prometheus.CounterVec
+	optimizeDuration  prometheus.Histogram
+	packSizesRequests *prometheus.CounterVec
+}
+
+// newMetrics registers the handler's collectors on registry, including the
+// number of configured pack sizes, read from the catalog at scrape time.
+func newMetrics(registry prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		optimizeRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "optimize_requests_total",
+			Help: "Optimize requests by HTTP status code.",
+		}, []string{"status"}),
+		optimizeDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "optimize_duration_seconds",
+			Help:    "Time to serve an optimize request.",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 4, 8),
+		}),
+		packSizesRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pack_sizes_requests_total",
+			Help: "Pack size catalog requests by HTTP method and status code.",
+		}, []string{"method", "status"}),
+	}
+	packSizesConfigured := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "pack_sizes_configured",
+		Help: "Number of pack sizes in the active catalog.",
+	}, func() float64 {
+		packSizeService, err := service.GetPackSizeService()
+		if err != nil {
+			return 0
+		}
+		return float64(len(packSizeService.GetPackSizes()))
+	})
+
+	for _, collector := range []prometheus.Collector{m.optimizeRequests, m.optimizeDuration, m.packSizesRequests, packSizesConfigured} {
+		if err := registry.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// newProcessRegistry returns a registry with the standard Go runtime and
+// process collectors, for the server's /metrics.
+func newProcessRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+func metricsHandler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// withMetrics counts optimize and pack size catalog requests by status and
+// times optimize requests. Other paths pass through untouched.
+func withMetrics(m *metrics, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/optimize" && r.URL.Path != "/api/pack-sizes" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rec := newStatusRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		status := strconv.Itoa(rec.status)
+		if r.URL.Path == "/api/optimize" {
+			m.optimizeRequests.WithLabelValues(status).Inc()
+			m.optimizeDuration.Observe(time.Since(start).Seconds())
+			return
+		}
+		m.packSizesRequests.WithLabelValues(r.Method, status).Inc()
+	})
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gymshark/internal/service"
+)
+
+// newMetricsTestHandler is newTestHandler with the handler's metrics on a
+// registry the test can gather.
+func newMetricsTestHandler(t *testing.T) (http.Handler, *prometheus.Registry) {
+	t.Helper()
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	if err := packSizeService.SetPackSizes(testDefaultPackSizes); err != nil {
+		t.Fatalf("SetPackSizes returned error: %v", err)
+	}
+
+	registry := prometheus.NewRegistry()
+	handler, err := NewHandlerWithRegistry(registry)
+	if err != nil {
+		t.Fatalf("NewHandlerWithRegistry returned error: %v", err)
+	}
+	return handler, registry
+}
+
+// gatheredValue returns the value of the named metric whose labels include
+// labels, or -1 when there is none; histograms report their sample count.
+func gatheredValue(t *testing.T, registry *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather returned error: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if want, ok := labels[pair.GetName()]; ok && want != pair.GetValue() {
+					continue metrics
+				}
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return -1
+}
+
+func TestMetrics_CountsOptimizeAndPackSizeRequests(t *testing.T) {
+	srv, registry := newMetricsTestHandler(t)
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":251}`)),
+		httptest.NewRequest(http.MethodGet, "/api/optimize?items_ordered=12001", nil),
+		httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":0}`)),
+		httptest.NewRequest(http.MethodGet, "/api/pack-sizes", nil),
+		httptest.NewRequest(http.MethodPut, "/api/pack-sizes", bytes.NewBufferString(`{"pack_sizes":[0]}`)),
+		httptest.NewRequest(http.MethodGet, "/api/health", nil),
+	}
+	for _, req := range requests {
+		srv.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   float64
+	}{
+		{name: "optimize_requests_total", labels: map[string]string{"status": "200"}, want: 2},
+		{name: "optimize_requests_total", labels: map[string]string{"status": "400"}, want: 1},
+		{name: "optimize_duration_seconds", want: 3},
+		{name: "pack_sizes_requests_total", labels: map[string]string{"method": "GET", "status": "200"}, want: 1},
+		{name: "pack_sizes_requests_total", labels: map[string]string{"method": "PUT", "status": "400"}, want: 1},
+		{name: "pack_sizes_configured", want: 5},
+	}
+	for _, tc := range tests {
+		if got := gatheredValue(t, registry, tc.name, tc.labels); got != tc.want {
+			t.Fatalf("%s%v = %v, want %v", tc.name, tc.labels, got, tc.want)
+		}
+	}
+}
+
+func TestMetrics_Endpoint(t *testing.T) {
+	srv, _ := newMetricsTestHandler(t)
+
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/optimize?items_ordered=251", nil))
+
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+	for _, want := range []string{`optimize_requests_total{status="200"} 1`, "optimize_duration_seconds_count 1", "pack_sizes_configured 5"} {
+		if !strings.Contains(res.Body.String(), want) {
+			t.Fatalf("metrics output lacks %q:\n%s", want, res.Body.String())
+		}
+	}
+}
+
+func TestNewHandler_ServesProcessMetrics(t *testing.T) {
+	srv := newTestHandler(t)
+
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), "go_goroutines") {
+		t.Fatalf("status = %d, want 200 with Go runtime metrics", res.Code)
+	}
+}
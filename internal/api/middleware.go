@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "gymshark/internal/api"
+
+// statusRecorder captures the status code written by the wrapped handler,
+// since http.ResponseWriter does not expose it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack hands the connection over, e.g. for WebSocket upgrades, which need
+// an http.Hijacker and cannot use http.ResponseController.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// withTracing starts a server span per request, continuing any trace
+// propagated by the caller. It is a no-op until a tracer provider is installed.
+func withTracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(tracerName).Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		rec := newStatusRecorder(w)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
+
+// withRequestLogging logs method, path, status and duration of each request
+// at debug level. The check up front keeps it free when debug is disabled.
+func withRequestLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := slog.Default()
+		if !logger.Enabled(r.Context(), slog.LevelDebug) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rec := newStatusRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		logger.LogAttrs(context.WithoutCancel(r.Context()), slog.LevelDebug, "request handled",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
+
+// requireAdmin guards an admin endpoint with a bearer token. With no token
+// configured, admin endpoints are disabled and always answer 403.
+func requireAdmin(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if authorizeAdmin(w, r, token) {
+			next(w, r)
+		}
+	}
+}
+
+// authorizeAdmin reports whether r carries the admin bearer token, answering
+// 403 or 401 when it does not.
+func authorizeAdmin(w http.ResponseWriter, r *http.Request, token string) bool {
+	if token == "" {
+		writeError(w, http.StatusForbidden, "admin endpoints are disabled")
+		return false
+	}
+
+	presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "invalid admin token")
+		return false
+	}
+	return true
+}
+
+// withCORS answers preflight requests and adds CORS headers for allowed
+// origins. It passes requests through untouched when no origin is allowed.
+func withCORS(cfg corsConfig, next http.Handler) http.Handler {
+	if len(cfg.allowedOrigins) == 0 {
+		return next
+	}
+	anyOrigin := slices.Contains(cfg.allowedOrigins, "*")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if origin == "" || !cfg.allowsOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if anyOrigin {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.allowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		// Lets dashboards read the catalog ETag to revalidate with If-None-Match.
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
+
+		if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, Accept-Language, If-None-Match, X-Max-Table-Entries")
+		if cfg.maxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cfg.maxAge))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
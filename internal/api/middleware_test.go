@@ -0,0 +1,250 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gymshark/internal/logging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func installTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(t.Context())
+	})
+
+	return exporter
+}
+
+func TestTracing_OptimizeProducesSpans(t *testing.T) {
+	exporter := installTestTracer(t)
+	srv := newTestHandler(t)
+
+	body := bytes.NewBufferString(`{"items_ordered":251}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize", body)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.Code)
+	}
+
+	spans := exporter.GetSpans()
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	for _, name := range []string{
+		"POST /api/optimize",
+		"service.Optimize",
+		"service.buildPackingTable",
+		"service.chooseFulfillmentTotal",
+		"service.buildBreakdown",
+	} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("missing span %q, got %d spans", name, len(spans))
+		}
+	}
+
+	root := byName["POST /api/optimize"]
+	if !hasIntAttribute(root.Attributes, "items_ordered", 251) {
+		t.Fatalf("request span missing items_ordered attribute: %v", root.Attributes)
+	}
+	if !hasIntAttribute(root.Attributes, "total_packs", 1) {
+		t.Fatalf("request span missing total_packs attribute: %v", root.Attributes)
+	}
+	if !hasIntAttribute(root.Attributes, "http.response.status_code", http.StatusOK) {
+		t.Fatalf("request span missing status attribute: %v", root.Attributes)
+	}
+
+	optimizeSpan := byName["service.Optimize"]
+	if optimizeSpan.Parent.SpanID() != root.SpanContext.SpanID() {
+		t.Fatal("expected service.Optimize to be a child of the request span")
+	}
+}
+
+func hasIntAttribute(attrs []attribute.KeyValue, key string, want int) bool {
+	for _, attr := range attrs {
+		if string(attr.Key) == key {
+			return attr.Value.AsInt64() == int64(want)
+		}
+	}
+	return false
+}
+
+func captureDefaultLogger(t *testing.T, level slog.Level) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, level))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return &buf
+}
+
+func TestRequestLogging_Levels(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   slog.Level
+		wantLog bool
+	}{
+		{name: "debug logs requests", level: slog.LevelDebug, wantLog: true},
+		{name: "info skips requests", level: slog.LevelInfo, wantLog: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := captureDefaultLogger(t, tc.level)
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			out := buf.String()
+			logged := strings.Contains(out, "request handled")
+			if logged != tc.wantLog {
+				t.Fatalf("request logged = %t, want %t; output:\n%s", logged, tc.wantLog, out)
+			}
+			if tc.wantLog && !(strings.Contains(out, "path=/api/health") && strings.Contains(out, "status=200")) {
+				t.Fatalf("request log missing path or status:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestFlushCacheEndpoint_Auth(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		status int
+	}{
+		{name: "disabled without token", token: "", header: "Bearer secret", status: http.StatusForbidden},
+		{name: "missing credentials", token: "secret", header: "", status: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", header: "Bearer nope", status: http.StatusUnauthorized},
+		{name: "valid token", token: "secret", header: "Bearer secret", status: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ADMIN_TOKEN", tc.token)
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/flush-cache", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestCORS_PreflightMaxAge(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://shop.example")
+	t.Setenv("CORS_MAX_AGE", "600")
+	srv := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/optimize", nil)
+	req.Header.Set("Origin", "https://shop.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", res.Code)
+	}
+	if got := res.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Fatalf("Access-Control-Max-Age = %q, want 600", got)
+	}
+	if got := res.Header().Get("Access-Control-Allow-Origin"); got != "https://shop.example" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want the origin", got)
+	}
+}
+
+func TestCORS_AllowOrigin(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     string
+		credentials string
+		origin      string
+		wantOrigin  string
+		wantCreds   string
+	}{
+		{name: "wildcard", origins: "*", origin: "https://a.example", wantOrigin: "*"},
+		{name: "credentials echo origin", origins: "https://a.example", credentials: "true", origin: "https://a.example", wantOrigin: "https://a.example", wantCreds: "true"},
+		{name: "listed origin", origins: "https://a.example, https://b.example", origin: "https://b.example", wantOrigin: "https://b.example"},
+		{name: "unlisted origin", origins: "https://a.example", credentials: "true", origin: "https://evil.example"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("CORS_ALLOWED_ORIGINS", tc.origins)
+			t.Setenv("CORS_ALLOW_CREDENTIALS", tc.credentials)
+			srv := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+			req.Header.Set("Origin", tc.origin)
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", res.Code)
+			}
+			if got := res.Header().Get("Access-Control-Allow-Origin"); got != tc.wantOrigin {
+				t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, tc.wantOrigin)
+			}
+			if got := res.Header().Get("Access-Control-Allow-Credentials"); got != tc.wantCreds {
+				t.Fatalf("Access-Control-Allow-Credentials = %q, want %q", got, tc.wantCreds)
+			}
+		})
+	}
+}
+
+func TestCORS_InvalidConfig(t *testing.T) {
+	for name, value := range map[string]string{"CORS_MAX_AGE": "-1", "CORS_ALLOW_CREDENTIALS": "maybe"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := NewHandler(); err == nil {
+				t.Fatalf("expected error for %s=%q", name, value)
+			}
+		})
+	}
+}
+
+func TestCORS_WildcardWithCredentialsRejected(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://shop.example, *")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	h, err := NewHandler()
+	if err == nil {
+		req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+		req.Header.Set("Origin", "https://evil.example")
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		t.Fatalf("expected a config error, got ACAO %q and ACAC %q", res.Header().Get("Access-Control-Allow-Origin"), res.Header().Get("Access-Control-Allow-Credentials"))
+	}
+}
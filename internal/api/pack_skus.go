@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+
+	"gymshark/internal/service"
+)
+
+// packSKUsPayload is the body of GET and PUT /api/pack-sizes/skus: SKUs keyed
+// by pack size, e.g. {"skus":{"250":"BOX-S"}}.
+type packSKUsPayload struct {
+	SKUs map[int]string `json:"skus"`
+}
+
+// handlePackSKUs reads or replaces the SKU labels of the configured pack
+// sizes, which optimize results echo in their packs.
+func (h *handler) handlePackSKUs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		var req packSKUsPayload
+		if err := decodeJSON(r.Body, &req); err != nil {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := service.SetPackSKUs(req.SKUs); err != nil {
+			if isValidationError(err) {
+				writeErrorFor(w, http.StatusBadRequest, err)
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "unable to update pack SKUs")
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, packSKUsPayload{SKUs: service.PackSKUs()})
+}
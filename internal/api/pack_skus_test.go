@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"gymshark/internal/service"
+)
+
+func TestPackSKUsEndpoint(t *testing.T) {
+	srv := newTestHandler(t)
+	t.Cleanup(func() { _ = service.SetPackSKUs(nil) })
+
+	put := httptest.NewRecorder()
+	srv.ServeHTTP(put, httptest.NewRequest(http.MethodPut, "/api/pack-sizes/skus", bytes.NewBufferString(`{"skus":{"5000":"BOX-XL","250":"BOX-S"}}`)))
+	if put.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200: %s", put.Code, put.Body.String())
+	}
+
+	get := httptest.NewRecorder()
+	srv.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/api/pack-sizes/skus", nil))
+	var labels packSKUsPayload
+	if err := json.NewDecoder(get.Body).Decode(&labels); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if want := map[int]string{5000: "BOX-XL", 250: "BOX-S"}; !reflect.DeepEqual(labels.SKUs, want) {
+		t.Fatalf("skus = %v, want %v", labels.SKUs, want)
+	}
+
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(`{"items_ordered":12001}`)))
+	if res.Code != http.StatusOK {
+		t.Fatalf("optimize status = %d, want 200: %s", res.Code, res.Body.String())
+	}
+	var plan service.Plan
+	if err := json.NewDecoder(res.Body).Decode(&plan); err != nil {
+		t.Fatalf("decode plan: %v", err)
+	}
+	want := []service.PackBreakdown{{Size: 5000, Count: 2, SKU: "BOX-XL"}, {Size: 2000, Count: 1}, {Size: 250, Count: 1, SKU: "BOX-S"}}
+	if !reflect.DeepEqual(plan.Packs, want) {
+		t.Fatalf("packs = %+v, want %+v", plan.Packs, want)
+	}
+}
+
+func TestPackSKUsEndpoint_EveryOptimizePath(t *testing.T) {
+	srv := newTestHandler(t)
+	if err := service.SetPackSKUs(map[int]string{5000: "BOX-XL", 250: "BOX-S"}); err != nil {
+		t.Fatalf("SetPackSKUs returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = service.SetPackSKUs(nil) })
+	want := []service.PackBreakdown{{Size: 5000, Count: 2, SKU: "BOX-XL"}, {Size: 2000, Count: 1}, {Size: 250, Count: 1, SKU: "BOX-S"}}
+
+	for _, body := range []string{`{"items_ordered":12001}`, `{"items_ordered":12001,"idempotent":true}`} {
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/optimize", bytes.NewBufferString(body)))
+		var plan service.Plan
+		if err := json.NewDecoder(res.Body).Decode(&plan); err != nil {
+			t.Fatalf("decode plan: %v", err)
+		}
+		if !reflect.DeepEqual(plan.Packs, want) {
+			t.Fatalf("%s: packs = %+v, want %+v", body, plan.Packs, want)
+		}
+	}
+
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/api/optimize/batch", bytes.NewBufferString(`{"orders":[{"items_ordered":12001}]}`)))
+	var results []batchResult
+	if err := json.NewDecoder(res.Body).Decode(&results); err != nil {
+		t.Fatalf("decode batch: %v", err)
+	}
+	if len(results) != 1 || results[0].Plan == nil || !reflect.DeepEqual(results[0].Packs, want) {
+		t.Fatalf("batch results = %+v, want packs %+v", results, want)
+	}
+}
+
+func TestPackSKUsEndpoint_Invalid(t *testing.T) {
+	srv := newTestHandler(t)
+
+	for _, body := range []string{
+		`{"skus":{"300":"BOX-M"}}`,
+		`{"skus":{"250":""}}`,
+		`{"skus":{"small":"BOX-S"}}`,
+	} {
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, httptest.NewRequest(http.MethodPut, "/api/pack-sizes/skus", bytes.NewBufferString(body)))
+		if res.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", body, res.Code)
+		}
+	}
+}
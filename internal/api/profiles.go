@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"gymshark/internal/service"
+)
+
+type profilesResponse struct {
+	Profiles []service.PackSizeProfile `json:"profiles"`
+}
+
+type profileResponse struct {
+	Name      string `json:"name"`
+	PackSizes []int  `json:"pack_sizes"`
+}
+
+// handleProfiles lists the stored pack size profiles, the active one flagged.
+func (h *handler) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	profileService, err := service.GetProfilePackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, profilesResponse{Profiles: profileService.ListProfiles()})
+}
+
+// handleProfile reads (GET) or creates and replaces (PUT) one profile; a PUT
+// takes the same body as PUT /api/pack-sizes.
+func (h *handler) handleProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	profileService, err := service.GetProfilePackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+	name := r.PathValue("name")
+
+	if r.Method == http.MethodPut {
+		var req packSizesPayload
+		if err := decodeJSON(r.Body, &req); err != nil {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := profileService.SetProfile(name, req.PackSizes); err != nil {
+			if isValidationError(err) {
+				writeErrorFor(w, http.StatusBadRequest, err)
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "unable to update pack size profile")
+			return
+		}
+	}
+
+	packSizes, err := profileService.GetProfile(name)
+	if err != nil {
+		if errors.Is(err, service.ErrProfileNotFound) {
+			writeErrorFor(w, http.StatusNotFound, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to read pack size profile")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, profileResponse{Name: name, PackSizes: packSizes})
+}
+
+// handleActivateProfile makes a profile the catalog optimizations use and
+// returns that catalog.
+func (h *handler) handleActivateProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	profileService, err := service.GetProfilePackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+
+	if err := profileService.ActivateProfile(r.PathValue("name")); err != nil {
+		if errors.Is(err, service.ErrProfileNotFound) {
+			writeErrorFor(w, http.StatusNotFound, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to activate pack size profile")
+		return
+	}
+
+	writeCatalog(w, r, profileService)
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"gymshark/internal/service"
+)
+
+// newProfileTestHandler is newTestHandler that reactivates the default
+// profile when the test ends, so other tests keep the default catalog.
+func newProfileTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	srv := newTestHandler(t)
+	t.Cleanup(func() {
+		profileService, err := service.GetProfilePackSizeService()
+		if err != nil {
+			t.Fatalf("GetProfilePackSizeService returned error: %v", err)
+		}
+		if err := profileService.ActivateProfile(service.DefaultProfile); err != nil {
+			t.Fatalf("ActivateProfile returned error: %v", err)
+		}
+	})
+	return srv
+}
+
+func TestProfileEndpoints(t *testing.T) {
+	srv := newProfileTestHandler(t)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		res := httptest.NewRecorder()
+		srv.ServeHTTP(res, req)
+		if res.Code != http.StatusOK {
+			t.Fatalf("%s %s: status = %d, want 200: %s", method, target, res.Code, res.Body.String())
+		}
+		return res
+	}
+
+	res := serve(http.MethodPut, "/api/pack-sizes/profiles/brand-b", `{"pack_sizes":[23,31,53]}`)
+	var profile profileResponse
+	if err := json.NewDecoder(res.Body).Decode(&profile); err != nil {
+		t.Fatalf("decode profile: %v", err)
+	}
+	if want := (profileResponse{Name: "brand-b", PackSizes: []int{53, 31, 23}}); !reflect.DeepEqual(profile, want) {
+		t.Fatalf("profile = %+v, want %+v", profile, want)
+	}
+
+	res = serve(http.MethodPost, "/api/pack-sizes/profiles/brand-b/activate", "")
+	var catalog packSizesResponse
+	if err := json.NewDecoder(res.Body).Decode(&catalog); err != nil {
+		t.Fatalf("decode catalog: %v", err)
+	}
+	if !reflect.DeepEqual(catalog.PackSizes, []int{53, 31, 23}) {
+		t.Fatalf("catalog after activation = %v, want [53 31 23]", catalog.PackSizes)
+	}
+
+	res = serve(http.MethodPost, "/api/optimize", `{"items_ordered":263}`)
+	var plan service.Plan
+	if err := json.NewDecoder(res.Body).Decode(&plan); err != nil {
+		t.Fatalf("decode plan: %v", err)
+	}
+	if plan.TotalItems != 263 {
+		t.Fatalf("optimize used %d items, want the active profile's exact 263", plan.TotalItems)
+	}
+
+	// The single-list endpoints update the active profile.
+	serve(http.MethodPatch, "/api/pack-sizes", `{"add":[100]}`)
+	res = serve(http.MethodGet, "/api/pack-sizes/profiles", "")
+	var profiles profilesResponse
+	if err := json.NewDecoder(res.Body).Decode(&profiles); err != nil {
+		t.Fatalf("decode profiles: %v", err)
+	}
+	want := []service.PackSizeProfile{
+		{Name: "brand-b", PackSizes: []int{100, 53, 31, 23}, Active: true},
+		{Name: service.DefaultProfile, PackSizes: []int{5000, 2000, 1000, 500, 250}},
+	}
+	if !reflect.DeepEqual(profiles.Profiles, want) {
+		t.Fatalf("profiles = %+v, want %+v", profiles.Profiles, want)
+	}
+}
+
+func TestProfileEndpoints_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{name: "invalid name", method: http.MethodPut, target: "/api/pack-sizes/profiles/brand.b", body: `{"pack_sizes":[10]}`, status: http.StatusBadRequest},
+		{name: "invalid sizes", method: http.MethodPut, target: "/api/pack-sizes/profiles/brand-c", body: `{"pack_sizes":[0]}`, status: http.StatusBadRequest},
+		{name: "unknown profile", method: http.MethodGet, target: "/api/pack-sizes/profiles/missing", status: http.StatusNotFound},
+		{name: "activate unknown profile", method: http.MethodPost, target: "/api/pack-sizes/profiles/missing/activate", status: http.StatusNotFound},
+		{name: "list with wrong method", method: http.MethodPost, target: "/api/pack-sizes/profiles", status: http.StatusMethodNotAllowed},
+		{name: "activate with wrong method", method: http.MethodGet, target: "/api/pack-sizes/profiles/default/activate", status: http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newProfileTestHandler(t)
+
+			req := httptest.NewRequest(tc.method, tc.target, bytes.NewBufferString(tc.body))
+			res := httptest.NewRecorder()
+			srv.ServeHTTP(res, req)
+
+			if res.Code != tc.status {
+				t.Fatalf("status = %d, want %d: %s", res.Code, tc.status, res.Body.String())
+			}
+		})
+	}
+}
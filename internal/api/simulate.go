@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+
+	"gymshark/internal/service"
+)
+
+type simulateRequest struct {
+	Samples  int `json:"samples"`
+	MinOrder int `json:"min_order"`
+	MaxOrder int `json:"max_order"`
+	// Seed fixes the sampled orders; when omitted a random seed is drawn and
+	// echoed in the response, so any run can be repeated.
+	Seed *uint64 `json:"seed"`
+	// Workers overrides SIMULATION_WORKERS for this request; it never
+	// changes the result, only how fast it is computed.
+	Workers int `json:"workers"`
+}
+
+func (h *handler) handleSimulate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req simulateRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeErrorFor(w, http.StatusBadRequest, err)
+		return
+	}
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+	// One snapshot serves every worker, so a concurrent catalog update
+	// cannot split the simulation across two catalogs.
+	packSizes := packSizeService.GetPackSizes()
+	limits, err := service.OrderLimitsFor(packSizes, 0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to read the order limits")
+		return
+	}
+	// Sampled orders are held to the batch range: no OptimizeLarge path.
+	limits.Large = 0
+	if err := h.config.orders.check("min_order", req.MinOrder, limits); err != nil {
+		writeOrderRangeError(w, err)
+		return
+	}
+	if err := h.config.orders.check("max_order", req.MaxOrder, limits); err != nil {
+		writeOrderRangeError(w, err)
+		return
+	}
+
+	sim := service.Simulation{
+		Samples:  req.Samples,
+		MinOrder: req.MinOrder,
+		MaxOrder: req.MaxOrder,
+		Workers:  req.Workers,
+	}
+	if req.Seed != nil {
+		sim.Seed = *req.Seed
+	} else {
+		sim.Seed = rand.Uint64()
+	}
+
+	result, err := service.SimulateDemand(r.Context(), sim, packSizes)
+	if err != nil {
+		if isValidationError(err) {
+			writeErrorFor(w, http.StatusBadRequest, err)
+			return
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			writeError(w, http.StatusServiceUnavailable, "optimization cancelled")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to simulate demand")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
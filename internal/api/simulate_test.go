@@ -0,0 +1,116 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"gymshark/internal/service"
+)
+
+func postSimulate(t *testing.T, srv http.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/optimize/simulate", bytes.NewBufferString(body))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+	return res
+}
+
+func TestSimulateEndpoint_WorkersDoNotChangeResult(t *testing.T) {
+	srv := newTestHandler(t)
+
+	var results []service.SimulationResult
+	for _, workers := range []string{"1", "8"} {
+		res := postSimulate(t, srv, `{"samples":2000,"min_order":1,"max_order":20000,"seed":42,"workers":`+workers+`}`)
+		if res.Code != http.StatusOK {
+			t.Fatalf("workers %s: status = %d, want 200; body %s", workers, res.Code, res.Body.String())
+		}
+		var result service.SimulationResult
+		if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		results = append(results, result)
+	}
+
+	if results[0].Seed != 42 || results[0].Samples != 2000 {
+		t.Fatalf("result = %+v, want seed 42 and 2000 samples", results[0])
+	}
+	if !reflect.DeepEqual(results[0], results[1]) {
+		t.Fatalf("8 workers = %+v, want serial %+v", results[1], results[0])
+	}
+}
+
+func TestSimulateEndpoint_EchoesDrawnSeed(t *testing.T) {
+	srv := newTestHandler(t)
+
+	res := postSimulate(t, srv, `{"samples":50,"min_order":1,"max_order":1000}`)
+	if res.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body %s", res.Code, res.Body.String())
+	}
+	var first service.SimulationResult
+	if err := json.NewDecoder(res.Body).Decode(&first); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	seed, err := json.Marshal(first.Seed)
+	if err != nil {
+		t.Fatalf("marshal seed: %v", err)
+	}
+	res = postSimulate(t, srv, `{"samples":50,"min_order":1,"max_order":1000,"seed":`+string(seed)+`}`)
+	var repeated service.SimulationResult
+	if err := json.NewDecoder(res.Body).Decode(&repeated); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !reflect.DeepEqual(repeated, first) {
+		t.Fatalf("repeated run = %+v, want %+v", repeated, first)
+	}
+}
+
+func TestSimulateEndpoint_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode string
+	}{
+		{name: "no samples", body: `{"min_order":1,"max_order":10}`, wantCode: "BAD_REQUEST"},
+		{name: "too many workers", body: `{"samples":10,"min_order":1,"max_order":10,"workers":65}`, wantCode: "BAD_REQUEST"},
+		{name: "zero min order", body: `{"samples":10,"max_order":10}`, wantCode: codeInvalidItemsOrdered},
+		{name: "max order above the catalog's", body: `{"samples":10,"min_order":1,"max_order":3000000}`, wantCode: codeInvalidItemsOrdered},
+	}
+
+	srv := newTestHandler(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := postSimulate(t, srv, tt.body)
+			if res.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400; body %s", res.Code, res.Body.String())
+			}
+			var body errorResponse
+			if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if body.Error.Code != tt.wantCode {
+				t.Fatalf("code = %q, want %q", body.Error.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestSimulateEndpoint_Cancelled(t *testing.T) {
+	srv := newTestHandler(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/api/optimize/simulate", bytes.NewBufferString(`{"samples":100,"min_order":1,"max_order":20000,"seed":1}`))
+	res := httptest.NewRecorder()
+	srv.ServeHTTP(res, req)
+
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503: %s", res.Code, res.Body.String())
+	}
+}
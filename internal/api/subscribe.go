@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"gymshark/internal/service"
+)
+
+const (
+	// subscribeWriteWait bounds each write to a subscriber.
+	subscribeWriteWait = 10 * time.Second
+	// subscribePongWait is how long a subscriber may stay silent before it is
+	// dropped; pings are sent well within it.
+	subscribePongWait   = 60 * time.Second
+	subscribePingPeriod = subscribePongWait * 9 / 10
+)
+
+// handleSubscribe upgrades to a WebSocket and pushes the pack sizes as a
+// packSizesPayload: the current ones on connect, then after every update.
+// Subscribers only receive; the connection closes when the client leaves.
+func (h *handler) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	packSizeService, err := service.GetPackSizeService()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "unable to initialize pack sizes")
+		return
+	}
+
+	updates, unsubscribe, err := service.SubscribeCatalog()
+	if err != nil {
+		if errors.Is(err, service.ErrTooManySubscribers) {
+			writeErrorFor(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "unable to subscribe")
+		return
+	}
+	defer unsubscribe()
+
+	upgrader := websocket.Upgrader{CheckOrigin: h.checkSubscribeOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already answered the client.
+		return
+	}
+	defer conn.Close()
+
+	// The read loop handles pongs and close frames and notices disconnects.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = conn.SetReadDeadline(time.Now().Add(subscribePongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(subscribePongWait))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(subscribePingPeriod)
+	defer ping.Stop()
+
+	if err := writeSubscriberCatalog(conn, packSizeService.GetPackSizes()); err != nil {
+		return
+	}
+	for {
+		select {
+		case <-done:
+			return
+		case packSizes := <-updates:
+			if err := writeSubscriberCatalog(conn, packSizes); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(subscribeWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeSubscriberCatalog(conn *websocket.Conn, packSizes []int) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(subscribeWriteWait)); err != nil {
+		return err
+	}
+	return conn.WriteJSON(packSizesPayload{PackSizes: packSizes})
+}
+
+// checkSubscribeOrigin accepts same-origin connections, clients sending no
+// Origin, and the origins CORS_ALLOWED_ORIGINS lists explicitly. Browsers send
+// no preflight for WebSocket handshakes, so this is the only guard against
+// other sites opening a subscription: a "*" setting does not extend to it.
+func (h *handler) checkSubscribeOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(h.config.cors.allowedOrigins, origin) {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func dialSubscribe(t *testing.T, srv *httptest.Server, header http.Header) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/pack-sizes/subscribe"
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+func readSubscribedCatalog(t *testing.T, conn *websocket.Conn) []int {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var payload packSizesPayload
+	if err := conn.ReadJSON(&payload); err != nil {
+		t.Fatalf("read push: %v", err)
+	}
+	return payload.PackSizes
+}
+
+func TestSubscribeEndpoint_PushesCatalogChanges(t *testing.T) {
+	srv := httptest.NewServer(newTestHandler(t))
+	defer srv.Close()
+
+	conn, _, err := dialSubscribe(t, srv, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	if got := readSubscribedCatalog(t, conn); !reflect.DeepEqual(got, []int{5000, 2000, 1000, 500, 250}) {
+		t.Fatalf("initial catalog = %v", got)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL+"/api/pack-sizes", bytes.NewBufferString(`{"pack_sizes":[23,31,53]}`))
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("update pack sizes: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("update status = %d, want 200", res.StatusCode)
+	}
+
+	if got := readSubscribedCatalog(t, conn); !reflect.DeepEqual(got, []int{53, 31, 23}) {
+		t.Fatalf("pushed catalog = %v, want [53 31 23]", got)
+	}
+}
+
+func TestSubscribeEndpoint_Origin(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins string
+		origin  string
+		// sameOrigin sends the test server's own URL as Origin.
+		sameOrigin bool
+		allowed    bool
+	}{
+		{name: "foreign origin", origin: "https://evil.example"},
+		{name: "wildcard does not apply", origins: "*", origin: "https://evil.example"},
+		{name: "listed origin", origins: "https://dash.example", origin: "https://dash.example", allowed: true},
+		{name: "unlisted origin", origins: "https://dash.example", origin: "https://evil.example"},
+		{name: "same origin", origins: "*", sameOrigin: true, allowed: true},
+		{name: "no origin", allowed: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("CORS_ALLOWED_ORIGINS", tc.origins)
+			srv := httptest.NewServer(newTestHandler(t))
+			defer srv.Close()
+
+			header := http.Header{}
+			if tc.origin != "" {
+				header.Set("Origin", tc.origin)
+			}
+			if tc.sameOrigin {
+				header.Set("Origin", srv.URL)
+			}
+			conn, res, err := dialSubscribe(t, srv, header)
+			if !tc.allowed {
+				if err == nil {
+					conn.Close()
+					t.Fatal("expected the handshake to fail")
+				}
+				if res == nil || res.StatusCode != http.StatusForbidden {
+					t.Fatalf("handshake response = %v, want 403", res)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+			readSubscribedCatalog(t, conn)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    slog.Level
+		wantErr bool
+	}{
+		{raw: "", want: slog.LevelInfo},
+		{raw: "info", want: slog.LevelInfo},
+		{raw: "DEBUG", want: slog.LevelDebug},
+		{raw: "warn", want: slog.LevelWarn},
+		{raw: "error", want: slog.LevelError},
+		{raw: "verbose", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.raw, func(t *testing.T) {
+			got, err := ParseLevel(tc.raw)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLevel returned error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("ParseLevel(%q) = %v, want %v", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNew_FiltersBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelWarn)
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.Warn("warn message")
+	logger.Error("error message")
+
+	out := buf.String()
+	for _, msg := range []string{"debug message", "info message"} {
+		if strings.Contains(out, msg) {
+			t.Fatalf("output contains %q below warn level:\n%s", msg, out)
+		}
+	}
+	for _, msg := range []string{"warn message", "error message"} {
+		if !strings.Contains(out, msg) {
+			t.Fatalf("output missing %q:\n%s", msg, out)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// maxAlternativeNodes bounds the search nodes OptimizeN visits, so catalogs
+// with many combinations per total (e.g. small sizes) stay cheap.
+const maxAlternativeNodes = 1_000_000
+
+var ErrInvalidAlternatives = errors.New("number of plans must be greater than zero")
+
+// OptimizeN returns up to n distinct plans for itemsOrdered, sorted by
+// overfill then pack count. The first plan is the one OptimizeWith returns;
+// the others are further breakdowns reconstructed from the same DP table,
+// e.g. the same total with a different pack mix or a larger total.
+//
+// n is limited by LimitAlternatives. Totals are searched up to the table's
+// fulfillment window (itemsOrdered plus the largest pack minus one), so fewer
+// than n plans come back when the window holds fewer breakdowns, or when the
+// search budget runs out; the plans returned are still the best in order.
+func OptimizeN(itemsOrdered int, packSizes []int, n int) ([]Plan, error) {
+	if n <= 0 {
+		return nil, ErrInvalidAlternatives
+	}
+	n, err := LimitAlternatives(n)
+	if err != nil {
+		return nil, err
+	}
+
+	best, err := OptimizeWith(itemsOrdered, packSizes)
+	if err != nil {
+		return nil, err
+	}
+	plans := []Plan{best}
+	if n == 1 {
+		return plans, nil
+	}
+
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return nil, err
+	}
+	_, table, _, err := computePlan(context.Background(), itemsOrdered, normalized, maxTableEntries, false)
+	if err != nil {
+		return nil, err
+	}
+
+	search := alternativeSearch{
+		table:  &table,
+		counts: make([]int, len(normalized)),
+		budget: maxAlternativeNodes,
+	}
+	limit := min(itemsOrdered+normalized[0]-1, table.fulfillmentLimit)
+	for total := best.TotalItems; total <= limit && len(plans) < n && search.budget > 0; total++ {
+		if table.minPacks[total] == table.unreachablePacks {
+			continue
+		}
+		// Every total holds at most total/smallest packs.
+		maxPacks := total / normalized[len(normalized)-1]
+		for packs := table.minPacks[total]; packs <= maxPacks && len(plans) < n && search.budget > 0; packs++ {
+			search.found = search.found[:0]
+			search.collect(0, total, packs, n)
+			for _, breakdown := range search.found {
+				if len(plans) == n {
+					break
+				}
+				if slices.Equal(breakdown, best.Packs) {
+					continue
+				}
+				// An alternative has no DP path; its smallest pack stands
+				// in as the last one added.
+				plan := Plan{
+					ItemsOrdered: itemsOrdered,
+					TotalItems:   total,
+					TotalPacks:   packs,
+					Packs:        breakdown,
+					DrivingSize:  breakdown[len(breakdown)-1].Size,
+				}
+				setOverfillMetrics(&plan)
+				plans = append(plans, plan)
+			}
+		}
+	}
+	return plans, nil
+}
+
+// alternativeSearch enumerates breakdowns of a total with an exact pack count,
+// largest sizes first, pruning with the table's minimum pack counts.
+type alternativeSearch struct {
+	table  *packingTable
+	counts []int
+	found  [][]PackBreakdown
+	budget int
+}
+
+// collect assigns counts to sortedPackSizes[index:] so they sum to remaining
+// items in exactly packs packs, appending up to n breakdowns to found.
+func (s *alternativeSearch) collect(index, remaining, packs, n int) {
+	if len(s.found) == n || s.budget <= 0 {
+		return
+	}
+	s.budget--
+
+	sizes := s.table.sortedPackSizes
+	if remaining == 0 {
+		if packs == 0 {
+			s.found = append(s.found, s.breakdown())
+		}
+		return
+	}
+	// The table's minimum over all sizes is a lower bound for any suffix.
+	if index == len(sizes) || s.table.minPacks[remaining] == s.table.unreachablePacks || s.table.minPacks[remaining] > packs {
+		return
+	}
+
+	size := sizes[index]
+	for count := min(remaining/size, packs); count >= 0; count-- {
+		s.counts[index] = count
+		s.collect(index+1, remaining-count*size, packs-count, n)
+	}
+	s.counts[index] = 0
+}
+
+func (s *alternativeSearch) breakdown() []PackBreakdown {
+	var breakdown []PackBreakdown
+	for i, count := range s.counts {
+		if count > 0 {
+			breakdown = append(breakdown, PackBreakdown{Size: s.table.sortedPackSizes[i], Count: count})
+		}
+	}
+	return breakdown
+}
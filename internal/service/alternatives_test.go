@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeN(t *testing.T) {
+	plans, err := OptimizeN(500, []int{250, 500, 1000}, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][]PackBreakdown{
+		{{Size: 500, Count: 1}},
+		{{Size: 250, Count: 2}},
+		{{Size: 500, Count: 1}, {Size: 250, Count: 1}},
+		{{Size: 250, Count: 3}},
+	}
+	if len(plans) != len(want) {
+		t.Fatalf("expected %d plans, got %d: %+v", len(want), len(plans), plans)
+	}
+	for i, plan := range plans {
+		if !reflect.DeepEqual(plan.Packs, want[i]) {
+			t.Fatalf("plan %d: expected packs %v, got %v", i, want[i], plan.Packs)
+		}
+		if plan.TotalPacks != TotalPhysicalPacks(plan.Packs) {
+			t.Fatalf("plan %d: total packs %d does not match %v", i, plan.TotalPacks, plan.Packs)
+		}
+	}
+}
+
+func TestOptimizeNFirstMatchesOptimize(t *testing.T) {
+	sizes := []int{23, 31, 53}
+	for _, ordered := range []int{1, 263, 500_000} {
+		want, err := OptimizeWith(ordered, sizes)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		plans, err := OptimizeN(ordered, sizes, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(plans[0], want) {
+			t.Fatalf("order %d: expected first plan %+v, got %+v", ordered, want, plans[0])
+		}
+	}
+}
+
+func TestOptimizeNSortedAndUnique(t *testing.T) {
+	plans, err := OptimizeN(263, []int{23, 31, 53}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plans) != 10 {
+		t.Fatalf("expected 10 plans, got %d", len(plans))
+	}
+
+	for i, plan := range plans {
+		total := 0
+		for _, pack := range plan.Packs {
+			total += pack.Size * pack.Count
+		}
+		if total != plan.TotalItems || plan.TotalItems < 263 {
+			t.Fatalf("plan %d: packs %v do not fulfill total %d", i, plan.Packs, plan.TotalItems)
+		}
+		for _, other := range plans[:i] {
+			if reflect.DeepEqual(other.Packs, plan.Packs) {
+				t.Fatalf("plan %d repeats packs %v", i, plan.Packs)
+			}
+		}
+		if i == 0 {
+			continue
+		}
+		prev := plans[i-1]
+		if plan.Overfill < prev.Overfill || (plan.Overfill == prev.Overfill && plan.TotalPacks < prev.TotalPacks) {
+			t.Fatalf("plan %d (%d overfill, %d packs) sorts before plan %d (%d overfill, %d packs)",
+				i, plan.Overfill, plan.TotalPacks, i-1, prev.Overfill, prev.TotalPacks)
+		}
+	}
+}
+
+func TestOptimizeNLimits(t *testing.T) {
+	t.Run("non-positive n", func(t *testing.T) {
+		if _, err := OptimizeN(500, []int{250, 500}, 0); !errors.Is(err, ErrInvalidAlternatives) {
+			t.Fatalf("expected ErrInvalidAlternatives, got %v", err)
+		}
+	})
+
+	t.Run("clamped to max alternatives", func(t *testing.T) {
+		setTestConfig(t, func(cfg *Config) { cfg.MaxAlternatives = 2 })
+		plans, err := OptimizeN(500, []int{250, 500}, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(plans) != 2 {
+			t.Fatalf("expected 2 plans, got %d", len(plans))
+		}
+	})
+
+	t.Run("rejected above max alternatives", func(t *testing.T) {
+		setTestConfig(t, func(cfg *Config) {
+			cfg.MaxAlternatives = 2
+			cfg.AlternativesPolicy = AlternativesReject
+		})
+		if _, err := OptimizeN(500, []int{250, 500}, 5); !errors.Is(err, ErrTooManyAlternatives) {
+			t.Fatalf("expected ErrTooManyAlternatives, got %v", err)
+		}
+	})
+
+	t.Run("window holds fewer plans", func(t *testing.T) {
+		plans, err := OptimizeN(500, []int{500}, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(plans) != 1 {
+			t.Fatalf("expected 1 plan, got %d", len(plans))
+		}
+	})
+}
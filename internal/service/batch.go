@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// OptimizeBatch optimizes every order against packSizes, building one DP
+// table for the largest order and serving every order from it. Totals in the
+// table do not depend on its limit, so each plan is identical to what
+// OptimizeWith returns for that order, at the cost of a single table build.
+// Divisible catalogs take divisiblePlan without a table, as OptimizeWith
+// does. An invalid order fails the whole batch.
+func OptimizeBatch(orders []int, packSizes []int) ([]Plan, error) {
+	return OptimizeBatchContext(context.Background(), orders, packSizes)
+}
+
+// OptimizeBatchContext is OptimizeBatch with cancellation of the table
+// build, like OptimizeContext.
+func OptimizeBatchContext(ctx context.Context, orders []int, packSizes []int) ([]Plan, error) {
+	if len(orders) == 0 {
+		return []Plan{}, nil
+	}
+	for i, order := range orders {
+		if order <= 0 || order > maxInt32Value {
+			return nil, fmt.Errorf("orders[%d]: %w", i, ErrInvalidItemsOrdered)
+		}
+	}
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	planOrder, err := batchPlanner(ctx, slices.Max(orders), normalized)
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]Plan, len(orders))
+	for i, order := range orders {
+		plan, err := planOrder(order)
+		if err != nil {
+			return nil, fmt.Errorf("orders[%d]: %w", i, err)
+		}
+		plans[i] = plan
+	}
+	return plans, nil
+}
+
+// batchPlanner returns a function planning any order up to largest against
+// sortedPackSizes, taking the path optimize takes for largest so plans and
+// their Algorithm match. It builds (or loads) one table and only reads it
+// afterwards, so the function is safe for concurrent use.
+func batchPlanner(ctx context.Context, largest int, sortedPackSizes []int) (func(int) (Plan, error), error) {
+	if useDivisiblePlan(largest, sortedPackSizes, maxTableEntries) {
+		return func(order int) (Plan, error) {
+			plan := divisiblePlan(order, sortedPackSizes)
+			setOverfillMetrics(&plan)
+			setPlanStatus(&plan)
+			return plan, nil
+		}, nil
+	}
+
+	table, cached := cachedTableFor(largest, sortedPackSizes)
+	if !cached {
+		var err error
+		table, err = newPackingTable(largest, sortedPackSizes)
+		if err != nil {
+			return nil, err
+		}
+		if err := table.buildOptimalPackingTableContext(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(order int) (Plan, error) {
+		// forOrder copies the table header; the DP slices are only read.
+		orderTable := table.forOrder(order)
+		total := orderTable.chooseFulfillmentTotal()
+		plan, err := orderTable.shipment(order, total)
+		if err == nil {
+			err = orderTable.verifyBreakdown(total, plan.Packs)
+		}
+		if err != nil {
+			return Plan{}, err
+		}
+		setOverfillMetrics(&plan)
+		setPlanStatus(&plan)
+		return plan, nil
+	}, nil
+}
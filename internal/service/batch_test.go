@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeBatchMatchesOptimizeWith(t *testing.T) {
+	tests := []struct {
+		name   string
+		sizes  []int
+		orders []int
+	}{
+		{name: "default catalog", sizes: []int{250, 500, 1000, 2000, 5000}, orders: []int{1, 250, 251, 501, 12001, 499}},
+		{name: "coprime sizes", sizes: []int{23, 31, 53}, orders: []int{500_000, 1, 263, 100, 52}},
+		{name: "single order", sizes: []int{3, 7}, orders: []int{11}},
+		{name: "divisible catalog", sizes: []int{250, 500, 1000}, orders: []int{1, 251, 12001, 999, 1000}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plans, err := OptimizeBatch(tc.orders, tc.sizes)
+			if err != nil {
+				t.Fatalf("OptimizeBatch returned error: %v", err)
+			}
+			if len(plans) != len(tc.orders) {
+				t.Fatalf("got %d plans, want %d", len(plans), len(tc.orders))
+			}
+			for i, order := range tc.orders {
+				want, err := OptimizeWith(order, tc.sizes)
+				if err != nil {
+					t.Fatalf("OptimizeWith returned error: %v", err)
+				}
+				if !reflect.DeepEqual(plans[i], want) {
+					t.Fatalf("order %d: plan = %+v, want %+v", order, plans[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestOptimizeBatchErrors(t *testing.T) {
+	if _, err := OptimizeBatch([]int{251, 0}, []int{250, 500}); !errors.Is(err, ErrInvalidItemsOrdered) {
+		t.Fatalf("error = %v, want ErrInvalidItemsOrdered", err)
+	}
+	if _, err := OptimizeBatch([]int{251}, nil); !errors.Is(err, ErrInvalidPackSizes) {
+		t.Fatalf("error = %v, want ErrInvalidPackSizes", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := OptimizeBatchContext(ctx, []int{4 * ctxCheckInterval}, []int{3, 7}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+
+	plans, err := OptimizeBatch(nil, []int{250})
+	if err != nil || len(plans) != 0 {
+		t.Fatalf("empty batch = %v, %v, want no plans", plans, err)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxCartLines bounds the lines accepted by OptimizeCart.
+const maxCartLines = 100
+
+// Cart objectives accepted by OptimizeCart.
+const (
+	CartSumOverfill     = "sum_overfill"
+	CartMinimaxOverfill = "minimax_overfill"
+)
+
+var (
+	ErrInvalidCart          = errors.New("cart must contain at least one line")
+	ErrInvalidCartObjective = errors.New("unsupported cart objective")
+)
+
+// CartLine is one line of a cart.
+type CartLine struct {
+	Label        string `json:"label"`
+	ItemsOrdered int    `json:"items_ordered"`
+}
+
+// CartLinePlan is the plan serving one cart line.
+type CartLinePlan struct {
+	Label string `json:"label"`
+	Plan  Plan   `json:"plan"`
+}
+
+// CartPlan holds per-line plans and the overfill metrics of the cart.
+type CartPlan struct {
+	Objective string         `json:"objective"`
+	Lines     []CartLinePlan `json:"lines"`
+	// ObjectiveValue is TotalOverfill or MaxOverfill, per Objective.
+	ObjectiveValue int `json:"objective_value"`
+	TotalOverfill  int `json:"total_overfill"`
+	MaxOverfill    int `json:"max_overfill"`
+	// WorstLine is the index of the first line with MaxOverfill.
+	WorstLine  int `json:"worst_line"`
+	TotalItems int `json:"total_items"`
+	TotalPacks int `json:"total_packs"`
+}
+
+// OptimizeCart optimizes every line of a cart against packSizes and scores
+// the cart by objective: the summed overfill of its lines, or the worst
+// single-line overfill.
+//
+// Every line ships in its own whole packs, so lines are independent and each
+// line's minimum-overfill plan minimizes both the sum and the maximum at once:
+// no trade-off between lines can lower either. The objective therefore picks
+// the reported ObjectiveValue; it never changes the plans. A joint formulation
+// would only differ if lines shared packs or a cart-wide constraint.
+func OptimizeCart(lines []CartLine, objective string, packSizes []int) (CartPlan, error) {
+	if objective == "" {
+		objective = CartSumOverfill
+	}
+	if objective != CartSumOverfill && objective != CartMinimaxOverfill {
+		return CartPlan{}, fmt.Errorf("%w: %q (want %q or %q)", ErrInvalidCartObjective, objective, CartSumOverfill, CartMinimaxOverfill)
+	}
+	if len(lines) == 0 {
+		return CartPlan{}, ErrInvalidCart
+	}
+	if len(lines) > maxCartLines {
+		return CartPlan{}, fmt.Errorf("%w: %d lines exceeds max %d", ErrInvalidCart, len(lines), maxCartLines)
+	}
+
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return CartPlan{}, err
+	}
+
+	result := CartPlan{Objective: objective, Lines: make([]CartLinePlan, 0, len(lines))}
+	for i, line := range lines {
+		plan, err := OptimizeWith(line.ItemsOrdered, normalized)
+		if err != nil {
+			return CartPlan{}, fmt.Errorf("lines[%d]: %w", i, err)
+		}
+
+		result.Lines = append(result.Lines, CartLinePlan{Label: line.Label, Plan: plan})
+		result.TotalOverfill += plan.Overfill
+		result.TotalItems += plan.TotalItems
+		result.TotalPacks += plan.TotalPacks
+		if plan.Overfill > result.MaxOverfill {
+			result.MaxOverfill = plan.Overfill
+			result.WorstLine = i
+		}
+	}
+
+	result.ObjectiveValue = result.TotalOverfill
+	if objective == CartMinimaxOverfill {
+		result.ObjectiveValue = result.MaxOverfill
+	}
+	return result, nil
+}
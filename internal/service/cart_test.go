@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeCart_MinimaxVersusSum(t *testing.T) {
+	lines := []CartLine{
+		{Label: "tees", ItemsOrdered: 251},
+		{Label: "socks", ItemsOrdered: 490},
+	}
+
+	sum, err := OptimizeCart(lines, CartSumOverfill, []int{250, 500})
+	if err != nil {
+		t.Fatalf("OptimizeCart(sum) returned error: %v", err)
+	}
+	minimax, err := OptimizeCart(lines, CartMinimaxOverfill, []int{250, 500})
+	if err != nil {
+		t.Fatalf("OptimizeCart(minimax) returned error: %v", err)
+	}
+
+	// 251 ships 500 (overfill 249) and 490 ships 500 (overfill 10).
+	if sum.ObjectiveValue != 259 || minimax.ObjectiveValue != 249 {
+		t.Fatalf("objective values = %d (sum), %d (minimax), want 259 and 249", sum.ObjectiveValue, minimax.ObjectiveValue)
+	}
+	if minimax.WorstLine != 0 || minimax.TotalOverfill != 259 || minimax.MaxOverfill != 249 {
+		t.Fatalf("unexpected minimax metrics: %+v", minimax)
+	}
+	// Lines are independent, so both objectives ship the same plans.
+	if !reflect.DeepEqual(sum.Lines, minimax.Lines) {
+		t.Fatalf("lines differ between objectives:\n%+v\n%+v", sum.Lines, minimax.Lines)
+	}
+}
+
+func TestOptimizeCart_DefaultsToSum(t *testing.T) {
+	got, err := OptimizeCart([]CartLine{{ItemsOrdered: 1}}, "", []int{250})
+	if err != nil {
+		t.Fatalf("OptimizeCart returned error: %v", err)
+	}
+	if got.Objective != CartSumOverfill || got.ObjectiveValue != 249 {
+		t.Fatalf("unexpected cart: %+v", got)
+	}
+}
+
+func TestOptimizeCart_InvalidInput(t *testing.T) {
+	if _, err := OptimizeCart(nil, "", []int{250}); !errors.Is(err, ErrInvalidCart) {
+		t.Fatalf("expected ErrInvalidCart, got %v", err)
+	}
+	if _, err := OptimizeCart(make([]CartLine, maxCartLines+1), "", []int{250}); !errors.Is(err, ErrInvalidCart) {
+		t.Fatalf("expected ErrInvalidCart, got %v", err)
+	}
+	if _, err := OptimizeCart([]CartLine{{ItemsOrdered: 1}}, "median", []int{250}); !errors.Is(err, ErrInvalidCartObjective) {
+		t.Fatalf("expected ErrInvalidCartObjective, got %v", err)
+	}
+	if _, err := OptimizeCart([]CartLine{{ItemsOrdered: 0}}, "", []int{250}); !errors.Is(err, ErrInvalidItemsOrdered) {
+		t.Fatalf("expected ErrInvalidItemsOrdered, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var ErrCatalogReloading = errors.New("pack size catalog is reloading")
+
+// catalogReloads counts reloads in progress; overlapping reloads nest.
+var catalogReloads atomic.Int32
+
+// BeginCatalogReload marks a catalog reload as in progress until the returned
+// function is called; meanwhile optimizations against the configured catalog
+// fail fast with ErrCatalogReloading instead of blocking or reading a catalog
+// that is being replaced. Loaders should bracket only the swap itself to keep
+// the window tiny.
+//
+// In-memory updates (SetPackSizes) swap atomically under the service lock and
+// never need this; it is meant for loaders that read a catalog from a file or
+// a remote store in steps.
+func BeginCatalogReload() (end func()) {
+	catalogReloads.Add(1)
+
+	var ended atomic.Bool
+	return func() {
+		if ended.CompareAndSwap(false, true) {
+			catalogReloads.Add(-1)
+		}
+	}
+}
+
+// CatalogReloading reports whether a catalog reload is in progress.
+func CatalogReloading() bool {
+	return catalogReloads.Load() > 0
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBeginCatalogReload_RejectsOptimizations(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	end := BeginCatalogReload()
+	if !CatalogReloading() {
+		t.Fatal("CatalogReloading() = false during a reload")
+	}
+	if _, err := OptimizeWithOptions(context.Background(), 251, Options{}); !errors.Is(err, ErrCatalogReloading) {
+		t.Fatalf("OptimizeWithOptions error = %v, want ErrCatalogReloading", err)
+	}
+	if _, err := OptimizeIdempotent(context.Background(), 251, Options{}); !errors.Is(err, ErrCatalogReloading) {
+		t.Fatalf("OptimizeIdempotent error = %v, want ErrCatalogReloading", err)
+	}
+	// Explicit catalogs do not read the configured one.
+	if _, err := OptimizeWith(251, []int{250}); err != nil {
+		t.Fatalf("OptimizeWith returned error during a reload: %v", err)
+	}
+
+	end()
+	end()
+	if CatalogReloading() {
+		t.Fatal("CatalogReloading() = true after the reload ended")
+	}
+	if _, err := OptimizeWithOptions(context.Background(), 251, Options{}); err != nil {
+		t.Fatalf("OptimizeWithOptions returned error after the reload: %v", err)
+	}
+}
+
+func TestBeginCatalogReload_Nests(t *testing.T) {
+	endFirst := BeginCatalogReload()
+	endSecond := BeginCatalogReload()
+
+	endFirst()
+	if !CatalogReloading() {
+		t.Fatal("CatalogReloading() = false while a second reload is in progress")
+	}
+	endSecond()
+	if CatalogReloading() {
+		t.Fatal("CatalogReloading() = true after both reloads ended")
+	}
+}
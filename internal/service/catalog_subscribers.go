@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"sync"
+)
+
+// maxCatalogSubscribers bounds the live catalog subscriptions.
+const maxCatalogSubscribers = 100
+
+var ErrTooManySubscribers = errors.New("too many catalog subscribers")
+
+var catalogSubscribers = struct {
+	mu   sync.Mutex
+	subs map[chan []int]struct{}
+}{subs: make(map[chan []int]struct{})}
+
+// SubscribeCatalog returns a channel receiving the pack sizes after every
+// successful update, and a function ending the subscription. A slow
+// subscriber only ever misses intermediate catalogs: the channel holds the
+// latest one. Received slices are shared between subscribers and must not be
+// modified.
+func SubscribeCatalog() (<-chan []int, func(), error) {
+	catalogSubscribers.mu.Lock()
+	defer catalogSubscribers.mu.Unlock()
+
+	if len(catalogSubscribers.subs) >= maxCatalogSubscribers {
+		return nil, nil, ErrTooManySubscribers
+	}
+
+	ch := make(chan []int, 1)
+	catalogSubscribers.subs[ch] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			catalogSubscribers.mu.Lock()
+			defer catalogSubscribers.mu.Unlock()
+			delete(catalogSubscribers.subs, ch)
+		})
+	}
+	return ch, unsubscribe, nil
+}
+
+// broadcastCatalog is the OnChange listener feeding SubscribeCatalog. It never
+// blocks: a pending catalog a subscriber has not read yet is replaced.
+func broadcastCatalog(packSizes []int) {
+	catalogSubscribers.mu.Lock()
+	defer catalogSubscribers.mu.Unlock()
+
+	for ch := range catalogSubscribers.subs {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- packSizes
+	}
+}
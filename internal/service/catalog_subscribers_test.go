@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSubscribeCatalog_ReceivesUpdates(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	updates, unsubscribe, err := SubscribeCatalog()
+	if err != nil {
+		t.Fatalf("SubscribeCatalog returned error: %v", err)
+	}
+	defer unsubscribe()
+
+	setOptimizerPackSizes(t, []int{100, 300})
+	if got := <-updates; !reflect.DeepEqual(got, []int{300, 100}) {
+		t.Fatalf("update = %v, want [300 100]", got)
+	}
+}
+
+func TestSubscribeCatalog_KeepsOnlyLatestForSlowSubscribers(t *testing.T) {
+	updates, unsubscribe, err := SubscribeCatalog()
+	if err != nil {
+		t.Fatalf("SubscribeCatalog returned error: %v", err)
+	}
+	defer unsubscribe()
+
+	setOptimizerPackSizes(t, []int{100})
+	setOptimizerPackSizes(t, []int{200})
+	if got := <-updates; !reflect.DeepEqual(got, []int{200}) {
+		t.Fatalf("update = %v, want [200]", got)
+	}
+	select {
+	case got := <-updates:
+		t.Fatalf("unexpected extra update %v", got)
+	default:
+	}
+}
+
+func TestSubscribeCatalog_BoundsSubscribers(t *testing.T) {
+	var unsubscribes []func()
+	defer func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}()
+
+	for range maxCatalogSubscribers {
+		_, unsubscribe, err := SubscribeCatalog()
+		if err != nil {
+			t.Fatalf("SubscribeCatalog returned error: %v", err)
+		}
+		unsubscribes = append(unsubscribes, unsubscribe)
+	}
+	if _, _, err := SubscribeCatalog(); !errors.Is(err, ErrTooManySubscribers) {
+		t.Fatalf("expected ErrTooManySubscribers, got %v", err)
+	}
+
+	// Unsubscribing frees a slot, and calling it twice is harmless.
+	unsubscribes[0]()
+	unsubscribes[0]()
+	_, unsubscribe, err := SubscribeCatalog()
+	if err != nil {
+		t.Fatalf("SubscribeCatalog after unsubscribe returned error: %v", err)
+	}
+	unsubscribes = append(unsubscribes, unsubscribe)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"sort"
+)
+
+// Pack size validation rules reported by ValidatePackSizes.
+const (
+	RuleZero      = "zero"
+	RuleNegative  = "negative"
+	RuleOverMax   = "over_max"
+	RuleDuplicate = "duplicate"
+	RuleEmpty     = "empty"
+	RuleTooMany   = "too_many"
+)
+
+// PackSizeViolation is one pack size breaking a validation rule.
+type PackSizeViolation struct {
+	// Value is the offending pack size; it is nil for RuleEmpty and
+	// RuleTooMany.
+	Value *int   `json:"value,omitempty"`
+	Rule  string `json:"rule"`
+	// Limit is the maximum that was exceeded, for RuleOverMax and
+	// RuleTooMany.
+	Limit int `json:"limit,omitempty"`
+}
+
+// CatalogValidation is the collect-all result of validating a catalog.
+type CatalogValidation struct {
+	// Normalized holds the valid sizes found, deduplicated and sorted
+	// descending, as NormalizePackSizes would return them.
+	Normalized []int               `json:"normalized"`
+	Violations []PackSizeViolation `json:"violations"`
+
+	// strictDuplicates records Config.StrictDuplicates at validation time.
+	strictDuplicates bool
+}
+
+// Valid reports whether the catalog would be accepted. Duplicates are
+// dropped during normalization, so they alone never make it invalid unless
+// Config.StrictDuplicates is set.
+func (v CatalogValidation) Valid() bool {
+	for _, violation := range v.Violations {
+		if violation.Rule != RuleDuplicate || v.strictDuplicates {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidatePackSizes applies the rules of NormalizePackSizes to every pack
+// size instead of stopping at the first failure, so callers can report all
+// problems at once.
+func ValidatePackSizes(packSizes []int) CatalogValidation {
+	cfg := currentConfig()
+	maxPackSize := min(cfg.MaxPackSize, maxInt32Value)
+
+	result := CatalogValidation{
+		Normalized:       make([]int, 0, len(packSizes)),
+		Violations:       []PackSizeViolation{},
+		strictDuplicates: cfg.StrictDuplicates,
+	}
+	seen := make(map[int]struct{}, len(packSizes))
+	for _, size := range packSizes {
+		switch {
+		case size == 0:
+			result.Violations = append(result.Violations, PackSizeViolation{Value: &size, Rule: RuleZero})
+		case size < 0:
+			result.Violations = append(result.Violations, PackSizeViolation{Value: &size, Rule: RuleNegative})
+		case size > maxPackSize:
+			result.Violations = append(result.Violations, PackSizeViolation{Value: &size, Rule: RuleOverMax, Limit: maxPackSize})
+		default:
+			if _, duplicate := seen[size]; duplicate {
+				result.Violations = append(result.Violations, PackSizeViolation{Value: &size, Rule: RuleDuplicate})
+				continue
+			}
+			seen[size] = struct{}{}
+			result.Normalized = append(result.Normalized, size)
+		}
+	}
+
+	if len(result.Normalized) == 0 {
+		result.Violations = append(result.Violations, PackSizeViolation{Rule: RuleEmpty})
+	}
+	if len(result.Normalized) > cfg.MaxPackSizes {
+		result.Violations = append(result.Violations, PackSizeViolation{Rule: RuleTooMany, Limit: cfg.MaxPackSizes})
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(result.Normalized)))
+	return result
+}
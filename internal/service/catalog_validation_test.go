@@ -0,0 +1,60 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestValidatePackSizes_CollectsAllViolations(t *testing.T) {
+	setTestConfig(t, func(cfg *Config) { cfg.MaxPackSize = 10_000 })
+
+	got := ValidatePackSizes([]int{250, 0, -5, 20_000, 500, 250})
+
+	want := CatalogValidation{
+		Normalized: []int{500, 250},
+		Violations: []PackSizeViolation{
+			{Value: intPtr(0), Rule: RuleZero},
+			{Value: intPtr(-5), Rule: RuleNegative},
+			{Value: intPtr(20_000), Rule: RuleOverMax, Limit: 10_000},
+			{Value: intPtr(250), Rule: RuleDuplicate},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ValidatePackSizes() = %+v, want %+v", got, want)
+	}
+	if got.Valid() {
+		t.Fatal("Valid() = true, want false")
+	}
+}
+
+func TestValidatePackSizes_Valid(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []int
+		want      bool
+	}{
+		{name: "valid", packSizes: []int{250, 500}, want: true},
+		{name: "duplicates only dropped", packSizes: []int{250, 250}, want: true},
+		{name: "empty", packSizes: nil, want: false},
+		{name: "all invalid", packSizes: []int{0, -1}, want: false},
+		{name: "too many distinct sizes", packSizes: []int{10, 20, 30, 40}, want: false},
+		{name: "duplicates not counted", packSizes: []int{10, 20, 30, 30}, want: true},
+	}
+	setTestConfig(t, func(cfg *Config) { cfg.MaxPackSizes = 3 })
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ValidatePackSizes(tc.packSizes)
+			if got.Valid() != tc.want {
+				t.Fatalf("Valid() = %t, want %t (%+v)", got.Valid(), tc.want, got)
+			}
+			if _, err := NormalizePackSizes(tc.packSizes); (err == nil) != tc.want {
+				t.Fatalf("NormalizePackSizes error = %v, disagrees with Valid() = %t", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	defaultMaxPackSize     = 1_000_000
+	defaultMaxPackSizes    = 50
+	defaultMaxAlternatives = 10
+)
+
+// Policies for alternative plan requests above Config.MaxAlternatives.
+const (
+	AlternativesClamp  = "clamp"
+	AlternativesReject = "reject"
+)
+
+var ErrTooManyAlternatives = errors.New("too many alternative plans requested")
+
+// Config holds operator-tunable business limits. They are separate from the
+// int32 overflow guards, which always apply.
+type Config struct {
+	// MaxPackSize is the largest pack size NormalizePackSizes accepts.
+	MaxPackSize int
+	// MaxPackSizes is how many distinct pack sizes NormalizePackSizes
+	// accepts, counted after duplicates are dropped.
+	MaxPackSizes int
+	// StrictDuplicates makes NormalizePackSizes reject duplicated sizes
+	// instead of silently dropping them.
+	StrictDuplicates bool
+	// WarmupCeiling is the highest total covered by the table precomputed in
+	// the background after a catalog change; zero disables the warm-up.
+	WarmupCeiling int
+	// ResultCacheSize is how many distinct orders' plans are cached per
+	// process; zero disables the result cache.
+	ResultCacheSize int
+	// NormalizeCacheSize is how many distinct inputs' NormalizePackSizes
+	// results are cached per process; zero disables the cache.
+	NormalizeCacheSize int
+	// PrimeOrders are order quantities whose plans are computed and cached at
+	// startup and after every catalog change. They need the result cache.
+	PrimeOrders []int
+	// CumulativePackUsage keeps pack usage counters across catalog changes
+	// instead of resetting them on every change.
+	CumulativePackUsage bool
+	// MaxAlternatives bounds how many alternative plans one request may
+	// enumerate; AlternativesPolicy decides whether larger requests are
+	// clamped to it or rejected (see LimitAlternatives).
+	MaxAlternatives    int
+	AlternativesPolicy string
+	// DefaultObjective is the objective of optimizations that set neither
+	// Options.Objective nor Options.SwitchPenalty; empty means
+	// ObjectiveFewestPacks.
+	DefaultObjective string
+	// SimulationWorkers is how many goroutines SimulateDemand optimizes
+	// samples with when a simulation sets none; zero means GOMAXPROCS.
+	SimulationWorkers int
+}
+
+var activeConfig atomic.Pointer[Config]
+
+// DefaultConfig returns the limits used when nothing is configured.
+func DefaultConfig() Config {
+	return Config{
+		MaxPackSize:        defaultMaxPackSize,
+		MaxPackSizes:       defaultMaxPackSizes,
+		MaxAlternatives:    defaultMaxAlternatives,
+		AlternativesPolicy: AlternativesClamp,
+		NormalizeCacheSize: defaultNormalizeCacheSize,
+	}
+}
+
+// ConfigFromEnv builds a Config from environment variables, using defaults
+// for unset values:
+//   - MAX_PACK_SIZE: largest accepted pack size.
+//   - MAX_PACK_SIZES: most distinct pack sizes in a catalog.
+//   - STRICT_DUPLICATES: reject duplicated pack sizes instead of dropping them.
+//   - WARMUP_CEILING: highest total precomputed after a catalog change (0 disables).
+//   - RESULT_CACHE_SIZE: distinct orders whose plans are cached (0 disables).
+//   - NORMALIZE_CACHE_SIZE: distinct pack size inputs whose normalization is cached (0 disables).
+//   - PRIME_ORDERS: comma-separated order quantities to precompute.
+//   - PACK_USAGE_CUMULATIVE: keep pack usage counters across catalog changes.
+//   - MAX_ALTERNATIVES: most alternative plans one request may enumerate.
+//   - ALTERNATIVES_POLICY: "clamp" or "reject" requests above MAX_ALTERNATIVES.
+//   - DEFAULT_OBJECTIVE: "fewest_packs" or "fewest_sizes" when requests set none.
+//   - SIMULATION_WORKERS: default simulation concurrency (0 uses GOMAXPROCS).
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+
+	if err := envInt("MAX_PACK_SIZE", &cfg.MaxPackSize); err != nil {
+		return Config{}, err
+	}
+	if err := envInt("MAX_PACK_SIZES", &cfg.MaxPackSizes); err != nil {
+		return Config{}, err
+	}
+	if err := envBool("STRICT_DUPLICATES", &cfg.StrictDuplicates); err != nil {
+		return Config{}, err
+	}
+	if err := envInt("WARMUP_CEILING", &cfg.WarmupCeiling); err != nil {
+		return Config{}, err
+	}
+	if err := envInt("RESULT_CACHE_SIZE", &cfg.ResultCacheSize); err != nil {
+		return Config{}, err
+	}
+	if err := envInt("NORMALIZE_CACHE_SIZE", &cfg.NormalizeCacheSize); err != nil {
+		return Config{}, err
+	}
+	if err := envIntList("PRIME_ORDERS", &cfg.PrimeOrders); err != nil {
+		return Config{}, err
+	}
+	if err := envBool("PACK_USAGE_CUMULATIVE", &cfg.CumulativePackUsage); err != nil {
+		return Config{}, err
+	}
+	if err := envInt("MAX_ALTERNATIVES", &cfg.MaxAlternatives); err != nil {
+		return Config{}, err
+	}
+	if raw := os.Getenv("ALTERNATIVES_POLICY"); raw != "" {
+		cfg.AlternativesPolicy = raw
+	}
+	cfg.DefaultObjective = os.Getenv("DEFAULT_OBJECTIVE")
+	if err := envInt("SIMULATION_WORKERS", &cfg.SimulationWorkers); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// SetConfig validates cfg and makes it the active configuration.
+func SetConfig(cfg Config) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	cfg.PrimeOrders = slices.Clone(cfg.PrimeOrders)
+	activeConfig.Store(&cfg)
+	return nil
+}
+
+func currentConfig() Config {
+	if cfg := activeConfig.Load(); cfg != nil {
+		return *cfg
+	}
+	return DefaultConfig()
+}
+
+func (c Config) validate() error {
+	if c.MaxPackSize <= 0 || c.MaxPackSize > maxInt32Value {
+		return fmt.Errorf("MAX_PACK_SIZE must be between 1 and %d, got %d", maxInt32Value, c.MaxPackSize)
+	}
+	if c.MaxPackSizes <= 0 {
+		return fmt.Errorf("MAX_PACK_SIZES must be greater than zero, got %d", c.MaxPackSizes)
+	}
+	if c.WarmupCeiling < 0 || c.WarmupCeiling+1 > maxTableEntries {
+		return fmt.Errorf("WARMUP_CEILING must be between 0 and %d, got %d", maxTableEntries-1, c.WarmupCeiling)
+	}
+	if c.ResultCacheSize < 0 {
+		return fmt.Errorf("RESULT_CACHE_SIZE must not be negative, got %d", c.ResultCacheSize)
+	}
+	if c.NormalizeCacheSize < 0 {
+		return fmt.Errorf("NORMALIZE_CACHE_SIZE must not be negative, got %d", c.NormalizeCacheSize)
+	}
+	for _, order := range c.PrimeOrders {
+		if order <= 0 || order > maxInt32Value {
+			return fmt.Errorf("PRIME_ORDERS entries must be between 1 and %d, got %d", maxInt32Value, order)
+		}
+	}
+	if len(c.PrimeOrders) > c.ResultCacheSize {
+		return fmt.Errorf("PRIME_ORDERS lists %d orders but RESULT_CACHE_SIZE is %d", len(c.PrimeOrders), c.ResultCacheSize)
+	}
+	if c.MaxAlternatives <= 0 {
+		return fmt.Errorf("MAX_ALTERNATIVES must be greater than zero, got %d", c.MaxAlternatives)
+	}
+	if c.AlternativesPolicy != AlternativesClamp && c.AlternativesPolicy != AlternativesReject {
+		return fmt.Errorf("ALTERNATIVES_POLICY must be %q or %q, got %q", AlternativesClamp, AlternativesReject, c.AlternativesPolicy)
+	}
+	if c.SimulationWorkers < 0 || c.SimulationWorkers > maxSimulationWorkers {
+		return fmt.Errorf("SIMULATION_WORKERS must be between 0 and %d, got %d", maxSimulationWorkers, c.SimulationWorkers)
+	}
+	if c.DefaultObjective != "" {
+		if err := checkObjective(c.DefaultObjective); err != nil {
+			return fmt.Errorf("DEFAULT_OBJECTIVE: %w", err)
+		}
+	}
+	return nil
+}
+
+// MaxPackSizes returns the configured Config.MaxPackSizes, so clients can
+// check a catalog before submitting it.
+func MaxPackSizes() int {
+	return currentConfig().MaxPackSizes
+}
+
+// LimitAlternatives applies Config.MaxAlternatives to a request for n
+// alternative plans: above the maximum, n is clamped to it or rejected with
+// ErrTooManyAlternatives, depending on Config.AlternativesPolicy.
+func LimitAlternatives(n int) (int, error) {
+	cfg := currentConfig()
+	if n <= cfg.MaxAlternatives {
+		return n, nil
+	}
+	if cfg.AlternativesPolicy == AlternativesReject {
+		return 0, fmt.Errorf("%w: %d exceeds max %d", ErrTooManyAlternatives, n, cfg.MaxAlternatives)
+	}
+	return cfg.MaxAlternatives, nil
+}
+
+// envInt overwrites *dst with the integer value of the named variable when it is set.
+func envInt(name string, dst *int) error {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", name, raw, err)
+	}
+
+	*dst = value
+	return nil
+}
+
+// envBool overwrites *dst with the boolean value of the named variable when it is set.
+func envBool(name string, dst *bool) error {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", name, raw, err)
+	}
+
+	*dst = value
+	return nil
+}
+
+// envIntList overwrites *dst with the comma-separated integers of the named
+// variable when it is set.
+func envIntList(name string, dst *[]int) error {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil
+	}
+
+	var values []int
+	for _, field := range strings.Split(raw, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return fmt.Errorf("invalid %s entry %q: %w", name, field, err)
+		}
+		values = append(values, value)
+	}
+
+	*dst = values
+	return nil
+}
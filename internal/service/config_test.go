@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// setTestConfig applies a modified default config for the duration of the test.
+func setTestConfig(t *testing.T, modify func(*Config)) {
+	t.Helper()
+
+	previous := currentConfig()
+	cfg := DefaultConfig()
+	modify(&cfg)
+	if err := SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig returned error: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := SetConfig(previous); err != nil {
+			t.Fatalf("restore config: %v", err)
+		}
+	})
+}
+
+func TestConfigFromEnv_Defaults(t *testing.T) {
+	t.Setenv("MAX_PACK_SIZE", "")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv returned error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Fatalf("ConfigFromEnv() = %+v, want %+v", cfg, DefaultConfig())
+	}
+}
+
+func TestConfigFromEnv_StrictDuplicates(t *testing.T) {
+	t.Setenv("STRICT_DUPLICATES", "true")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv returned error: %v", err)
+	}
+	if !cfg.StrictDuplicates {
+		t.Fatal("StrictDuplicates = false, want true")
+	}
+}
+
+func TestConfigFromEnv_MaxPackSize(t *testing.T) {
+	t.Setenv("MAX_PACK_SIZE", "10000")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv returned error: %v", err)
+	}
+	if cfg.MaxPackSize != 10000 {
+		t.Fatalf("MaxPackSize = %d, want 10000", cfg.MaxPackSize)
+	}
+
+	previous := currentConfig()
+	if err := SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = SetConfig(previous) })
+
+	_, err = NormalizePackSizes([]int{250, 50000})
+	if !errors.Is(err, ErrPackSizeTooLarge) {
+		t.Fatalf("expected ErrPackSizeTooLarge, got %v", err)
+	}
+
+	if _, err := NormalizePackSizes([]int{250, 10000}); err != nil {
+		t.Fatalf("expected pack size at the cap to be accepted, got %v", err)
+	}
+}
+
+func TestConfigFromEnv_InvalidMaxPackSize(t *testing.T) {
+	for _, raw := range []string{"abc", "0", "-1", "3000000000"} {
+		t.Run(raw, func(t *testing.T) {
+			t.Setenv("MAX_PACK_SIZE", raw)
+
+			if _, err := ConfigFromEnv(); err == nil {
+				t.Fatalf("expected error for MAX_PACK_SIZE=%q", raw)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv_PrimeOrders(t *testing.T) {
+	tests := []struct {
+		name      string
+		cacheSize string
+		orders    string
+		want      []int
+		wantErr   bool
+	}{
+		{name: "valid", cacheSize: "10", orders: "251, 501,12001", want: []int{251, 501, 12001}},
+		{name: "not a number", cacheSize: "10", orders: "251,abc", wantErr: true},
+		{name: "not positive", cacheSize: "10", orders: "251,0", wantErr: true},
+		{name: "cache too small", cacheSize: "1", orders: "251,501", wantErr: true},
+		{name: "cache disabled", cacheSize: "", orders: "251", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("RESULT_CACHE_SIZE", tc.cacheSize)
+			t.Setenv("PRIME_ORDERS", tc.orders)
+
+			cfg, err := ConfigFromEnv()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConfigFromEnv returned error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.PrimeOrders, tc.want) {
+				t.Fatalf("PrimeOrders = %v, want %v", cfg.PrimeOrders, tc.want)
+			}
+		})
+	}
+}
+
+func TestLimitAlternatives(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		n       int
+		want    int
+		wantErr bool
+	}{
+		{name: "clamp at max", policy: AlternativesClamp, n: 10, want: 10},
+		{name: "clamp above max", policy: AlternativesClamp, n: 11, want: 10},
+		{name: "reject at max", policy: AlternativesReject, n: 10, want: 10},
+		{name: "reject above max", policy: AlternativesReject, n: 11, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setTestConfig(t, func(cfg *Config) { cfg.AlternativesPolicy = tc.policy })
+
+			got, err := LimitAlternatives(tc.n)
+			if tc.wantErr {
+				if !errors.Is(err, ErrTooManyAlternatives) {
+					t.Fatalf("error = %v, want ErrTooManyAlternatives", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LimitAlternatives returned error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("LimitAlternatives(%d) = %d, want %d", tc.n, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv_AlternativesPolicy(t *testing.T) {
+	t.Setenv("MAX_ALTERNATIVES", "3")
+	t.Setenv("ALTERNATIVES_POLICY", "reject")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv returned error: %v", err)
+	}
+	if cfg.MaxAlternatives != 3 || cfg.AlternativesPolicy != AlternativesReject {
+		t.Fatalf("cfg = %+v, want max 3 with reject policy", cfg)
+	}
+
+	t.Setenv("ALTERNATIVES_POLICY", "drop")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
+
+func TestConfigFromEnv_MaxPackSizes(t *testing.T) {
+	t.Setenv("MAX_PACK_SIZES", "3")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv returned error: %v", err)
+	}
+	if cfg.MaxPackSizes != 3 {
+		t.Fatalf("MaxPackSizes = %d, want 3", cfg.MaxPackSizes)
+	}
+
+	for _, raw := range []string{"abc", "0", "-1"} {
+		t.Setenv("MAX_PACK_SIZES", raw)
+		if _, err := ConfigFromEnv(); err == nil {
+			t.Fatalf("expected error for MAX_PACK_SIZES=%q", raw)
+		}
+	}
+}
+
+func TestNormalizePackSizes_MaxPackSizes(t *testing.T) {
+	setTestConfig(t, func(cfg *Config) { cfg.MaxPackSizes = 3 })
+
+	// Duplicates are dropped before counting.
+	if _, err := NormalizePackSizes([]int{10, 20, 30, 30, 30}); err != nil {
+		t.Fatalf("expected 3 distinct sizes to be accepted, got %v", err)
+	}
+	if _, err := NormalizePackSizes([]int{10, 20, 30, 40}); !errors.Is(err, ErrTooManyPackSizes) {
+		t.Fatalf("expected ErrTooManyPackSizes, got %v", err)
+	}
+
+	svc, err := NewInMemoryPackSizeService([]int{10})
+	if err != nil {
+		t.Fatalf("NewInMemoryPackSizeService returned error: %v", err)
+	}
+	if err := svc.SetPackSizes([]int{10, 20, 30, 40}); !errors.Is(err, ErrTooManyPackSizes) {
+		t.Fatalf("SetPackSizes: expected ErrTooManyPackSizes, got %v", err)
+	}
+	if err := svc.AddPackSizes([]int{20, 30, 40}); !errors.Is(err, ErrTooManyPackSizes) {
+		t.Fatalf("AddPackSizes: expected ErrTooManyPackSizes, got %v", err)
+	}
+	if MaxPackSizes() != 3 {
+		t.Fatalf("MaxPackSizes() = %d, want 3", MaxPackSizes())
+	}
+}
+
+func TestConfigFromEnv_NormalizeCacheSize(t *testing.T) {
+	t.Setenv("NORMALIZE_CACHE_SIZE", "8")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv returned error: %v", err)
+	}
+	if cfg.NormalizeCacheSize != 8 {
+		t.Fatalf("NormalizeCacheSize = %d, want 8", cfg.NormalizeCacheSize)
+	}
+
+	t.Setenv("NORMALIZE_CACHE_SIZE", "-1")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatal("expected error for a negative cache size")
+	}
+}
+
+func TestConfigFromEnv_SimulationWorkers(t *testing.T) {
+	t.Setenv("SIMULATION_WORKERS", "4")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv returned error: %v", err)
+	}
+	if cfg.SimulationWorkers != 4 {
+		t.Fatalf("SimulationWorkers = %d, want 4", cfg.SimulationWorkers)
+	}
+
+	t.Setenv("SIMULATION_WORKERS", "65")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatal("expected error for too many simulation workers")
+	}
+}
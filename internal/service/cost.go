@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// costTolerance is the relative difference below which two plan costs are
+// treated as equal, so float rounding does not beat the pack count tiebreak.
+const costTolerance = 1e-9
+
+var ErrInvalidPackCost = errors.New("packs must list each size once with a finite, non-negative unit cost")
+
+// PackWithCost is a pack size and what shipping one pack of it costs.
+type PackWithCost struct {
+	Size     int     `json:"size"`
+	UnitCost float64 `json:"unit_cost"`
+}
+
+// OptimizeByCost ships the same total as OptimizeWith (minimum overfill for
+// the given sizes) but picks, among the combinations reaching it, the one with
+// the lowest summed unit cost instead of the fewest packs. Ties in cost go to
+// fewer packs. It returns the plan and its total cost.
+//
+// The DP mirrors buildOptimalPackingTable with accumulated cost in place of
+// minPacks, over the totals up to the chosen one.
+func OptimizeByCost(itemsOrdered int, packs []PackWithCost) (Plan, float64, error) {
+	sizes, unitCosts, err := validatePackCosts(packs)
+	if err != nil {
+		return Plan{}, 0, err
+	}
+
+	plan, err := OptimizeWith(itemsOrdered, sizes)
+	if err != nil {
+		return Plan{}, 0, err
+	}
+	total := plan.TotalItems
+
+	cost := make([]float64, total+1)
+	packCount := make([]int, total+1)
+	prevPack := make([]int, total+1)
+	for t := 1; t <= total; t++ {
+		cost[t] = math.Inf(1)
+		for i, size := range sizes {
+			predecessor := t - size
+			if predecessor < 0 || math.IsInf(cost[predecessor], 1) {
+				continue
+			}
+			candidate := cost[predecessor] + unitCosts[i]
+			candidatePacks := packCount[predecessor] + 1
+			if cheaper(candidate, cost[t]) || (!cheaper(cost[t], candidate) && candidatePacks < packCount[t]) {
+				cost[t] = candidate
+				packCount[t] = candidatePacks
+				prevPack[t] = size
+			}
+		}
+	}
+	if math.IsInf(cost[total], 1) {
+		return Plan{}, 0, errReconstructPlan
+	}
+
+	counts := make(map[int]int)
+	for t := total; t > 0; t -= prevPack[t] {
+		counts[prevPack[t]]++
+	}
+	plan.Packs = plan.Packs[:0:0]
+	for _, size := range sizes {
+		if counts[size] > 0 {
+			plan.Packs = append(plan.Packs, PackBreakdown{Size: size, Count: counts[size]})
+		}
+	}
+	plan.TotalPacks = packCount[total]
+	plan.DrivingSize = prevPack[total]
+	setOverfillMetrics(&plan)
+	return plan, cost[total], nil
+}
+
+// cheaper reports whether cost a is lower than b beyond costTolerance.
+func cheaper(a, b float64) bool {
+	if math.IsInf(b, 1) {
+		return !math.IsInf(a, 1)
+	}
+	return a < b-costTolerance*max(1, math.Abs(b))
+}
+
+// validatePackCosts checks packs and returns their sizes in descending order
+// with the matching unit costs.
+func validatePackCosts(packs []PackWithCost) ([]int, []float64, error) {
+	if len(packs) == 0 {
+		return nil, nil, ErrInvalidPackCost
+	}
+
+	sorted := slices.Clone(packs)
+	slices.SortFunc(sorted, func(a, b PackWithCost) int { return b.Size - a.Size })
+	sizes := make([]int, 0, len(sorted))
+	unitCosts := make([]float64, 0, len(sorted))
+	for _, pack := range sorted {
+		if slices.Contains(sizes, pack.Size) {
+			return nil, nil, fmt.Errorf("%w: size %d is listed twice", ErrInvalidPackCost, pack.Size)
+		}
+		if math.IsNaN(pack.UnitCost) || math.IsInf(pack.UnitCost, 0) || pack.UnitCost < 0 {
+			return nil, nil, fmt.Errorf("%w: size %d has unit cost %v", ErrInvalidPackCost, pack.Size, pack.UnitCost)
+		}
+		sizes = append(sizes, pack.Size)
+		unitCosts = append(unitCosts, pack.UnitCost)
+	}
+
+	if _, err := NormalizePackSizes(sizes); err != nil {
+		return nil, nil, err
+	}
+	return sizes, unitCosts, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeByCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		ordered   int
+		packs     []PackWithCost
+		wantTotal int
+		wantPacks []PackBreakdown
+		wantCost  float64
+	}{
+		{
+			name:      "handling fees make small packs cheaper",
+			ordered:   4500,
+			packs:     []PackWithCost{{Size: 1000, UnitCost: 10}, {Size: 5000, UnitCost: 60}},
+			wantTotal: 5000,
+			wantPacks: []PackBreakdown{{Size: 1000, Count: 5}},
+			wantCost:  50,
+		},
+		{
+			name:      "large pack cheaper per unit",
+			ordered:   4500,
+			packs:     []PackWithCost{{Size: 1000, UnitCost: 10}, {Size: 5000, UnitCost: 40}},
+			wantTotal: 5000,
+			wantPacks: []PackBreakdown{{Size: 5000, Count: 1}},
+			wantCost:  40,
+		},
+		{
+			name:      "equal cost goes to fewer packs",
+			ordered:   5000,
+			packs:     []PackWithCost{{Size: 1000, UnitCost: 0.1}, {Size: 5000, UnitCost: 0.5}},
+			wantTotal: 5000,
+			wantPacks: []PackBreakdown{{Size: 5000, Count: 1}},
+			wantCost:  0.5,
+		},
+		{
+			name:      "cost never adds overfill",
+			ordered:   251,
+			packs:     []PackWithCost{{Size: 250, UnitCost: 1}, {Size: 500, UnitCost: 100}},
+			wantTotal: 500,
+			wantPacks: []PackBreakdown{{Size: 250, Count: 2}},
+			wantCost:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, cost, err := OptimizeByCost(tt.ordered, tt.packs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if plan.TotalItems != tt.wantTotal {
+				t.Fatalf("expected total %d, got %d", tt.wantTotal, plan.TotalItems)
+			}
+			if !reflect.DeepEqual(plan.Packs, tt.wantPacks) {
+				t.Fatalf("expected packs %v, got %v", tt.wantPacks, plan.Packs)
+			}
+			if plan.TotalPacks != TotalPhysicalPacks(tt.wantPacks) {
+				t.Fatalf("expected %d packs, got %d", TotalPhysicalPacks(tt.wantPacks), plan.TotalPacks)
+			}
+			if math.Abs(cost-tt.wantCost) > 1e-9 {
+				t.Fatalf("expected cost %v, got %v", tt.wantCost, cost)
+			}
+		})
+	}
+}
+
+func TestOptimizeByCostDoesNotAlterCachedPlan(t *testing.T) {
+	setTestConfig(t, func(cfg *Config) { cfg.ResultCacheSize = 8 })
+
+	before, err := OptimizeWith(4500, []int{1000, 5000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := OptimizeByCost(4500, []PackWithCost{{Size: 1000, UnitCost: 10}, {Size: 5000, UnitCost: 60}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after, err := OptimizeWith(4500, []int{1000, 5000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(before, after) {
+		t.Fatalf("expected cached plan %+v, got %+v", before, after)
+	}
+}
+
+func TestOptimizeByCostRejectsInvalidPacks(t *testing.T) {
+	tests := []struct {
+		name    string
+		packs   []PackWithCost
+		wantErr error
+	}{
+		{name: "no packs", packs: nil, wantErr: ErrInvalidPackCost},
+		{name: "duplicate size", packs: []PackWithCost{{Size: 250, UnitCost: 1}, {Size: 250, UnitCost: 2}}, wantErr: ErrInvalidPackCost},
+		{name: "negative cost", packs: []PackWithCost{{Size: 250, UnitCost: -1}}, wantErr: ErrInvalidPackCost},
+		{name: "NaN cost", packs: []PackWithCost{{Size: 250, UnitCost: math.NaN()}}, wantErr: ErrInvalidPackCost},
+		{name: "non-positive size", packs: []PackWithCost{{Size: 0, UnitCost: 1}}, wantErr: ErrInvalidPackSizes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := OptimizeByCost(500, tt.packs); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	// maxCoverageOrder bounds the order range CheckCoverage scans, which also
+	// bounds the packing table it builds.
+	maxCoverageOrder = 1_000_000
+	// maxCoverageOffenders bounds how many failing orders a report lists.
+	maxCoverageOffenders = 20
+)
+
+var ErrInvalidCoverage = errors.New("coverage requires max_order >= 1 and max_overfill >= 0")
+
+// CoverageOffender is an order whose best plan overfills beyond the threshold.
+type CoverageOffender struct {
+	ItemsOrdered int `json:"items_ordered"`
+	TotalItems   int `json:"total_items"`
+	Overfill     int `json:"overfill"`
+}
+
+// CoverageReport tells whether every order from 1 to MaxOrder ships with at
+// most MaxOverfill extra items.
+type CoverageReport struct {
+	PackSizes   []int `json:"pack_sizes"`
+	MaxOrder    int   `json:"max_order"`
+	MaxOverfill int   `json:"max_overfill"`
+	// Covered is true when no order in the range exceeds MaxOverfill.
+	Covered bool `json:"covered"`
+	// FailingOrders counts the orders that exceed MaxOverfill.
+	FailingOrders int `json:"failing_orders"`
+	// WorstOffenders lists up to maxCoverageOffenders failing orders, largest
+	// overfill first and ties by the smaller order.
+	WorstOffenders []CoverageOffender `json:"worst_offenders"`
+}
+
+// CheckCoverage reports whether packSizes serve every order from 1 to
+// maxOrder with an overfill of at most maxOverfill items, reading the total
+// each order would ship off a single packing table built for maxOrder.
+func CheckCoverage(maxOrder, maxOverfill int, packSizes []int) (CoverageReport, error) {
+	if maxOrder < 1 || maxOverfill < 0 {
+		return CoverageReport{}, fmt.Errorf("%w: got max_order=%d, max_overfill=%d", ErrInvalidCoverage, maxOrder, maxOverfill)
+	}
+	if maxOrder > maxCoverageOrder {
+		return CoverageReport{}, fmt.Errorf("%w: max_order=%d exceeds max %d", ErrInvalidCoverage, maxOrder, maxCoverageOrder)
+	}
+
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return CoverageReport{}, err
+	}
+
+	table, err := newPackingTable(maxOrder, normalized)
+	if err != nil {
+		return CoverageReport{}, err
+	}
+	table.buildOptimalPackingTable()
+
+	report := CoverageReport{
+		PackSizes:      normalized,
+		MaxOrder:       maxOrder,
+		MaxOverfill:    maxOverfill,
+		WorstOffenders: []CoverageOffender{},
+	}
+
+	table.forEachChosenTotal(maxOrder, func(order, total int) {
+		overfill := total - order
+		if overfill <= maxOverfill {
+			return
+		}
+		report.FailingOrders++
+		report.WorstOffenders = addCoverageOffender(report.WorstOffenders, CoverageOffender{
+			ItemsOrdered: order,
+			TotalItems:   total,
+			Overfill:     overfill,
+		})
+	})
+
+	report.Covered = report.FailingOrders == 0
+	return report, nil
+}
+
+// addCoverageOffender inserts offender into the sorted, bounded offender list.
+func addCoverageOffender(offenders []CoverageOffender, offender CoverageOffender) []CoverageOffender {
+	worse := func(a, b CoverageOffender) int {
+		if a.Overfill != b.Overfill {
+			return b.Overfill - a.Overfill
+		}
+		return a.ItemsOrdered - b.ItemsOrdered
+	}
+
+	i, _ := slices.BinarySearchFunc(offenders, offender, worse)
+	if i >= maxCoverageOffenders {
+		return offenders
+	}
+	offenders = slices.Insert(offenders, i, offender)
+	if len(offenders) > maxCoverageOffenders {
+		offenders = offenders[:maxCoverageOffenders]
+	}
+	return offenders
+}
+
+// forEachChosenTotal calls fn with the total chooseFulfillmentTotal would pick
+// for every order from maxOrder down to 1, in one downward scan that tracks
+// the next reachable total. maxOrder must not exceed t.itemsOrdered.
+func (t *packingTable) forEachChosenTotal(maxOrder int, fn func(order, total int)) {
+	// The next multiple of the largest pack is always within the table, so
+	// nextReachable is set before the scan reaches maxOrder.
+	nextReachable := 0
+	for total := t.fulfillmentLimit; total >= 1; total-- {
+		if t.minPacks[total] != t.unreachablePacks {
+			nextReachable = total
+		}
+		if total <= maxOrder {
+			fn(total, nextReachable)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckCoverage_ThresholdCatchesSmallOrders(t *testing.T) {
+	got, err := CheckCoverage(1000, 100, []int{250, 500})
+	if err != nil {
+		t.Fatalf("CheckCoverage returned error: %v", err)
+	}
+
+	if got.Covered {
+		t.Fatal("Covered = true, want false")
+	}
+	// In each 250-item band, orders 1..149 above its start overfill by more than 100.
+	if got.FailingOrders != 4*149 {
+		t.Fatalf("FailingOrders = %d, want %d", got.FailingOrders, 4*149)
+	}
+
+	wantWorst := []CoverageOffender{
+		{ItemsOrdered: 1, TotalItems: 250, Overfill: 249},
+		{ItemsOrdered: 251, TotalItems: 500, Overfill: 249},
+		{ItemsOrdered: 501, TotalItems: 750, Overfill: 249},
+		{ItemsOrdered: 751, TotalItems: 1000, Overfill: 249},
+		{ItemsOrdered: 2, TotalItems: 250, Overfill: 248},
+	}
+	if len(got.WorstOffenders) != maxCoverageOffenders {
+		t.Fatalf("len(WorstOffenders) = %d, want %d", len(got.WorstOffenders), maxCoverageOffenders)
+	}
+	if !reflect.DeepEqual(got.WorstOffenders[:len(wantWorst)], wantWorst) {
+		t.Fatalf("WorstOffenders = %v, want prefix %v", got.WorstOffenders, wantWorst)
+	}
+}
+
+func TestCheckCoverage_MatchesOptimizePerOrder(t *testing.T) {
+	packSizes := []int{23, 31, 53}
+	got, err := CheckCoverage(300, 5, packSizes)
+	if err != nil {
+		t.Fatalf("CheckCoverage returned error: %v", err)
+	}
+
+	failing := 0
+	for order := 1; order <= 300; order++ {
+		plan, err := OptimizeWith(order, packSizes)
+		if err != nil {
+			t.Fatalf("OptimizeWith(%d) returned error: %v", order, err)
+		}
+		if plan.TotalItems-order > 5 {
+			failing++
+		}
+	}
+	if got.FailingOrders != failing {
+		t.Fatalf("FailingOrders = %d, want %d", got.FailingOrders, failing)
+	}
+}
+
+func TestCheckCoverage_Covered(t *testing.T) {
+	got, err := CheckCoverage(1000, 249, []int{250, 500})
+	if err != nil {
+		t.Fatalf("CheckCoverage returned error: %v", err)
+	}
+	if !got.Covered || got.FailingOrders != 0 || len(got.WorstOffenders) != 0 {
+		t.Fatalf("unexpected report: %+v", got)
+	}
+}
+
+func TestCheckCoverage_InvalidInput(t *testing.T) {
+	for _, tc := range []struct{ maxOrder, maxOverfill int }{
+		{0, 10},
+		{100, -1},
+		{maxCoverageOrder + 1, 10},
+	} {
+		if _, err := CheckCoverage(tc.maxOrder, tc.maxOverfill, []int{250}); !errors.Is(err, ErrInvalidCoverage) {
+			t.Fatalf("CheckCoverage(%d, %d): expected ErrInvalidCoverage, got %v", tc.maxOrder, tc.maxOverfill, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+// chainDivisible reports whether every size of sortedPackSizes (descending)
+// divides the next larger one, as in {250, 500, 1000}. Such catalogs are
+// canonical: filling greedily from the largest pack is optimal.
+func chainDivisible(sortedPackSizes []int) bool {
+	for i := 1; i < len(sortedPackSizes); i++ {
+		if sortedPackSizes[i-1]%sortedPackSizes[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// useDivisiblePlan reports whether itemsOrdered takes the divisiblePlan path
+// instead of the DP. Divisible catalogs skip the table but keep its size
+// limit, so both paths accept the same orders.
+func useDivisiblePlan(itemsOrdered int, sortedPackSizes []int, tableLimit int) bool {
+	return chainDivisible(sortedPackSizes) && itemsOrdered+sortedPackSizes[0] <= tableLimit
+}
+
+// divisiblePlan is the plan of computePlan for a chainDivisible catalog,
+// without a table. Every reachable total is a multiple of the smallest size,
+// so the chosen total is itemsOrdered rounded up to one; each size then takes
+// as many packs as fit, which is the unique fewest-packs breakdown because
+// any other holds enough of some size to swap for one larger pack.
+//
+// DrivingSize matches the DP's: its backtrack ends with the largest size of
+// that unique breakdown.
+func divisiblePlan(itemsOrdered int, sortedPackSizes []int) Plan {
+	smallest := sortedPackSizes[len(sortedPackSizes)-1]
+	total := (itemsOrdered + smallest - 1) / smallest * smallest
+
+	plan := Plan{
+		ItemsOrdered: itemsOrdered,
+		TotalItems:   total,
+		Optimal:      true,
+		Algorithm:    AlgorithmGreedyDivisible,
+	}
+	remaining := total
+	for _, size := range sortedPackSizes {
+		count := remaining / size
+		if count == 0 {
+			continue
+		}
+		remaining -= count * size
+		plan.TotalPacks += count
+		plan.Packs = append(plan.Packs, PackBreakdown{Size: size, Count: count})
+		if plan.DrivingSize == 0 {
+			plan.DrivingSize = size
+		}
+	}
+	return plan
+}
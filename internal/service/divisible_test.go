@@ -0,0 +1,74 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChainDivisible(t *testing.T) {
+	tests := []struct {
+		sizes []int
+		want  bool
+	}{
+		{sizes: []int{1000, 500, 250}, want: true},
+		{sizes: []int{250}, want: true},
+		{sizes: []int{5000, 2000, 1000, 500, 250}, want: false},
+		{sizes: []int{53, 31, 23}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := chainDivisible(tt.sizes); got != tt.want {
+			t.Fatalf("chainDivisible(%v) = %t, want %t", tt.sizes, got, tt.want)
+		}
+	}
+}
+
+func TestDivisiblePlanMatchesDP(t *testing.T) {
+	for _, sizes := range [][]int{{1000, 500, 250}, {60, 12, 4, 2}, {7}} {
+		for ordered := 1; ordered <= 3000; ordered++ {
+			want, _, _, err := computePlan(t.Context(), ordered, sizes, maxTableEntries, true)
+			if err != nil {
+				t.Fatalf("sizes %v order %d: computePlan returned error: %v", sizes, ordered, err)
+			}
+			want.Algorithm = AlgorithmGreedyDivisible
+			if got := divisiblePlan(ordered, sizes); !reflect.DeepEqual(got, want) {
+				t.Fatalf("sizes %v order %d: divisiblePlan = %+v, want %+v", sizes, ordered, got, want)
+			}
+		}
+	}
+}
+
+func TestOptimize_Algorithm(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		opts  Options
+		want  string
+	}{
+		{name: "divisible catalog", sizes: []int{250, 500, 1000}, want: AlgorithmGreedyDivisible},
+		{name: "coprime catalog", sizes: []int{23, 31, 53}, want: AlgorithmDP},
+		{name: "divisible catalog with explanation", sizes: []int{250, 500, 1000}, opts: Options{Explain: true}, want: AlgorithmDP},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, tt.sizes)
+
+			plan, err := OptimizeWithOptions(t.Context(), 12001, tt.opts)
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+			if plan.Algorithm != tt.want {
+				t.Fatalf("algorithm = %q, want %q", plan.Algorithm, tt.want)
+			}
+		})
+	}
+
+	plan, err := OptimizeGreedy(10_000_001, []int{23, 31, 53})
+	if err != nil {
+		t.Fatalf("OptimizeGreedy returned error: %v", err)
+	}
+	if plan.Algorithm != AlgorithmGreedyApprox {
+		t.Fatalf("greedy algorithm = %q, want %q", plan.Algorithm, AlgorithmGreedyApprox)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxExactRangeTotal bounds the highest total ExactTotals may scan.
+const maxExactRangeTotal = 1_000_000
+
+var ErrInvalidExactRange = errors.New("exact range must satisfy 1 <= from <= to")
+
+// ExactTotals returns the totals in [from, to] that whole packs of packSizes
+// reach exactly, in ascending order.
+func ExactTotals(from, to int, packSizes []int) ([]int, error) {
+	totals := []int{}
+	err := StreamExactTotals(context.Background(), from, to, packSizes, func(total int) error {
+		totals = append(totals, total)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// StreamExactTotals computes the same totals as ExactTotals in a single
+// reachability pass and calls emit for each one as soon as it is known, so
+// callers can forward results before the pass ends. It stops at the first
+// emit error or when ctx is done.
+func StreamExactTotals(ctx context.Context, from, to int, packSizes []int, emit func(total int) error) error {
+	if from < 1 || from > to {
+		return fmt.Errorf("%w: got from=%d, to=%d", ErrInvalidExactRange, from, to)
+	}
+	if to > maxExactRangeTotal {
+		return fmt.Errorf("%w: to=%d exceeds max %d", ErrInvalidExactRange, to, maxExactRangeTotal)
+	}
+
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return err
+	}
+
+	reachable := make([]bool, to+1)
+	reachable[0] = true
+	for total := 1; total <= to; total++ {
+		if total%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		for _, size := range normalized {
+			if size <= total && reachable[total-size] {
+				reachable[total] = true
+				break
+			}
+		}
+		// Totals are filled in ascending order, so reachable[total] is final here.
+		if reachable[total] && total >= from {
+			if err := emit(total); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
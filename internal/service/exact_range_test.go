@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExactTotals(t *testing.T) {
+	got, err := ExactTotals(10, 25, []int{6, 10})
+	if err != nil {
+		t.Fatalf("ExactTotals returned error: %v", err)
+	}
+
+	want := []int{10, 12, 16, 18, 20, 22, 24}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExactTotals() = %v, want %v", got, want)
+	}
+}
+
+func TestExactTotals_InvalidRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+	}{
+		{name: "from below one", from: 0, to: 10},
+		{name: "from above to", from: 11, to: 10},
+		{name: "to above max", from: 1, to: maxExactRangeTotal + 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ExactTotals(tc.from, tc.to, []int{6, 10}); !errors.Is(err, ErrInvalidExactRange) {
+				t.Fatalf("error = %v, want ErrInvalidExactRange", err)
+			}
+		})
+	}
+}
+
+func TestStreamExactTotals_StopsOnEmitError(t *testing.T) {
+	errStop := errors.New("stop")
+	var emitted []int
+
+	err := StreamExactTotals(context.Background(), 1, 100, []int{6, 10}, func(total int) error {
+		emitted = append(emitted, total)
+		if len(emitted) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("error = %v, want errStop", err)
+	}
+	if !reflect.DeepEqual(emitted, []int{6, 10}) {
+		t.Fatalf("emitted = %v, want [6 10]", emitted)
+	}
+}
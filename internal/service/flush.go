@@ -0,0 +1,59 @@
+package service
+
+// FlushReport lists what FlushCaches cleared.
+type FlushReport struct {
+	// ResultCacheEntries is the number of cached plans dropped.
+	ResultCacheEntries int `json:"result_cache_entries"`
+	// NormalizeCacheEntries is the number of cached pack size
+	// normalizations dropped.
+	NormalizeCacheEntries int `json:"normalize_cache_entries"`
+	// IdempotentResults is the number of stored idempotent results dropped.
+	IdempotentResults int `json:"idempotent_results"`
+	// PackingTable reports whether a shared packing table was dropped.
+	PackingTable bool `json:"packing_table"`
+	// WarmupCancelled reports whether a running table warm-up was cancelled.
+	WarmupCancelled bool `json:"warmup_cancelled"`
+}
+
+// FlushCaches clears every in-memory cache, so the next optimizations
+// recompute from scratch. Tables persisted by PrepareTableCache stay on disk.
+func FlushCaches() FlushReport {
+	var report FlushReport
+
+	tableWarmup.mu.Lock()
+	if tableWarmup.cancel != nil {
+		select {
+		case <-tableWarmup.done:
+		default:
+			report.WarmupCancelled = true
+		}
+		tableWarmup.cancel()
+		tableWarmup.cancel = nil
+	}
+	tableWarmup.mu.Unlock()
+
+	sharedTable.mu.Lock()
+	report.PackingTable = sharedTable.table != nil
+	sharedTable.table = nil
+	sharedTable.mu.Unlock()
+
+	resultCache.mu.Lock()
+	report.ResultCacheEntries = resultCache.order.Len()
+	resultCache.order.Init()
+	clear(resultCache.entries)
+	resultCache.mu.Unlock()
+
+	normalizeCache.mu.Lock()
+	report.NormalizeCacheEntries = normalizeCache.order.Len()
+	normalizeCache.order.Init()
+	clear(normalizeCache.entries)
+	normalizeCache.mu.Unlock()
+
+	idempotentResults.mu.Lock()
+	report.IdempotentResults = len(idempotentResults.keys)
+	idempotentResults.keys = nil
+	clear(idempotentResults.results)
+	idempotentResults.mu.Unlock()
+
+	return report
+}
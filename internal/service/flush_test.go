@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+)
+
+func TestFlushCaches_NextOptimizeRecomputes(t *testing.T) {
+	resetResultCache(t)
+	resetCachedTable(t)
+	setTestConfig(t, func(cfg *Config) { cfg.ResultCacheSize = 10 })
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	if _, err := Optimize(12001); err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if _, err := OptimizeIdempotent(t.Context(), 12001, Options{}); err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+
+	report := FlushCaches()
+	if report.ResultCacheEntries != 1 || report.IdempotentResults == 0 {
+		t.Fatalf("FlushCaches() = %+v, want the cached plan and stored result cleared", report)
+	}
+
+	before := planComputations.Load()
+	if _, err := Optimize(12001); err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if got := planComputations.Load() - before; got != 1 {
+		t.Fatalf("optimize after flush computed %d plans, want 1", got)
+	}
+}
+
+func TestFlushCaches_DropsSharedTable(t *testing.T) {
+	resetCachedTable(t)
+
+	table, err := newCoveringTable([]int{500, 250}, 10_000)
+	if err != nil {
+		t.Fatalf("newCoveringTable returned error: %v", err)
+	}
+	table.buildOptimalPackingTable()
+	setCachedTable(&table)
+
+	if report := FlushCaches(); !report.PackingTable {
+		t.Fatalf("FlushCaches() = %+v, want the packing table dropped", report)
+	}
+	if _, ok := cachedTableFor(251, []int{500, 250}); ok {
+		t.Fatal("packing table is still cached after flush")
+	}
+}
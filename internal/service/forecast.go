@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxForecastPeriods bounds the horizon accepted by PlanForecast.
+const maxForecastPeriods = 120
+
+var ErrInvalidForecast = errors.New("forecast must contain at least one period")
+
+// ForecastPeriod is the demand expected in one future period.
+type ForecastPeriod struct {
+	Label        string `json:"label"`
+	ItemsOrdered int    `json:"items_ordered"`
+}
+
+// ForecastPeriodPlan is the plan serving one forecast period.
+type ForecastPeriodPlan struct {
+	Label string `json:"label"`
+	Plan  Plan   `json:"plan"`
+}
+
+// ForecastPlan holds per-period plans and the pack demand they add up to.
+type ForecastPlan struct {
+	Periods []ForecastPeriodPlan `json:"periods"`
+	// PackDemand counts the packs of each size needed across the horizon, in
+	// descending size order; sizes no period uses are listed with zero.
+	PackDemand []PackBreakdown `json:"pack_demand"`
+	TotalItems int             `json:"total_items"`
+	TotalPacks int             `json:"total_packs"`
+}
+
+// PlanForecast optimizes every period of a demand forecast against packSizes
+// and aggregates the pack demand per size, for procurement planning.
+func PlanForecast(periods []ForecastPeriod, packSizes []int) (ForecastPlan, error) {
+	if len(periods) == 0 {
+		return ForecastPlan{}, ErrInvalidForecast
+	}
+	if len(periods) > maxForecastPeriods {
+		return ForecastPlan{}, fmt.Errorf("%w: %d periods exceeds max %d", ErrInvalidForecast, len(periods), maxForecastPeriods)
+	}
+
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return ForecastPlan{}, err
+	}
+
+	demand := make(map[int]int, len(normalized))
+	result := ForecastPlan{Periods: make([]ForecastPeriodPlan, 0, len(periods))}
+	for i, period := range periods {
+		plan, err := OptimizeWith(period.ItemsOrdered, normalized)
+		if err != nil {
+			return ForecastPlan{}, fmt.Errorf("periods[%d]: %w", i, err)
+		}
+
+		result.Periods = append(result.Periods, ForecastPeriodPlan{Label: period.Label, Plan: plan})
+		result.TotalItems += plan.TotalItems
+		result.TotalPacks += plan.TotalPacks
+		for _, pack := range plan.Packs {
+			demand[pack.Size] += pack.Count
+		}
+	}
+
+	result.PackDemand = make([]PackBreakdown, 0, len(normalized))
+	for _, size := range normalized {
+		result.PackDemand = append(result.PackDemand, PackBreakdown{Size: size, Count: demand[size]})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPlanForecast_SumsPackDemand(t *testing.T) {
+	periods := []ForecastPeriod{
+		{Label: "2026-11", ItemsOrdered: 251},
+		{Label: "2026-12", ItemsOrdered: 12001},
+		{Label: "2027-01", ItemsOrdered: 501},
+	}
+
+	got, err := PlanForecast(periods, []int{250, 500, 1000, 2000, 5000})
+	if err != nil {
+		t.Fatalf("PlanForecast returned error: %v", err)
+	}
+
+	// 251 -> 1x500; 12001 -> 2x5000, 1x2000, 1x250; 501 -> 1x500, 1x250.
+	wantDemand := []PackBreakdown{
+		{Size: 5000, Count: 2},
+		{Size: 2000, Count: 1},
+		{Size: 1000, Count: 0},
+		{Size: 500, Count: 2},
+		{Size: 250, Count: 2},
+	}
+	if !reflect.DeepEqual(got.PackDemand, wantDemand) {
+		t.Fatalf("PackDemand = %+v, want %+v", got.PackDemand, wantDemand)
+	}
+	if got.TotalItems != 500+12250+750 || got.TotalPacks != 7 {
+		t.Fatalf("totals = %d items in %d packs, want 13500 in 7", got.TotalItems, got.TotalPacks)
+	}
+	if len(got.Periods) != 3 || got.Periods[1].Label != "2026-12" || got.Periods[1].Plan.TotalItems != 12250 {
+		t.Fatalf("unexpected periods: %+v", got.Periods)
+	}
+}
+
+func TestPlanForecast_InvalidInput(t *testing.T) {
+	if _, err := PlanForecast(nil, []int{250}); !errors.Is(err, ErrInvalidForecast) {
+		t.Fatalf("expected ErrInvalidForecast, got %v", err)
+	}
+	if _, err := PlanForecast(make([]ForecastPeriod, maxForecastPeriods+1), []int{250}); !errors.Is(err, ErrInvalidForecast) {
+		t.Fatalf("expected ErrInvalidForecast for oversized horizon, got %v", err)
+	}
+	if _, err := PlanForecast([]ForecastPeriod{{ItemsOrdered: 0}}, []int{250}); !errors.Is(err, ErrInvalidItemsOrdered) {
+		t.Fatalf("expected ErrInvalidItemsOrdered, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// OptimizeGreedy plans orders too large for the exact DP table: it ships the
+// largest pack repeatedly and solves only the tail exactly. The tail is the
+// remainder plus one of the largest packs, so the DP can trade that pack for
+// smaller ones; when even the tail's table is too large (catalogs configured
+// with very large packs), the tail ships as the single smallest pack covering
+// it.
+//
+// The plan is usually within a pack or two of optimal but carries no
+// guarantee, so Optimal is always false. itemsOrdered may exceed the int32
+// ceiling of OptimizeWith.
+func OptimizeGreedy(itemsOrdered int, packSizes []int) (Plan, error) {
+	if itemsOrdered <= 0 {
+		return Plan{}, ErrInvalidItemsOrdered
+	}
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return Plan{}, err
+	}
+	largest := normalized[0]
+	// The shipped total stays below itemsOrdered plus the largest pack.
+	if itemsOrdered > math.MaxInt-largest {
+		return Plan{}, fmt.Errorf("%w: %d exceeds max value %d", ErrInvalidItemsOrdered, itemsOrdered, math.MaxInt-largest)
+	}
+
+	count, remainder := itemsOrdered/largest, itemsOrdered%largest
+	// One largest pack moves into the exactly solved tail when there is one.
+	moved := min(count, 1)
+
+	var tailPlan Plan
+	if tail := remainder + moved*largest; tail+largest > maxTableEntries {
+		moved = 0
+		tailPlan = coveringPackPlan(remainder, normalized)
+	} else if tailPlan, err = OptimizeWith(tail, normalized); err != nil {
+		return Plan{}, err
+	}
+	count -= moved
+
+	plan := Plan{
+		ItemsOrdered: itemsOrdered,
+		TotalItems:   count*largest + tailPlan.TotalItems,
+		TotalPacks:   count + tailPlan.TotalPacks,
+		Packs:        addPacks(tailPlan.Packs, largest, count),
+		DrivingSize:  tailPlan.DrivingSize,
+		Algorithm:    AlgorithmGreedyApprox,
+	}
+	if plan.DrivingSize == 0 {
+		plan.DrivingSize = largest
+	}
+	setOverfillMetrics(&plan)
+	setPlanStatus(&plan)
+	return plan, nil
+}
+
+// OptimizeWithFallback behaves like OptimizeWith but serves orders the exact
+// DP rejects as too large with OptimizeGreedy instead of failing; check
+// Plan.Optimal to tell the two apart.
+func OptimizeWithFallback(itemsOrdered int, packSizes []int) (Plan, error) {
+	if itemsOrdered > maxInt32Value {
+		return OptimizeGreedy(itemsOrdered, packSizes)
+	}
+	plan, err := OptimizeWith(itemsOrdered, packSizes)
+	if errors.Is(err, ErrOptimizationTooLarge) {
+		return OptimizeGreedy(itemsOrdered, packSizes)
+	}
+	return plan, err
+}
+
+// coveringPackPlan ships items as the single smallest pack holding them all,
+// or nothing for zero items; sortedPackSizes is sorted descending and its
+// largest pack must hold items.
+func coveringPackPlan(items int, sortedPackSizes []int) Plan {
+	if items == 0 {
+		return Plan{}
+	}
+	size := sortedPackSizes[0]
+	for _, candidate := range sortedPackSizes[1:] {
+		if candidate >= items {
+			size = candidate
+		}
+	}
+	return Plan{
+		TotalItems:  size,
+		TotalPacks:  1,
+		Packs:       []PackBreakdown{{Size: size, Count: 1}},
+		DrivingSize: size,
+	}
+}
+
+// addPacks returns packs, sorted by size descending, with count more packs of
+// size, which must not be smaller than any size in packs.
+func addPacks(packs []PackBreakdown, size, count int) []PackBreakdown {
+	if count == 0 {
+		return packs
+	}
+	if len(packs) > 0 && packs[0].Size == size {
+		return append([]PackBreakdown{{Size: size, Count: packs[0].Count + count}}, packs[1:]...)
+	}
+	return append([]PackBreakdown{{Size: size, Count: count}}, packs...)
+}
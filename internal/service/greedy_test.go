@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeGreedy(t *testing.T) {
+	sizes := []int{250, 500, 1000, 2000, 5000}
+	tests := []struct {
+		name      string
+		ordered   int
+		sizes     []int
+		wantTotal int
+		wantPacks []PackBreakdown
+	}{
+		{
+			name:      "multiple of the largest pack",
+			ordered:   10_000_000,
+			sizes:     sizes,
+			wantTotal: 10_000_000,
+			wantPacks: []PackBreakdown{{Size: 5000, Count: 2000}},
+		},
+		{
+			name:      "tail solved exactly",
+			ordered:   10_000_001,
+			sizes:     sizes,
+			wantTotal: 10_000_250,
+			wantPacks: []PackBreakdown{{Size: 5000, Count: 2000}, {Size: 250, Count: 1}},
+		},
+		{
+			name:      "tail trades a largest pack for smaller ones",
+			ordered:   5_000_001,
+			sizes:     []int{3, 5},
+			wantTotal: 5_000_001,
+			wantPacks: []PackBreakdown{{Size: 5, Count: 999_999}, {Size: 3, Count: 2}},
+		},
+		{
+			name:      "beyond the int32 ceiling",
+			ordered:   3_000_000_001,
+			sizes:     sizes,
+			wantTotal: 3_000_000_250,
+			wantPacks: []PackBreakdown{{Size: 5000, Count: 600_000}, {Size: 250, Count: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := OptimizeGreedy(tt.ordered, tt.sizes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if plan.TotalItems != tt.wantTotal {
+				t.Fatalf("expected total %d, got %d", tt.wantTotal, plan.TotalItems)
+			}
+			if !reflect.DeepEqual(plan.Packs, tt.wantPacks) {
+				t.Fatalf("expected packs %v, got %v", tt.wantPacks, plan.Packs)
+			}
+			if plan.TotalPacks != TotalPhysicalPacks(tt.wantPacks) {
+				t.Fatalf("expected %d packs, got %d", TotalPhysicalPacks(tt.wantPacks), plan.TotalPacks)
+			}
+			if plan.Overfill != tt.wantTotal-tt.ordered {
+				t.Fatalf("expected overfill %d, got %d", tt.wantTotal-tt.ordered, plan.Overfill)
+			}
+			if plan.Optimal {
+				t.Fatal("expected greedy plan to be flagged approximate")
+			}
+		})
+	}
+}
+
+func TestOptimizeGreedyCoversTailWithOnePackWhenTailTableTooLarge(t *testing.T) {
+	setTestConfig(t, func(cfg *Config) { cfg.MaxPackSize = 2_000_000 })
+
+	plan, err := OptimizeGreedy(4_000_000, []int{1_500_000, 7})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []PackBreakdown{{Size: 1_500_000, Count: 3}}
+	if !reflect.DeepEqual(plan.Packs, want) || plan.TotalItems != 4_500_000 || plan.TotalPacks != 3 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+}
+
+func TestOptimizeGreedyStaysWithinOneLargestPack(t *testing.T) {
+	sizes := []int{23, 31, 53}
+	for ordered := 1; ordered <= 2000; ordered++ {
+		plan, err := OptimizeGreedy(ordered, sizes)
+		if err != nil {
+			t.Fatalf("order %d: unexpected error: %v", ordered, err)
+		}
+		if plan.TotalItems < ordered || plan.Overfill >= 53 {
+			t.Fatalf("order %d: total %d outside [order, order+53)", ordered, plan.TotalItems)
+		}
+		sum := 0
+		for _, pack := range plan.Packs {
+			sum += pack.Size * pack.Count
+		}
+		if sum != plan.TotalItems {
+			t.Fatalf("order %d: packs sum to %d, total is %d", ordered, sum, plan.TotalItems)
+		}
+	}
+}
+
+func TestOptimizeGreedyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ordered int
+		sizes   []int
+		wantErr error
+	}{
+		{name: "zero order", ordered: 0, sizes: []int{250}, wantErr: ErrInvalidItemsOrdered},
+		{name: "no sizes", ordered: 10, sizes: nil, wantErr: ErrInvalidPackSizes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := OptimizeGreedy(tt.ordered, tt.sizes); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithFallback(t *testing.T) {
+	sizes := []int{250, 500, 1000, 2000, 5000}
+
+	exact, err := OptimizeWithFallback(12001, sizes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, err := OptimizeWith(12001, sizes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(exact, want) || !exact.Optimal {
+		t.Fatalf("expected the exact plan %+v, got %+v", want, exact)
+	}
+
+	for _, ordered := range []int{10_000_001, 3_000_000_001} {
+		if _, err := OptimizeWith(ordered, sizes); err == nil {
+			t.Fatalf("order %d: expected the exact DP to reject it", ordered)
+		}
+		plan, err := OptimizeWithFallback(ordered, sizes)
+		if err != nil {
+			t.Fatalf("order %d: unexpected error: %v", ordered, err)
+		}
+		if plan.Optimal || plan.TotalItems != ordered+249 {
+			t.Fatalf("order %d: expected the greedy plan, got %+v", ordered, plan)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// maxIdempotentResults bounds how many recent results OptimizeIdempotent keeps.
+const maxIdempotentResults = 1000
+
+type idempotencyKey struct {
+	itemsOrdered   int
+	catalogVersion uint64
+	skusVersion    uint64
+	opts           Options
+}
+
+// idempotentResults keeps the most recent results in insertion order; the
+// oldest is evicted first once maxIdempotentResults is reached.
+var idempotentResults = struct {
+	mu      sync.Mutex
+	keys    []idempotencyKey
+	results map[idempotencyKey]Plan
+}{
+	results: make(map[idempotencyKey]Plan),
+}
+
+// OptimizeIdempotent behaves like OptimizeWithOptions but returns the stored
+// result of an earlier identical request (same order, options and catalog
+// version) when there is one, so retries get identical responses even if
+// tie-breaking ever changes. A catalog or pack SKU update bumps a version in
+// the key, so stored results never outlive the catalog and labels they were
+// computed with. The returned plan
+// may be shared with other callers and must not be modified.
+func OptimizeIdempotent(ctx context.Context, itemsOrdered int, opts Options) (Plan, error) {
+	if CatalogReloading() {
+		return Plan{}, ErrCatalogReloading
+	}
+
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	packSizes, version := packSizeService.GetCatalog()
+	key := idempotencyKey{itemsOrdered: itemsOrdered, catalogVersion: version, skusVersion: packSKUsVersion(), opts: opts}
+
+	idempotentResults.mu.Lock()
+	plan, ok := idempotentResults.results[key]
+	idempotentResults.mu.Unlock()
+	if ok {
+		recordPackUsage(plan)
+		return servedPlan(plan, opts), nil
+	}
+
+	plan, err = optimizeTraced(ctx, itemsOrdered, packSizes, opts)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan.PlanID = planID(itemsOrdered, packSizes, version, opts)
+	if opts.EchoInput {
+		plan.Input = newPlanInput(itemsOrdered, plan, packSizes, version, opts)
+	}
+	LabelPacks(&plan)
+	recordPackUsage(plan)
+
+	idempotentResults.mu.Lock()
+	defer idempotentResults.mu.Unlock()
+
+	// A concurrent identical request may have stored its result first; keep
+	// that one so every retry sees the same plan.
+	if stored, ok := idempotentResults.results[key]; ok {
+		return servedPlan(stored, opts), nil
+	}
+	if len(idempotentResults.keys) == maxIdempotentResults {
+		delete(idempotentResults.results, idempotentResults.keys[0])
+		idempotentResults.keys = idempotentResults.keys[1:]
+	}
+	idempotentResults.keys = append(idempotentResults.keys, key)
+	idempotentResults.results[key] = plan
+
+	return servedPlan(plan, opts), nil
+}
+
+// servedPlan is a stored plan as returned to the caller. Stored plans keep
+// their breakdown, so a retry counts the same pack usage as the first call.
+func servedPlan(plan Plan, opts Options) Plan {
+	if opts.SkipBreakdown {
+		return omitBreakdown(plan)
+	}
+	return plan
+}
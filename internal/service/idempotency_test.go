@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeIdempotent_ReturnsStoredResult(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+	opts := Options{Usage: true}
+
+	first, err := OptimizeIdempotent(context.Background(), 12001, opts)
+	if err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+
+	before := planComputations.Load()
+	second, err := OptimizeIdempotent(context.Background(), 12001, opts)
+	if err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+
+	if got := planComputations.Load() - before; got != 0 {
+		t.Fatalf("retry computed %d plans, want 0", got)
+	}
+	// Usage carries a timing, so only a stored result can match exactly.
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("retry = %+v, want stored %+v", second, first)
+	}
+}
+
+func TestOptimizeIdempotent_KeyedByCatalogVersionAndOptions(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	first, err := OptimizeIdempotent(context.Background(), 251, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+	if first.TotalItems != 500 {
+		t.Fatalf("TotalItems = %d, want 500", first.TotalItems)
+	}
+
+	snapped, err := OptimizeIdempotent(context.Background(), 251, Options{SnapToExact: true})
+	if err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+	if snapped.ItemsOrdered != 500 {
+		t.Fatalf("ItemsOrdered = %d, want 500 with different options", snapped.ItemsOrdered)
+	}
+
+	setOptimizerPackSizes(t, []int{251})
+	after, err := OptimizeIdempotent(context.Background(), 251, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+	if after.TotalItems != 251 {
+		t.Fatalf("TotalItems = %d, want 251 after the catalog changed", after.TotalItems)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxInventoryWork bounds the bounded knapsack behind OptimizeWithInventory:
+// split items times table entries.
+const maxInventoryWork = 100_000_000
+
+var (
+	ErrInsufficientInventory = errors.New("no plan fits within the available inventory")
+	ErrInvalidInventory      = errors.New("inventory counts must not be negative")
+)
+
+// inventoryItem is a bundle of count packs of one size; each size's available
+// count is split into bundles of 1, 2, 4, ... packs so any count up to it is
+// a sum of distinct bundles.
+type inventoryItem struct {
+	size  int
+	count int
+}
+
+// OptimizeWithInventory is OptimizeWith where inventory maps each pack size
+// to how many packs of it are on hand, and a plan may not use more. It still
+// minimizes overfill, then packs; it returns ErrInsufficientInventory when no
+// total at or above itemsOrdered can be reached within the inventory.
+//
+// The single-dimension table cannot track per-size usage, so this solves a
+// bounded knapsack instead: counts are split into power-of-two bundles and
+// solved as a 0/1 knapsack over the fulfillment window, bounded by
+// maxInventoryWork.
+func OptimizeWithInventory(itemsOrdered int, inventory map[int]int) (Plan, error) {
+	if itemsOrdered <= 0 || itemsOrdered > maxInt32Value {
+		return Plan{}, ErrInvalidItemsOrdered
+	}
+
+	sizes := make([]int, 0, len(inventory))
+	for size, count := range inventory {
+		if count < 0 {
+			return Plan{}, fmt.Errorf("%w: size %d has %d", ErrInvalidInventory, size, count)
+		}
+		sizes = append(sizes, size)
+	}
+	sizes, err := NormalizePackSizes(sizes)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	// Any plan reaching itemsOrdered+largest or more still covers the order
+	// without one of its packs, so the best total lies below that.
+	limit := itemsOrdered + sizes[0] - 1
+	if limit+1 > maxTableEntries {
+		return Plan{}, fmt.Errorf("%w: requires %d table entries (max %d)", ErrOptimizationTooLarge, limit+1, maxTableEntries)
+	}
+
+	var items []inventoryItem
+	for _, size := range sizes {
+		available := min(inventory[size], limit/size)
+		for bundle := 1; available > 0; bundle *= 2 {
+			count := min(bundle, available)
+			items = append(items, inventoryItem{size: size, count: count})
+			available -= count
+		}
+	}
+	if work := int64(len(items)) * int64(limit+1); work > maxInventoryWork {
+		return Plan{}, fmt.Errorf("%w: inventory needs %d steps (max %d)", ErrOptimizationTooLarge, work, maxInventoryWork)
+	}
+
+	const unreachable = -1
+	minPacks := make([]int, limit+1)
+	for t := 1; t <= limit; t++ {
+		minPacks[t] = unreachable
+	}
+	// taken[i] records, per total, whether items[i] improved it.
+	taken := make([][]uint64, len(items))
+	for i, item := range items {
+		taken[i] = make([]uint64, limit/64+1)
+		weight := item.size * item.count
+		for t := limit; t >= weight; t-- {
+			if minPacks[t-weight] == unreachable {
+				continue
+			}
+			candidate := minPacks[t-weight] + item.count
+			if minPacks[t] == unreachable || candidate < minPacks[t] {
+				minPacks[t] = candidate
+				taken[i][t/64] |= 1 << (t % 64)
+			}
+		}
+	}
+
+	total := itemsOrdered
+	for total <= limit && minPacks[total] == unreachable {
+		total++
+	}
+	if total > limit {
+		return Plan{}, fmt.Errorf("%w: %d items", ErrInsufficientInventory, itemsOrdered)
+	}
+
+	counts := make(map[int]int, len(sizes))
+	remaining := total
+	for i := len(items) - 1; i >= 0; i-- {
+		if taken[i][remaining/64]&(1<<(remaining%64)) != 0 {
+			counts[items[i].size] += items[i].count
+			remaining -= items[i].size * items[i].count
+		}
+	}
+	if remaining != 0 {
+		return Plan{}, errReconstructPlan
+	}
+
+	plan := Plan{
+		ItemsOrdered: itemsOrdered,
+		TotalItems:   total,
+		TotalPacks:   minPacks[total],
+		Optimal:      true,
+		Algorithm:    AlgorithmDP,
+	}
+	for _, size := range sizes {
+		if counts[size] > inventory[size] {
+			return Plan{}, errReconstructPlan
+		}
+		if counts[size] > 0 {
+			plan.Packs = append(plan.Packs, PackBreakdown{Size: size, Count: counts[size]})
+		}
+	}
+	// The knapsack has no single last step; the smallest pack used stands in
+	// for it.
+	plan.DrivingSize = plan.Packs[len(plan.Packs)-1].Size
+	setOverfillMetrics(&plan)
+	return plan, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeWithInventory(t *testing.T) {
+	tests := []struct {
+		name      string
+		ordered   int
+		inventory map[int]int
+		wantTotal int
+		wantPacks []PackBreakdown
+	}{
+		{
+			name:      "ample inventory matches the unbounded plan",
+			ordered:   12001,
+			inventory: map[int]int{250: 100, 500: 100, 1000: 100, 2000: 100, 5000: 100},
+			wantTotal: 12250,
+			wantPacks: []PackBreakdown{{Size: 5000, Count: 2}, {Size: 2000, Count: 1}, {Size: 250, Count: 1}},
+		},
+		{
+			name:      "out of the largest size",
+			ordered:   12001,
+			inventory: map[int]int{250: 100, 500: 100, 1000: 100, 2000: 100, 5000: 0},
+			wantTotal: 12250,
+			wantPacks: []PackBreakdown{{Size: 2000, Count: 6}, {Size: 250, Count: 1}},
+		},
+		{
+			name:      "short of a size adds packs",
+			ordered:   1000,
+			inventory: map[int]int{250: 10, 500: 1},
+			wantTotal: 1000,
+			wantPacks: []PackBreakdown{{Size: 500, Count: 1}, {Size: 250, Count: 2}},
+		},
+		{
+			name:      "shortage raises overfill",
+			ordered:   750,
+			inventory: map[int]int{250: 1, 500: 2},
+			wantTotal: 750,
+			wantPacks: []PackBreakdown{{Size: 500, Count: 1}, {Size: 250, Count: 1}},
+		},
+		{
+			name:      "only a larger total fits",
+			ordered:   500,
+			inventory: map[int]int{300: 1, 400: 5},
+			wantTotal: 700,
+			wantPacks: []PackBreakdown{{Size: 400, Count: 1}, {Size: 300, Count: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := OptimizeWithInventory(tt.ordered, tt.inventory)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if plan.TotalItems != tt.wantTotal {
+				t.Fatalf("expected total %d, got %d", tt.wantTotal, plan.TotalItems)
+			}
+			if !reflect.DeepEqual(plan.Packs, tt.wantPacks) {
+				t.Fatalf("expected packs %v, got %v", tt.wantPacks, plan.Packs)
+			}
+			if plan.TotalPacks != TotalPhysicalPacks(tt.wantPacks) {
+				t.Fatalf("expected %d packs, got %d", TotalPhysicalPacks(tt.wantPacks), plan.TotalPacks)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithInventoryErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		ordered   int
+		inventory map[int]int
+		wantErr   error
+	}{
+		{name: "not enough stock", ordered: 1001, inventory: map[int]int{250: 2, 500: 1}, wantErr: ErrInsufficientInventory},
+		{name: "empty stock", ordered: 1, inventory: map[int]int{250: 0}, wantErr: ErrInsufficientInventory},
+		{name: "negative count", ordered: 1, inventory: map[int]int{250: -1}, wantErr: ErrInvalidInventory},
+		{name: "no sizes", ordered: 1, inventory: map[int]int{}, wantErr: ErrInvalidPackSizes},
+		{name: "invalid order", ordered: 0, inventory: map[int]int{250: 1}, wantErr: ErrInvalidItemsOrdered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := OptimizeWithInventory(tt.ordered, tt.inventory); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"math"
+)
+
+// OptimizeLarge optimizes orders beyond the int32 ceiling of OptimizeWith.
+//
+// Every reachable total is a multiple of g, the greatest common divisor of
+// the pack sizes, so the problem scales down exactly: the order is rounded up
+// to ceil(itemsOrdered/g) units of g and solved against the sizes divided by
+// g, then scaled back. Catalogs of large sizes (e.g. pallets of 1000000) thus
+// serve orders of billions of items from a small table; the scaled order
+// still has to fit the int32 ceiling and maxTableEntries, or
+// ErrOptimizationTooLarge is returned. For itemsOrdered within the ceiling the
+// plan equals OptimizeWith's, up to ties between equally good breakdowns.
+func OptimizeLarge(itemsOrdered int64, packSizes []int) (Plan, error) {
+	if itemsOrdered <= 0 {
+		return Plan{}, ErrInvalidItemsOrdered
+	}
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return Plan{}, err
+	}
+	// The shipped total stays below itemsOrdered plus the largest pack.
+	if itemsOrdered > int64(math.MaxInt)-int64(normalized[0]) {
+		return Plan{}, fmt.Errorf("%w: %d exceeds max value %d", ErrInvalidItemsOrdered, itemsOrdered, int64(math.MaxInt)-int64(normalized[0]))
+	}
+
+	g := normalized[0]
+	for _, size := range normalized[1:] {
+		g = gcd(g, size)
+	}
+	scaledSizes := make([]int, len(normalized))
+	for i, size := range normalized {
+		scaledSizes[i] = size / g
+	}
+	scaledOrder := (itemsOrdered + int64(g) - 1) / int64(g)
+	if scaledOrder > maxInt32Value {
+		return Plan{}, fmt.Errorf("%w: %d items in units of %d still exceed %d", ErrOptimizationTooLarge, itemsOrdered, g, maxInt32Value)
+	}
+
+	scaled, err := OptimizeWith(int(scaledOrder), scaledSizes)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	plan := Plan{
+		ItemsOrdered: int(itemsOrdered),
+		TotalItems:   scaled.TotalItems * g,
+		TotalPacks:   scaled.TotalPacks,
+		Packs:        make([]PackBreakdown, len(scaled.Packs)),
+		DrivingSize:  scaled.DrivingSize * g,
+		Optimal:      scaled.Optimal,
+		Algorithm:    scaled.Algorithm,
+	}
+	for i, pack := range scaled.Packs {
+		plan.Packs[i] = PackBreakdown{Size: pack.Size * g, Count: pack.Count}
+	}
+	setOverfillMetrics(&plan)
+	setPlanStatus(&plan)
+	return plan, nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
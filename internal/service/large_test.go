@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestOptimizeLarge(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("orders beyond int32 need a 64-bit int")
+	}
+
+	plan, err := OptimizeLarge(3_000_000_001, []int{250_000, 500_000, 1_000_000})
+	if err != nil {
+		t.Fatalf("OptimizeLarge returned error: %v", err)
+	}
+
+	want := []PackBreakdown{{Size: 1_000_000, Count: 3000}, {Size: 250_000, Count: 1}}
+	if !reflect.DeepEqual(plan.Packs, want) {
+		t.Fatalf("Packs = %v, want %v", plan.Packs, want)
+	}
+	if plan.ItemsOrdered != 3_000_000_001 || plan.TotalItems != 3_000_250_000 || plan.TotalPacks != 3001 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if plan.Overfill != 249_999 || plan.OverfillSource != plan.DrivingSize || plan.DrivingSize%250_000 != 0 {
+		t.Fatalf("unexpected overfill metrics: %+v", plan)
+	}
+}
+
+func TestOptimizeLargeMatchesOptimizeWithinInt32(t *testing.T) {
+	for _, tc := range []struct {
+		ordered int
+		sizes   []int
+	}{
+		{ordered: 12001, sizes: []int{250, 500, 1000, 2000, 5000}},
+		{ordered: 263, sizes: []int{23, 31, 53}},
+	} {
+		want, err := OptimizeWith(tc.ordered, tc.sizes)
+		if err != nil {
+			t.Fatalf("OptimizeWith returned error: %v", err)
+		}
+		got, err := OptimizeLarge(int64(tc.ordered), tc.sizes)
+		if err != nil {
+			t.Fatalf("OptimizeLarge returned error: %v", err)
+		}
+		if got.TotalItems != want.TotalItems || got.TotalPacks != want.TotalPacks || got.Overfill != want.Overfill {
+			t.Fatalf("order %d: plan = %+v, want %+v", tc.ordered, got, want)
+		}
+	}
+}
+
+func TestOptimizeLargeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ordered int64
+		sizes   []int
+		wantErr error
+	}{
+		{name: "non-positive order", ordered: 0, sizes: []int{250}, wantErr: ErrInvalidItemsOrdered},
+		{name: "beyond int", ordered: math.MaxInt64, sizes: []int{250}, wantErr: ErrInvalidItemsOrdered},
+		{name: "coprime sizes beyond int32", ordered: 3_000_000_000, sizes: []int{23, 31}, wantErr: ErrOptimizationTooLarge},
+		{name: "scaled table too large", ordered: 3_000_000_000, sizes: []int{1000, 3000}, wantErr: ErrOptimizationTooLarge},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := OptimizeLarge(tc.ordered, tc.sizes); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Catalog lint rules reported by LintPackSizes.
+const (
+	LintDuplicate         = "duplicate"
+	LintRedundantMultiple = "redundant_multiple"
+	LintNeverImproves     = "never_improves"
+)
+
+// Lint finding severities. Warnings point at sizes that can be dropped as
+// they are; advisories need a judgment call.
+const (
+	SeverityWarning  = "warning"
+	SeverityAdvisory = "advisory"
+)
+
+const (
+	// maxLintSampleMax bounds the order range LintPackSizes samples.
+	maxLintSampleMax = 100_000
+	// lintDefaultSampleFactor sets the default sampled range to this many
+	// multiples of the largest size.
+	lintDefaultSampleFactor = 10
+	// maxLintWork bounds the table cells LintPackSizes fills in total: one
+	// table for the catalog plus one per size left out.
+	maxLintWork = 200_000_000
+)
+
+var ErrInvalidLintSample = errors.New("sample_max must be between 1 and the sampling limit")
+
+// LintFinding is one catalog hygiene issue.
+type LintFinding struct {
+	Size     int    `json:"size"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// MultipleOf is the largest smaller size that divides Size, for
+	// LintRedundantMultiple.
+	MultipleOf int `json:"multiple_of,omitempty"`
+}
+
+// CatalogLint is the result of linting a proposed catalog.
+type CatalogLint struct {
+	// PackSizes is the catalog as it would be stored: deduplicated, descending.
+	PackSizes []int `json:"pack_sizes"`
+	// SampleMax is the largest order LintNeverImproves was checked against.
+	SampleMax int           `json:"sample_max"`
+	Findings  []LintFinding `json:"findings"`
+}
+
+// LintPackSizes flags hygiene issues in a proposed catalog: duplicated
+// sizes, sizes that are exact multiples of a smaller size (advisory: they can
+// still save packs), and sizes that leave every plan for orders 1..sampleMax
+// unchanged when removed. A zero sampleMax samples lintDefaultSampleFactor
+// multiples of the largest size, up to maxLintSampleMax.
+func LintPackSizes(packSizes []int, sampleMax int) (CatalogLint, error) {
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return CatalogLint{}, err
+	}
+
+	if sampleMax == 0 {
+		sampleMax = min(normalized[0]*lintDefaultSampleFactor, maxLintSampleMax)
+	}
+	if sampleMax < 0 || sampleMax > maxLintSampleMax {
+		return CatalogLint{}, fmt.Errorf("%w: got %d, max %d", ErrInvalidLintSample, sampleMax, maxLintSampleMax)
+	}
+	entries := sampleMax + normalized[0]
+	if work := (len(normalized) + 1) * len(normalized) * entries; work > maxLintWork {
+		return CatalogLint{}, fmt.Errorf("%w: linting %d sizes up to %d needs %d steps (max %d)", ErrOptimizationTooLarge, len(normalized), sampleMax, work, maxLintWork)
+	}
+
+	result := CatalogLint{
+		PackSizes: normalized,
+		SampleMax: sampleMax,
+		Findings:  []LintFinding{},
+	}
+
+	seen := make(map[int]bool, len(packSizes))
+	for _, size := range packSizes {
+		if seen[size] {
+			result.Findings = append(result.Findings, LintFinding{
+				Size:     size,
+				Rule:     LintDuplicate,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%d is listed more than once; the duplicate is dropped", size),
+			})
+		}
+		seen[size] = true
+	}
+
+	for i, size := range normalized {
+		// Sizes are descending, so the first divisor after i is the largest.
+		for _, smaller := range normalized[i+1:] {
+			if size%smaller == 0 {
+				result.Findings = append(result.Findings, LintFinding{
+					Size:       size,
+					Rule:       LintRedundantMultiple,
+					Severity:   SeverityAdvisory,
+					Message:    fmt.Sprintf("%d is %d x %d; it only saves packs, never items", size, size/smaller, smaller),
+					MultipleOf: smaller,
+				})
+				break
+			}
+		}
+	}
+
+	unused, err := sizesNeverImproving(normalized, sampleMax)
+	if err != nil {
+		return CatalogLint{}, err
+	}
+	for _, size := range unused {
+		result.Findings = append(result.Findings, LintFinding{
+			Size:     size,
+			Rule:     LintNeverImproves,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("removing %d changes no plan for orders up to %d", size, sampleMax),
+		})
+	}
+
+	return result, nil
+}
+
+// sizesNeverImproving returns the sizes whose removal leaves the shipped
+// total and pack count of every order in 1..sampleMax unchanged. A single
+// size is always needed.
+func sizesNeverImproving(normalized []int, sampleMax int) ([]int, error) {
+	if len(normalized) == 1 {
+		return nil, nil
+	}
+
+	totals, packs, err := chosenPlans(normalized, sampleMax)
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []int
+	for i, size := range normalized {
+		without := slices.Delete(slices.Clone(normalized), i, i+1)
+		withoutTotals, withoutPacks, err := chosenPlans(without, sampleMax)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Equal(totals, withoutTotals) && slices.Equal(packs, withoutPacks) {
+			unused = append(unused, size)
+		}
+	}
+	return unused, nil
+}
+
+// chosenPlans returns, indexed by order, the total and pack count Optimize
+// would ship for every order in 1..sampleMax.
+func chosenPlans(normalized []int, sampleMax int) (totals, packs []int, err error) {
+	table, err := newPackingTable(sampleMax, normalized)
+	if err != nil {
+		return nil, nil, err
+	}
+	table.buildOptimalPackingTable()
+
+	totals = make([]int, sampleMax+1)
+	packs = make([]int, sampleMax+1)
+	table.forEachChosenTotal(sampleMax, func(order, total int) {
+		totals[order] = total
+		packs[order] = table.minPacks[total]
+	})
+	return totals, packs, nil
+}
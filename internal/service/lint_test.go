@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func findLint(findings []LintFinding, size int, rule string) (LintFinding, bool) {
+	for _, finding := range findings {
+		if finding.Size == size && finding.Rule == rule {
+			return finding, true
+		}
+	}
+	return LintFinding{}, false
+}
+
+func TestLintPackSizes_FlagsRedundantMultiple(t *testing.T) {
+	got, err := LintPackSizes([]int{250, 500, 1000}, 0)
+	if err != nil {
+		t.Fatalf("LintPackSizes returned error: %v", err)
+	}
+
+	finding, ok := findLint(got.Findings, 500, LintRedundantMultiple)
+	if !ok {
+		t.Fatalf("expected 500 flagged as a redundant multiple, got %+v", got.Findings)
+	}
+	if finding.Severity != SeverityAdvisory || finding.MultipleOf != 250 {
+		t.Fatalf("unexpected finding: %+v", finding)
+	}
+	if finding, ok := findLint(got.Findings, 1000, LintRedundantMultiple); !ok || finding.MultipleOf != 500 {
+		t.Fatalf("expected 1000 flagged as a multiple of 500, got %+v", got.Findings)
+	}
+	if _, ok := findLint(got.Findings, 250, LintRedundantMultiple); ok {
+		t.Fatalf("250 has no smaller divisor, got %+v", got.Findings)
+	}
+	if got.SampleMax != 10_000 {
+		t.Fatalf("SampleMax = %d, want 10000", got.SampleMax)
+	}
+}
+
+func TestLintPackSizes_FlagsDuplicates(t *testing.T) {
+	got, err := LintPackSizes([]int{250, 500, 250}, 0)
+	if err != nil {
+		t.Fatalf("LintPackSizes returned error: %v", err)
+	}
+
+	finding, ok := findLint(got.Findings, 250, LintDuplicate)
+	if !ok || finding.Severity != SeverityWarning {
+		t.Fatalf("expected a duplicate warning for 250, got %+v", got.Findings)
+	}
+	if len(got.PackSizes) != 2 {
+		t.Fatalf("PackSizes = %v, want duplicates dropped", got.PackSizes)
+	}
+}
+
+func TestLintPackSizes_FlagsSizesThatNeverImprove(t *testing.T) {
+	got, err := LintPackSizes([]int{3, 6, 30}, 40)
+	if err != nil {
+		t.Fatalf("LintPackSizes returned error: %v", err)
+	}
+
+	if _, ok := findLint(got.Findings, 30, LintNeverImproves); ok {
+		t.Fatalf("30 ships order 30 in one pack instead of five, got %+v", got.Findings)
+	}
+
+	got, err = LintPackSizes([]int{3, 6, 60}, 20)
+	if err != nil {
+		t.Fatalf("LintPackSizes returned error: %v", err)
+	}
+	if _, ok := findLint(got.Findings, 60, LintNeverImproves); !ok {
+		t.Fatalf("expected 60 flagged for orders up to 20 (every plan uses 3 and 6), got %+v", got.Findings)
+	}
+	if _, ok := findLint(got.Findings, 6, LintNeverImproves); ok {
+		t.Fatalf("6 saves packs, got %+v", got.Findings)
+	}
+}
+
+func TestLintPackSizes_InvalidInput(t *testing.T) {
+	if _, err := LintPackSizes([]int{250}, -1); !errors.Is(err, ErrInvalidLintSample) {
+		t.Fatalf("expected ErrInvalidLintSample, got %v", err)
+	}
+	if _, err := LintPackSizes([]int{250}, maxLintSampleMax+1); !errors.Is(err, ErrInvalidLintSample) {
+		t.Fatalf("expected ErrInvalidLintSample, got %v", err)
+	}
+	if _, err := LintPackSizes([]int{0}, 0); !errors.Is(err, ErrInvalidPackSizes) {
+		t.Fatalf("expected ErrInvalidPackSizes, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Limits of CompareMatrix: the number of cells, and the DP work summed over
+// them (table entries times pack sizes per cell).
+const (
+	maxMatrixCells = 400
+	maxMatrixWork  = 50_000_000
+)
+
+var ErrInvalidMatrix = errors.New("matrix must have at least one order and one catalog")
+
+// MatrixCell summarizes the plan of one order under one catalog.
+type MatrixCell struct {
+	TotalItems int `json:"total_items"`
+	Overfill   int `json:"overfill"`
+	TotalPacks int `json:"total_packs"`
+}
+
+// PlanMatrix holds one row per order and one column per catalog, for
+// comparing catalogs across a range of orders (e.g. as a heatmap).
+type PlanMatrix struct {
+	Orders []int `json:"orders"`
+	// Catalogs are the normalized catalogs, in request order.
+	Catalogs [][]int        `json:"catalogs"`
+	Cells    [][]MatrixCell `json:"cells"`
+}
+
+// CompareMatrix optimizes every order against every catalog. All catalogs
+// and orders are validated, and the cell count and summed DP work bounded,
+// before any cell is computed, so a bad or oversized request fails fast.
+func CompareMatrix(orders []int, catalogs [][]int) (PlanMatrix, error) {
+	if len(orders) == 0 || len(catalogs) == 0 {
+		return PlanMatrix{}, ErrInvalidMatrix
+	}
+	if cells := len(orders) * len(catalogs); cells > maxMatrixCells {
+		return PlanMatrix{}, fmt.Errorf("%w: %d cells exceeds max %d", ErrInvalidMatrix, cells, maxMatrixCells)
+	}
+
+	normalized := make([][]int, len(catalogs))
+	for i, catalog := range catalogs {
+		sizes, err := NormalizePackSizes(catalog)
+		if err != nil {
+			return PlanMatrix{}, fmt.Errorf("catalogs[%d]: %w", i, err)
+		}
+		normalized[i] = sizes
+	}
+	for i, order := range orders {
+		if order <= 0 || order > maxInt32Value {
+			return PlanMatrix{}, fmt.Errorf("orders[%d]: %w", i, ErrInvalidItemsOrdered)
+		}
+	}
+
+	work := int64(0)
+	for _, order := range orders {
+		for _, sizes := range normalized {
+			work += (int64(order) + int64(sizes[0])) * int64(len(sizes))
+		}
+	}
+	if work > maxMatrixWork {
+		return PlanMatrix{}, fmt.Errorf("%w: matrix needs %d steps (max %d)", ErrOptimizationTooLarge, work, maxMatrixWork)
+	}
+
+	matrix := PlanMatrix{
+		Orders:   orders,
+		Catalogs: normalized,
+		Cells:    make([][]MatrixCell, len(orders)),
+	}
+	for i, order := range orders {
+		row := make([]MatrixCell, len(normalized))
+		for j, sizes := range normalized {
+			plan, err := OptimizeWith(order, sizes)
+			if err != nil {
+				return PlanMatrix{}, fmt.Errorf("orders[%d], catalogs[%d]: %w", i, j, err)
+			}
+			row[j] = MatrixCell{TotalItems: plan.TotalItems, Overfill: plan.Overfill, TotalPacks: plan.TotalPacks}
+		}
+		matrix.Cells[i] = row
+	}
+	return matrix, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompareMatrix(t *testing.T) {
+	matrix, err := CompareMatrix([]int{251, 500, 501}, [][]int{{250, 500, 1000}, {300, 200}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := [][]MatrixCell{
+		{{TotalItems: 500, Overfill: 249, TotalPacks: 1}, {TotalItems: 300, Overfill: 49, TotalPacks: 1}},
+		{{TotalItems: 500, Overfill: 0, TotalPacks: 1}, {TotalItems: 500, Overfill: 0, TotalPacks: 2}},
+		{{TotalItems: 750, Overfill: 249, TotalPacks: 2}, {TotalItems: 600, Overfill: 99, TotalPacks: 2}},
+	}
+	if !reflect.DeepEqual(matrix.Cells, want) {
+		t.Fatalf("expected cells %+v, got %+v", want, matrix.Cells)
+	}
+	if !reflect.DeepEqual(matrix.Catalogs, [][]int{{1000, 500, 250}, {300, 200}}) {
+		t.Fatalf("expected normalized catalogs, got %v", matrix.Catalogs)
+	}
+}
+
+func TestCompareMatrixRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		orders   []int
+		catalogs [][]int
+		wantErr  error
+	}{
+		{name: "no orders", catalogs: [][]int{{250}}, wantErr: ErrInvalidMatrix},
+		{name: "no catalogs", orders: []int{250}, wantErr: ErrInvalidMatrix},
+		{name: "too many cells", orders: make([]int, 21), catalogs: make([][]int, 20), wantErr: ErrInvalidMatrix},
+		{name: "invalid later catalog", orders: []int{250}, catalogs: [][]int{{250}, {}}, wantErr: ErrInvalidPackSizes},
+		{name: "invalid order", orders: []int{250, 0}, catalogs: [][]int{{250}}, wantErr: ErrInvalidItemsOrdered},
+		{name: "too much work", orders: []int{1_000_000, 1_000_000}, catalogs: [][]int{{23, 31, 53}, {7, 11, 13, 17, 19, 29, 37, 41, 43, 47}, {3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73}}, wantErr: ErrOptimizationTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompareMatrix(tt.orders, tt.catalogs); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
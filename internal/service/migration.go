@@ -0,0 +1,60 @@
+package service
+
+import "fmt"
+
+// CatalogMigration compares the plans an order gets before and after a catalog change.
+type CatalogMigration struct {
+	ItemsOrdered int  `json:"items_ordered"`
+	OldPlan      Plan `json:"old_plan"`
+	NewPlan      Plan `json:"new_plan"`
+	// NewDominates is true when the new plan ships no more items and no more
+	// packs than the old one, and strictly fewer of at least one.
+	NewDominates bool   `json:"new_dominates"`
+	Note         string `json:"note"`
+}
+
+// CompareCatalogMigration optimizes itemsOrdered under the old and the new
+// pack sizes and describes how the migration affects the order.
+func CompareCatalogMigration(itemsOrdered int, oldPackSizes, newPackSizes []int) (CatalogMigration, error) {
+	oldPlan, err := OptimizeWith(itemsOrdered, oldPackSizes)
+	if err != nil {
+		return CatalogMigration{}, fmt.Errorf("old catalog: %w", err)
+	}
+	newPlan, err := OptimizeWith(itemsOrdered, newPackSizes)
+	if err != nil {
+		return CatalogMigration{}, fmt.Errorf("new catalog: %w", err)
+	}
+
+	itemsDelta := newPlan.TotalItems - oldPlan.TotalItems
+	packsDelta := newPlan.TotalPacks - oldPlan.TotalPacks
+
+	return CatalogMigration{
+		ItemsOrdered: itemsOrdered,
+		OldPlan:      oldPlan,
+		NewPlan:      newPlan,
+		NewDominates: itemsDelta <= 0 && packsDelta <= 0 && (itemsDelta < 0 || packsDelta < 0),
+		Note:         migrationNote(itemsDelta, packsDelta),
+	}, nil
+}
+
+func migrationNote(itemsDelta, packsDelta int) string {
+	if itemsDelta == 0 && packsDelta == 0 {
+		return "the new catalog ships the same number of items and packs"
+	}
+	return fmt.Sprintf("the new catalog ships %s and %s", describeDelta(itemsDelta, "item"), describeDelta(packsDelta, "pack"))
+}
+
+func describeDelta(delta int, unit string) string {
+	switch {
+	case delta == 0:
+		return "the same number of " + unit + "s"
+	case delta == 1:
+		return "1 more " + unit
+	case delta == -1:
+		return "1 fewer " + unit
+	case delta > 0:
+		return fmt.Sprintf("%d more %ss", delta, unit)
+	default:
+		return fmt.Sprintf("%d fewer %ss", -delta, unit)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompareCatalogMigration(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldSizes  []int
+		newSizes  []int
+		ordered   int
+		oldTotal  int
+		newTotal  int
+		dominates bool
+		note      string
+	}{
+		{
+			name:      "adding a size removes overfill",
+			oldSizes:  []int{250, 500, 1000},
+			newSizes:  []int{250, 300, 500, 1000},
+			ordered:   300,
+			oldTotal:  500,
+			newTotal:  300,
+			dominates: true,
+			note:      "the new catalog ships 200 fewer items and the same number of packs",
+		},
+		{
+			name:      "retiring a size increases packs",
+			oldSizes:  []int{250, 500, 1000},
+			newSizes:  []int{250, 1000},
+			ordered:   500,
+			oldTotal:  500,
+			newTotal:  500,
+			dominates: false,
+			note:      "the new catalog ships the same number of items and 1 more pack",
+		},
+		{
+			name:      "identical catalogs",
+			oldSizes:  []int{250, 500},
+			newSizes:  []int{500, 250},
+			ordered:   251,
+			oldTotal:  500,
+			newTotal:  500,
+			dominates: false,
+			note:      "the new catalog ships the same number of items and packs",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := CompareCatalogMigration(tc.ordered, tc.oldSizes, tc.newSizes)
+			if err != nil {
+				t.Fatalf("CompareCatalogMigration returned error: %v", err)
+			}
+
+			if got.OldPlan.TotalItems != tc.oldTotal || got.NewPlan.TotalItems != tc.newTotal {
+				t.Fatalf("totals = %d -> %d, want %d -> %d", got.OldPlan.TotalItems, got.NewPlan.TotalItems, tc.oldTotal, tc.newTotal)
+			}
+			if got.NewDominates != tc.dominates {
+				t.Fatalf("NewDominates = %t, want %t", got.NewDominates, tc.dominates)
+			}
+			if got.Note != tc.note {
+				t.Fatalf("Note = %q, want %q", got.Note, tc.note)
+			}
+		})
+	}
+}
+
+func TestCompareCatalogMigration_InvalidCatalog(t *testing.T) {
+	_, err := CompareCatalogMigration(300, []int{250}, []int{0})
+	if !errors.Is(err, ErrInvalidPackSizes) {
+		t.Fatalf("expected ErrInvalidPackSizes, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Bounds of SuggestMinimalExactCatalog: how many targets it accepts, how
+// large they may be, how many sizes a catalog may have and how many
+// reachability steps the search may take overall.
+const (
+	maxMinimalExactTargets = 50
+	maxMinimalExactTarget  = 5000
+	maxMinimalExactSizes   = 4
+	maxMinimalExactWork    = 100_000_000
+)
+
+var ErrInvalidExactTargets = errors.New("targets must contain between 1 and 50 orders of at most 5000 items")
+
+// MinimalExactCatalog is the smallest catalog shipping every target exactly.
+type MinimalExactCatalog struct {
+	// Targets are the distinct target orders, ascending.
+	Targets []int `json:"targets"`
+	MinSize int   `json:"min_size"`
+	// Feasible is false when no catalog of at most maxMinimalExactSizes sizes,
+	// none smaller than MinSize, ships every target exactly.
+	Feasible bool `json:"feasible"`
+	// PackSizes is a minimal-cardinality catalog, descending; among catalogs
+	// of that size, the search tries larger sizes first.
+	PackSizes []int `json:"pack_sizes,omitempty"`
+}
+
+// SuggestMinimalExactCatalog finds a catalog with the fewest pack sizes, each
+// at least minSize, that ships every target with zero overfill. Without a
+// minSize above their greatest common divisor the answer is that divisor
+// alone, so minSize is what makes the question interesting.
+//
+// Finding a minimal catalog is a hard combinatorial problem. The search
+// deepens one size at a time, so the first catalog found is minimal: at each
+// level it branches on the sizes that could make the smallest unreachable
+// target reachable (those between minSize and that target, skipping sizes the
+// catalog already reaches). Each branch costs one pass over the targets'
+// range, so the worst case is about maxTarget^(k+1) steps for k sizes; when
+// the search exceeds maxMinimalExactWork steps it fails with
+// ErrOptimizationTooLarge rather than return a catalog it could not prove
+// minimal. Catalogs are limited to maxMinimalExactSizes sizes.
+func SuggestMinimalExactCatalog(targets []int, minSize int) (MinimalExactCatalog, error) {
+	if len(targets) == 0 || len(targets) > maxMinimalExactTargets {
+		return MinimalExactCatalog{}, fmt.Errorf("%w: got %d targets", ErrInvalidExactTargets, len(targets))
+	}
+	for i, target := range targets {
+		if target <= 0 {
+			return MinimalExactCatalog{}, fmt.Errorf("targets[%d]: %w", i, ErrInvalidItemsOrdered)
+		}
+		if target > maxMinimalExactTarget {
+			return MinimalExactCatalog{}, fmt.Errorf("%w: targets[%d] is %d", ErrInvalidExactTargets, i, target)
+		}
+	}
+	if minSize < 0 {
+		return MinimalExactCatalog{}, fmt.Errorf("%w: min_size must not be negative, got %d", ErrInvalidPackSizes, minSize)
+	}
+	minSize = max(minSize, 1)
+
+	sorted := slices.Clone(targets)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	maxTarget := sorted[len(sorted)-1]
+	search := minimalExactSearch{
+		targets:   sorted,
+		minSize:   minSize,
+		maxTarget: maxTarget,
+		budget:    maxMinimalExactWork,
+		reach:     make([][]bool, maxMinimalExactSizes+1),
+	}
+	for i := range search.reach {
+		search.reach[i] = make([]bool, maxTarget+1)
+	}
+	search.reach[0][0] = true
+
+	result := MinimalExactCatalog{Targets: sorted, MinSize: minSize}
+	for sizes := 1; sizes <= maxMinimalExactSizes; sizes++ {
+		if search.find(0, sizes) {
+			result.Feasible = true
+			result.PackSizes = slices.Clone(search.sizes)
+			slices.SortFunc(result.PackSizes, func(a, b int) int { return b - a })
+			return result, nil
+		}
+		if search.budget <= 0 {
+			return MinimalExactCatalog{}, fmt.Errorf("%w: minimal catalog search exceeded %d steps", ErrOptimizationTooLarge, maxMinimalExactWork)
+		}
+	}
+	return result, nil
+}
+
+// minimalExactSearch is a depth-first search over catalogs; reach[d] holds the
+// totals reachable with the first d sizes chosen.
+type minimalExactSearch struct {
+	targets   []int
+	minSize   int
+	maxTarget int
+	budget    int
+	reach     [][]bool
+	sizes     []int
+}
+
+// find reports whether adding at most remaining sizes to the depth sizes
+// chosen so far reaches every target, leaving the catalog in sizes.
+func (s *minimalExactSearch) find(depth, remaining int) bool {
+	reach := s.reach[depth]
+	unreached := 0
+	for _, target := range s.targets {
+		if !reach[target] {
+			unreached = target
+			break
+		}
+	}
+	if unreached == 0 {
+		return true
+	}
+	if remaining == 0 {
+		return false
+	}
+
+	// Some added size must be at most the smallest unreached target; adding
+	// it first loses no catalog. A size already reachable adds nothing.
+	next := s.reach[depth+1]
+	for size := unreached; size >= s.minSize; size-- {
+		if reach[size] {
+			continue
+		}
+		if s.budget <= 0 {
+			return false
+		}
+		s.budget -= s.maxTarget
+
+		copy(next, reach)
+		for total := size; total <= s.maxTarget; total++ {
+			if next[total-size] {
+				next[total] = true
+			}
+		}
+		s.sizes = append(s.sizes, size)
+		if s.find(depth+1, remaining-1) {
+			return true
+		}
+		s.sizes = s.sizes[:len(s.sizes)-1]
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSuggestMinimalExactCatalog(t *testing.T) {
+	tests := []struct {
+		name      string
+		targets   []int
+		minSize   int
+		wantSizes []int
+	}{
+		{name: "common divisor without a minimum size", targets: []int{1000, 250, 500, 500}, minSize: 0, wantSizes: []int{250}},
+		{name: "minimum size forces two sizes", targets: []int{4, 6, 9}, minSize: 2, wantSizes: []int{4, 3}},
+		{name: "coprime targets", targets: []int{23, 31, 53, 100, 101}, minSize: 10, wantSizes: []int{31, 23, 11}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SuggestMinimalExactCatalog(tt.targets, tt.minSize)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Feasible || !reflect.DeepEqual(got.PackSizes, tt.wantSizes) {
+				t.Fatalf("expected pack sizes %v, got %+v", tt.wantSizes, got)
+			}
+			for _, target := range got.Targets {
+				plan, err := OptimizeWith(target, got.PackSizes)
+				if err != nil {
+					t.Fatalf("target %d: unexpected error: %v", target, err)
+				}
+				if plan.Overfill != 0 {
+					t.Fatalf("target %d ships %d extra items with %v", target, plan.Overfill, got.PackSizes)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestMinimalExactCatalog_NoSmallerCatalog(t *testing.T) {
+	// {31, 23, 11} is minimal: no two sizes of at least 10 ship every target.
+	targets := []int{23, 31, 53, 100, 101}
+	reachable := func(sizes []int, total int) bool {
+		reach := make([]bool, total+1)
+		reach[0] = true
+		for t := 1; t <= total; t++ {
+			for _, size := range sizes {
+				if size <= t && reach[t-size] {
+					reach[t] = true
+				}
+			}
+		}
+		return reach[total]
+	}
+	for a := 10; a <= 101; a++ {
+		for b := a; b <= 101; b++ {
+			all := true
+			for _, target := range targets {
+				if !reachable([]int{a, b}, target) {
+					all = false
+					break
+				}
+			}
+			if all {
+				t.Fatalf("sizes %d and %d already ship every target", a, b)
+			}
+		}
+	}
+}
+
+func TestSuggestMinimalExactCatalog_Infeasible(t *testing.T) {
+	got, err := SuggestMinimalExactCatalog([]int{7}, 8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Feasible || got.PackSizes != nil {
+		t.Fatalf("expected no catalog, got %+v", got)
+	}
+}
+
+func TestSuggestMinimalExactCatalog_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []int
+		minSize int
+		wantErr error
+	}{
+		{name: "no targets", targets: nil, wantErr: ErrInvalidExactTargets},
+		{name: "too many targets", targets: make([]int, maxMinimalExactTargets+1), wantErr: ErrInvalidExactTargets},
+		{name: "target too large", targets: []int{maxMinimalExactTarget + 1}, wantErr: ErrInvalidExactTargets},
+		{name: "non-positive target", targets: []int{10, 0}, wantErr: ErrInvalidItemsOrdered},
+		{name: "negative min size", targets: []int{10}, minSize: -1, wantErr: ErrInvalidPackSizes},
+		{name: "search budget exceeded", targets: []int{2999, 4993, 4999}, minSize: 1000, wantErr: ErrOptimizationTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SuggestMinimalExactCatalog(tt.targets, tt.minSize); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+// maxNearestExactWindow bounds how far from the order nearestExact looks for
+// exactly fulfillable totals.
+const maxNearestExactWindow = 10_000
+
+// nearestExact returns the largest exactly fulfillable total at or below the
+// order and the smallest at or above it, each only when it lies within
+// maxNearestExactWindow items of the order. The one above is chosenTotal,
+// the minimum-overfill total, so only the one below needs a scan.
+func (t *packingTable) nearestExact(chosenTotal int) (below, above *int) {
+	if chosenTotal-t.itemsOrdered <= maxNearestExactWindow {
+		above = &chosenTotal
+	}
+
+	for total := t.itemsOrdered; total >= max(1, t.itemsOrdered-maxNearestExactWindow); total-- {
+		if t.minPacks[total] != t.unreachablePacks {
+			return &total, above
+		}
+	}
+	return nil, above
+}
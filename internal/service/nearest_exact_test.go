@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOptimizeWithOptions_NearestExact(t *testing.T) {
+	tests := []struct {
+		name      string
+		ordered   int
+		wantBelow *int
+		wantAbove *int
+	}{
+		{name: "between exact totals", ordered: 300, wantBelow: intPtr(250), wantAbove: intPtr(500)},
+		{name: "exact order", ordered: 750, wantBelow: intPtr(750), wantAbove: intPtr(750)},
+		{name: "below the smallest pack", ordered: 1, wantBelow: nil, wantAbove: intPtr(250)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500})
+
+			plan, err := OptimizeWithOptions(context.Background(), tc.ordered, Options{NearestExact: true})
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+
+			if !equalIntPtr(plan.NearestExactBelow, tc.wantBelow) || !equalIntPtr(plan.NearestExactAbove, tc.wantAbove) {
+				t.Fatalf("nearest exact = (%v, %v), want (%v, %v)", derefOrNil(plan.NearestExactBelow), derefOrNil(plan.NearestExactAbove), derefOrNil(tc.wantBelow), derefOrNil(tc.wantAbove))
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_NearestExactBoundedWindow(t *testing.T) {
+	setOptimizerPackSizes(t, []int{50_000})
+
+	plan, err := OptimizeWithOptions(context.Background(), 70_000, Options{NearestExact: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if plan.NearestExactBelow != nil || plan.NearestExactAbove != nil {
+		t.Fatalf("expected no nearest exact totals within %d items, got (%v, %v)", maxNearestExactWindow, derefOrNil(plan.NearestExactBelow), derefOrNil(plan.NearestExactAbove))
+	}
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func derefOrNil(p *int) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
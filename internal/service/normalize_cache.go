@@ -0,0 +1,101 @@
+package service
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"sync"
+)
+
+const defaultNormalizeCacheSize = 64
+
+// normalizeCacheKey is a hash of a NormalizePackSizes input together with the
+// limits it was checked against, so a config change never serves a result
+// the new limits would reject.
+type normalizeCacheKey [sha256.Size]byte
+
+type normalizeCacheEntry struct {
+	key        normalizeCacheKey
+	normalized []int
+}
+
+// normalizeCache holds the results of the most recently normalized distinct
+// inputs, up to Config.NormalizeCacheSize. Only successful normalizations are
+// stored; entries are never handed out, only copies of them.
+var normalizeCache = struct {
+	mu      sync.Mutex
+	order   *list.List
+	entries map[normalizeCacheKey]*list.Element
+}{
+	order:   list.New(),
+	entries: make(map[normalizeCacheKey]*list.Element),
+}
+
+func newNormalizeCacheKey(packSizes []int, cfg Config) normalizeCacheKey {
+	h := sha256.New()
+	var buf [8]byte
+	for _, value := range []int{cfg.MaxPackSize, cfg.MaxPackSizes, len(packSizes)} {
+		binary.BigEndian.PutUint64(buf[:], uint64(value))
+		h.Write(buf[:])
+	}
+	if cfg.StrictDuplicates {
+		h.Write([]byte{1})
+	} else {
+		h.Write([]byte{0})
+	}
+	for _, size := range packSizes {
+		binary.BigEndian.PutUint64(buf[:], uint64(size))
+		h.Write(buf[:])
+	}
+
+	var key normalizeCacheKey
+	h.Sum(key[:0])
+	return key
+}
+
+// cachedNormalization returns a copy of the cached normalization of
+// packSizes under cfg, if any.
+func cachedNormalization(packSizes []int, cfg Config) ([]int, bool) {
+	if cfg.NormalizeCacheSize <= 0 {
+		return nil, false
+	}
+	key := newNormalizeCacheKey(packSizes, cfg)
+
+	normalizeCache.mu.Lock()
+	defer normalizeCache.mu.Unlock()
+
+	elem, ok := normalizeCache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	normalizeCache.order.MoveToFront(elem)
+	return slices.Clone(elem.Value.(*normalizeCacheEntry).normalized), true
+}
+
+// storeNormalization caches a copy of normalized as the result for packSizes
+// under cfg, evicting the least recently used inputs beyond
+// Config.NormalizeCacheSize.
+func storeNormalization(packSizes, normalized []int, cfg Config) {
+	if cfg.NormalizeCacheSize <= 0 {
+		return
+	}
+	key := newNormalizeCacheKey(packSizes, cfg)
+	normalized = slices.Clone(normalized)
+
+	normalizeCache.mu.Lock()
+	defer normalizeCache.mu.Unlock()
+
+	if elem, ok := normalizeCache.entries[key]; ok {
+		elem.Value.(*normalizeCacheEntry).normalized = normalized
+		normalizeCache.order.MoveToFront(elem)
+		return
+	}
+
+	normalizeCache.entries[key] = normalizeCache.order.PushFront(&normalizeCacheEntry{key: key, normalized: normalized})
+	for normalizeCache.order.Len() > cfg.NormalizeCacheSize {
+		oldest := normalizeCache.order.Back()
+		normalizeCache.order.Remove(oldest)
+		delete(normalizeCache.entries, oldest.Value.(*normalizeCacheEntry).key)
+	}
+}
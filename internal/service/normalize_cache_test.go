@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func resetNormalizeCache(t *testing.T) {
+	t.Helper()
+
+	reset := func() {
+		normalizeCache.mu.Lock()
+		defer normalizeCache.mu.Unlock()
+		normalizeCache.order.Init()
+		clear(normalizeCache.entries)
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestNormalizeCache_HitReturnsIsolatedCopy(t *testing.T) {
+	resetNormalizeCache(t)
+	input := []int{250, 500, 250, 5000}
+
+	first, err := NormalizePackSizes(input)
+	if err != nil {
+		t.Fatalf("NormalizePackSizes returned error: %v", err)
+	}
+	if _, ok := cachedNormalization(input, currentConfig()); !ok {
+		t.Fatal("expected the normalization to be cached")
+	}
+
+	// Neither the caller's result nor its input reaches the cached entry.
+	first[0] = -1
+	input[0] = 999
+	second, err := NormalizePackSizes([]int{250, 500, 250, 5000})
+	if err != nil {
+		t.Fatalf("NormalizePackSizes returned error: %v", err)
+	}
+	if want := []int{5000, 500, 250}; !reflect.DeepEqual(second, want) {
+		t.Fatalf("cached result = %v, want %v", second, want)
+	}
+	second[1] = -1
+	third, _ := NormalizePackSizes([]int{250, 500, 250, 5000})
+	if third[1] != 500 {
+		t.Fatalf("cached result was modified through a returned slice: %v", third)
+	}
+}
+
+func TestNormalizeCache_IsBounded(t *testing.T) {
+	resetNormalizeCache(t)
+	setTestConfig(t, func(cfg *Config) { cfg.NormalizeCacheSize = 3 })
+
+	for size := 1; size <= 10; size++ {
+		if _, err := NormalizePackSizes([]int{size}); err != nil {
+			t.Fatalf("NormalizePackSizes returned error: %v", err)
+		}
+	}
+
+	normalizeCache.mu.Lock()
+	entries := normalizeCache.order.Len()
+	normalizeCache.mu.Unlock()
+	if entries != 3 {
+		t.Fatalf("cache holds %d entries, want 3", entries)
+	}
+	if _, ok := cachedNormalization([]int{1}, currentConfig()); ok {
+		t.Fatal("expected the least recently used input to be evicted")
+	}
+	if _, ok := cachedNormalization([]int{10}, currentConfig()); !ok {
+		t.Fatal("expected the most recent input to be cached")
+	}
+}
+
+func TestNormalizeCache_KeyedByConfig(t *testing.T) {
+	resetNormalizeCache(t)
+	input := []int{250, 250}
+
+	if _, err := NormalizePackSizes(input); err != nil {
+		t.Fatalf("NormalizePackSizes returned error: %v", err)
+	}
+
+	setTestConfig(t, func(cfg *Config) { cfg.StrictDuplicates = true })
+	if _, err := NormalizePackSizes(input); !errors.Is(err, ErrInvalidPackSizes) {
+		t.Fatalf("expected strict duplicates to reject a cached input, got %v", err)
+	}
+}
+
+func TestNormalizeCache_Disabled(t *testing.T) {
+	resetNormalizeCache(t)
+	setTestConfig(t, func(cfg *Config) { cfg.NormalizeCacheSize = 0 })
+
+	if _, err := NormalizePackSizes([]int{250, 500}); err != nil {
+		t.Fatalf("NormalizePackSizes returned error: %v", err)
+	}
+	if _, ok := cachedNormalization([]int{250, 500}, DefaultConfig()); ok {
+		t.Fatal("expected nothing cached with the cache disabled")
+	}
+}
+
+func TestNormalizeCache_ConcurrentUse(t *testing.T) {
+	resetNormalizeCache(t)
+	setTestConfig(t, func(cfg *Config) { cfg.NormalizeCacheSize = 4 })
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Go(func() {
+			for i := range 200 {
+				input := []int{250, 500 + (worker+i)%6}
+				normalized, err := NormalizePackSizes(input)
+				if err != nil || len(normalized) != 2 || normalized[1] != 250 {
+					t.Errorf("NormalizePackSizes(%v) = %v, %v", input, normalized, err)
+					return
+				}
+				normalized[0] = 0
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Plan objectives: every plan ships the minimum-overfill total, and the
+// objective names how ties between breakdowns of that total are broken.
+// Options.Objective and Config.DefaultObjective select ObjectiveFewestPacks
+// or ObjectiveFewestSizes; ObjectiveSwitchPenalty is reported when
+// Options.SwitchPenalty is set.
+const (
+	ObjectiveFewestPacks   = "fewest_packs"
+	ObjectiveFewestSizes   = "fewest_sizes"
+	ObjectiveSwitchPenalty = "switch_penalty"
+)
+
+var ErrInvalidObjective = errors.New("unsupported objective")
+
+// resolveObjective returns the objective opts are planned for: the request's
+// Objective, else a SwitchPenalty, else Config.DefaultObjective, else
+// ObjectiveFewestPacks. Split shipments are each planned for the fewest packs,
+// so a default of ObjectiveFewestSizes does not apply to them and requesting
+// it with a shipment cap fails.
+func resolveObjective(opts Options) (string, error) {
+	splitting := opts.MaxItemsPerShipment > 0 || opts.MaxShipmentWeight > 0
+	if opts.Objective != "" {
+		if err := checkObjective(opts.Objective); err != nil {
+			return "", err
+		}
+		if opts.SwitchPenalty > 0 {
+			return "", fmt.Errorf("%w: %q cannot be combined with a switch penalty", ErrInvalidObjective, opts.Objective)
+		}
+		if splitting && opts.Objective == ObjectiveFewestSizes {
+			return "", fmt.Errorf("%w: %q cannot be combined with a shipment cap", ErrInvalidObjective, opts.Objective)
+		}
+		return opts.Objective, nil
+	}
+	if opts.SwitchPenalty > 0 {
+		return ObjectiveSwitchPenalty, nil
+	}
+	if objective := currentConfig().DefaultObjective; objective != "" && !splitting {
+		return objective, nil
+	}
+	return ObjectiveFewestPacks, nil
+}
+
+func checkObjective(objective string) error {
+	if objective != ObjectiveFewestPacks && objective != ObjectiveFewestSizes {
+		return fmt.Errorf("%w: %q (want %q or %q)", ErrInvalidObjective, objective, ObjectiveFewestPacks, ObjectiveFewestSizes)
+	}
+	return nil
+}
+
+// applyFewestSizes replaces plan's packs with the combination reaching the
+// same total with the fewest distinct sizes, ties going to fewer packs. It is
+// a switch penalty larger than any pack count: no plan ships more packs than
+// items.
+func applyFewestSizes(plan *Plan, maxPacks int, sortedPackSizes []int) error {
+	return applySwitchPenalty(plan, plan.TotalItems+1, maxPacks, sortedPackSizes)
+}
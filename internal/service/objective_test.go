@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeWithOptions_ObjectivePrecedence(t *testing.T) {
+	fewestPacks := []PackBreakdown{{Size: 6, Count: 2}, {Size: 4, Count: 2}}
+	fewestSizes := []PackBreakdown{{Size: 4, Count: 5}}
+
+	tests := []struct {
+		name          string
+		defaultObj    string
+		objective     string
+		wantObjective string
+		wantPacks     []PackBreakdown
+	}{
+		{name: "neither", wantObjective: ObjectiveFewestPacks, wantPacks: fewestPacks},
+		{name: "server default only", defaultObj: ObjectiveFewestSizes, wantObjective: ObjectiveFewestSizes, wantPacks: fewestSizes},
+		{name: "request only", objective: ObjectiveFewestSizes, wantObjective: ObjectiveFewestSizes, wantPacks: fewestSizes},
+		{name: "request overrides server default", defaultObj: ObjectiveFewestSizes, objective: ObjectiveFewestPacks, wantObjective: ObjectiveFewestPacks, wantPacks: fewestPacks},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setTestConfig(t, func(cfg *Config) { cfg.DefaultObjective = tc.defaultObj })
+			setOptimizerPackSizes(t, []int{4, 6})
+
+			plan, err := OptimizeWithOptions(context.Background(), 20, Options{Objective: tc.objective, EchoInput: true})
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+			if !reflect.DeepEqual(plan.Packs, tc.wantPacks) {
+				t.Fatalf("Packs = %v, want %v", plan.Packs, tc.wantPacks)
+			}
+			if plan.TotalItems != 20 || plan.TotalPacks != TotalPhysicalPacks(tc.wantPacks) {
+				t.Fatalf("unexpected totals: %+v", plan)
+			}
+			if plan.Input.Objective != tc.wantObjective {
+				t.Fatalf("Input.Objective = %q, want %q", plan.Input.Objective, tc.wantObjective)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_DefaultObjectiveSkipsSplitShipments(t *testing.T) {
+	setTestConfig(t, func(cfg *Config) { cfg.DefaultObjective = ObjectiveFewestSizes })
+	setOptimizerPackSizes(t, []int{4, 6})
+
+	plan, err := OptimizeWithOptions(context.Background(), 20, Options{MaxItemsPerShipment: 12, EchoInput: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if plan.Input.Objective != ObjectiveFewestPacks || plan.TotalPacks != 4 {
+		t.Fatalf("expected a fewest-packs split, got %+v", plan)
+	}
+}
+
+// A server default of fewest_sizes must not fail large orders the order range
+// accepts: past the enumeration's work bound they keep the fewest packs.
+func TestOptimizeWithOptions_DefaultObjectiveLargeOrder(t *testing.T) {
+	setTestConfig(t, func(cfg *Config) { cfg.DefaultObjective = ObjectiveFewestSizes })
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	plan, err := OptimizeWithOptions(context.Background(), 1_000_001, Options{EchoInput: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	want, err := OptimizeWith(1_000_001, []int{250, 500, 1000, 2000, 5000})
+	if err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+	if !plan.ObjectiveFallback || plan.Input.Objective != ObjectiveFewestPacks {
+		t.Fatalf("ObjectiveFallback = %t, Input.Objective = %q, want a fewest-packs fallback", plan.ObjectiveFallback, plan.Input.Objective)
+	}
+	if !reflect.DeepEqual(plan.Packs, want.Packs) {
+		t.Fatalf("Packs = %v, want the fewest packs %v", plan.Packs, want.Packs)
+	}
+
+	// Small orders still get the default, and an explicit objective is
+	// never replaced.
+	small, err := OptimizeWithOptions(context.Background(), 12001, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if small.ObjectiveFallback {
+		t.Fatalf("small order fell back: %+v", small)
+	}
+	_, err = OptimizeWithOptions(context.Background(), 1_000_001, Options{Objective: ObjectiveFewestSizes})
+	if !errors.Is(err, ErrOptimizationTooLarge) {
+		t.Fatalf("explicit objective error = %v, want ErrOptimizationTooLarge", err)
+	}
+}
+
+func TestOptimizeWithOptions_InvalidObjective(t *testing.T) {
+	setOptimizerPackSizes(t, []int{4, 6})
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "unknown", opts: Options{Objective: "cheapest"}},
+		{name: "switch penalty by name", opts: Options{Objective: ObjectiveSwitchPenalty}},
+		{name: "with a switch penalty", opts: Options{Objective: ObjectiveFewestPacks, SwitchPenalty: 2}},
+		{name: "fewest sizes with a shipment cap", opts: Options{Objective: ObjectiveFewestSizes, MaxItemsPerShipment: 12}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := OptimizeWithOptions(context.Background(), 20, tc.opts); !errors.Is(err, ErrInvalidObjective) {
+				t.Fatalf("expected ErrInvalidObjective, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv_DefaultObjective(t *testing.T) {
+	t.Setenv("DEFAULT_OBJECTIVE", ObjectiveFewestSizes)
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv returned error: %v", err)
+	}
+	if cfg.DefaultObjective != ObjectiveFewestSizes {
+		t.Fatalf("DefaultObjective = %q, want %q", cfg.DefaultObjective, ObjectiveFewestSizes)
+	}
+
+	t.Setenv("DEFAULT_OBJECTIVE", ObjectiveSwitchPenalty)
+	if _, err := ConfigFromEnv(); !errors.Is(err, ErrInvalidObjective) {
+		t.Fatalf("expected ErrInvalidObjective, got %v", err)
+	}
+}
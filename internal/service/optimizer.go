@@ -1,9 +1,18 @@
 package service
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const maxInt32Value = math.MaxInt32
@@ -12,26 +21,357 @@ var (
 	ErrInvalidItemsOrdered  = errors.New("items_ordered must be greater than zero")
 	ErrInvalidPackSizes     = errors.New("pack_sizes must contain at least one positive integer")
 	ErrOptimizationTooLarge = errors.New("optimization range is too large")
+	ErrPackSizeTooLarge     = errors.New("pack size exceeds the configured maximum")
+	ErrTooManyPackSizes     = errors.New("too many pack sizes")
+	ErrMaxTotalUnreachable  = errors.New("no reachable total within max_total")
+	ErrOverfillExceeded     = errors.New("no plan within the allowed overfill")
+	ErrInvalidMaxOverfill   = errors.New("max overfill must not be negative")
+	ErrTooManyPacks         = errors.New("plan needs more packs than allowed")
+	ErrInvalidMaxPacks      = errors.New("max packs must be greater than zero")
+	ErrInvalidTableLimit    = errors.New("table entry limit out of range")
 	errReconstructPlan      = errors.New("unable to reconstruct packing combination")
 )
 
 const maxTableEntries = 2_000_000
 
+// maxTableEntriesCeiling is the hard ceiling of Options.MaxTableEntries: a
+// table this size takes about 240 MB while it is built.
+const maxTableEntriesCeiling = 10_000_000
+
+// ctxCheckInterval is how many totals the DP fills between cancellation checks.
+const ctxCheckInterval = 1 << 16
+
+// maxExplainGapTotals bounds how many unreachable totals an explanation lists
+// so large gaps cannot blow up the response size.
+const maxExplainGapTotals = 100
+
+// PackBreakdown is one run of a run-length encoded plan: Count packs of Size.
+// Plans list each size at most once with a positive count, so a breakdown
+// stays as small as the catalog however many packs ship (e.g. 9434 packs of
+// 53 are one entry). Packs are never flattened into one entry per pack.
 type PackBreakdown struct {
 	Size  int `json:"size"`
 	Count int `json:"count"`
+	// SKU is the pack size's label in the catalog (see SetPackSKUs), set by
+	// OptimizeWithOptions and omitted when the size has none.
+	SKU string `json:"sku,omitempty"`
+}
+
+// TotalPhysicalPacks returns the number of packs a breakdown ships, without
+// materializing them.
+func TotalPhysicalPacks(packs []PackBreakdown) int {
+	total := 0
+	for _, pack := range packs {
+		total += pack.Count
+	}
+	return total
 }
 
+// Values of Plan.Algorithm.
+const (
+	// AlgorithmDP is the exact dynamic program over every total.
+	AlgorithmDP = "dp"
+	// AlgorithmGreedyDivisible is the exact greedy fill of catalogs where
+	// every size divides the next larger one (see divisiblePlan).
+	AlgorithmGreedyDivisible = "greedy-divisible"
+	// AlgorithmGreedyApprox is OptimizeGreedy's largest-pack-first heuristic.
+	AlgorithmGreedyApprox = "greedy-approx"
+	// AlgorithmShipmentSplit is the fill-to-the-cap split of
+	// splitIntoShipments; each shipment is still solved by the DP.
+	AlgorithmShipmentSplit = "shipment-split"
+)
+
+// Values of Plan.Status, so clients can branch on one field instead of
+// inferring the outcome from Optimal and Shipments.
+const (
+	// PlanStatusOptimal is a provably optimal plan.
+	PlanStatusOptimal = "optimal"
+	// PlanStatusApproximate is a heuristic plan (see OptimizeGreedy).
+	PlanStatusApproximate = "approximate"
+	// PlanStatusCapped is a plan split into shipments by a shipment cap,
+	// which may overfill more than an unconstrained plan.
+	PlanStatusCapped = "capped"
+	// PlanStatusInfeasibleExact is never set on a plan: it is the status the
+	// API reports for ErrOverfillExceeded, when no plan fits the allowed
+	// overfill (none at all for exact-only requests).
+	PlanStatusInfeasibleExact = "infeasible_exact"
+)
+
 type Plan struct {
 	ItemsOrdered int             `json:"items_ordered"`
 	TotalItems   int             `json:"total_items"`
 	TotalPacks   int             `json:"total_packs"`
-	Packs        []PackBreakdown `json:"packs"`
+	Packs        []PackBreakdown `json:"packs,omitempty"`
+	// Overfill is TotalItems - ItemsOrdered, never negative.
+	Overfill int `json:"overfill"`
+	// WastePercent is Overfill as a percentage of TotalItems, rounded to two
+	// decimals.
+	WastePercent float64 `json:"waste_percent"`
+	// OverfillSource is the pack size whose addition pushed the total past
+	// the order; it is zero (omitted) when there is no overfill.
+	OverfillSource int `json:"overfill_source,omitempty"`
+	// DrivingSize is the pack whose addition first reached the chosen total,
+	// i.e. the last step of the DP path that produced the plan.
+	DrivingSize int `json:"driving_size"`
+	// FillerPacks is how many packs of the plan come from filler sizes; it is
+	// only set by OptimizeTwoTier.
+	FillerPacks int `json:"filler_packs,omitempty"`
+	// Optimal is true when the plan is provably optimal (exact DP) and false
+	// when it comes from a heuristic or approximate path.
+	Optimal bool `json:"optimal"`
+	// Algorithm names the path that produced the plan: AlgorithmDP,
+	// AlgorithmGreedyDivisible or AlgorithmGreedyApprox.
+	Algorithm string `json:"algorithm,omitempty"`
+	// Status summarizes the outcome of an optimization: PlanStatusOptimal,
+	// PlanStatusApproximate or PlanStatusCapped (see setPlanStatus). It is
+	// not set on shipments or on plans of other endpoints.
+	Status string `json:"status,omitempty"`
+	// ObjectiveFallback reports that Config.DefaultObjective was
+	// ObjectiveFewestSizes but the order exceeds its work bound, so the plan
+	// keeps the fewest packs instead of failing.
+	ObjectiveFallback bool `json:"objective_fallback,omitempty"`
+	// OriginalItemsOrdered is the order before it was snapped to an exactly
+	// fulfillable total; it is only set when the order was snapped (see
+	// Options.SnapToExact and Options.PreferExactWithin).
+	OriginalItemsOrdered int `json:"original_items_ordered,omitempty"`
+	// Shipments lists the per-shipment plans when Options.MaxItemsPerShipment
+	// is set; the plan's totals and packs are then their sums.
+	Shipments []Plan `json:"shipments,omitempty"`
+	// Description is a customer-facing sentence summarizing the plan; it is
+	// filled in by the presentation layer on request.
+	Description string `json:"description,omitempty"`
+	// NearestExactBelow and NearestExactAbove are the closest exactly
+	// fulfillable totals at or below and at or above the order, set with
+	// Options.NearestExact when within maxNearestExactWindow items of it.
+	NearestExactBelow *int `json:"nearest_exact_below,omitempty"`
+	NearestExactAbove *int `json:"nearest_exact_above,omitempty"`
+	// DisplayTotalItems is TotalItems rounded to the nearest multiple of
+	// Options.DisplayUnit, for display only; TotalItems stays the true total.
+	DisplayTotalItems *int `json:"display_total_items,omitempty"`
+	// PacksSavedVsNaive is how many fewer packs the plan uses than filling
+	// its total with the smallest pack only; set with Options.Savings.
+	PacksSavedVsNaive *int `json:"packs_saved_vs_naive,omitempty"`
+	// ComputedAt is the RFC3339 UTC time the plan was served; it is only set
+	// with Options.Timestamp.
+	ComputedAt string `json:"computed_at,omitempty"`
+	// Weight is TotalItems times Options.ItemWeight; it is only set with
+	// Options.ItemWeight.
+	Weight int `json:"weight,omitempty"`
+	// PackFill shows how much of each pack counts toward the order rather
+	// than overfill; it is only set with Options.FillVsOrder.
+	PackFill []PackFill `json:"pack_fill,omitempty"`
+	// PlanID identifies the inputs the plan was served for: the order, the
+	// catalog and its version, and the options (see planID). Identical
+	// requests get the same ID; it is only set for the configured catalog.
+	PlanID string `json:"plan_id,omitempty"`
+	// Input echoes the effective request; it is only set with
+	// Options.EchoInput.
+	Input *PlanInput `json:"input,omitempty"`
+	// Pallets is set when Options.PalletCapacity is used.
+	Pallets     *PalletBreakdown `json:"pallets,omitempty"`
+	Explanation *Explanation     `json:"explanation,omitempty"`
+	Usage       *ResourceUsage   `json:"usage,omitempty"`
+}
+
+// Explanation describes why a plan ships more items than were ordered.
+type Explanation struct {
+	// GapTotals lists the totals between the order and the chosen total that
+	// no combination of whole packs can reach, in ascending order.
+	GapTotals []int `json:"gap_totals"`
+	// GapTotalsTruncated reports whether GapTotals was cut at maxExplainGapTotals.
+	GapTotalsTruncated bool `json:"gap_totals_truncated"`
+}
+
+// ResourceUsage reports the approximate cost of computing a plan.
+type ResourceUsage struct {
+	// ComputeMicros is the time spent optimizing. The computation is
+	// single-threaded and CPU-bound, so it approximates CPU time.
+	ComputeMicros int64 `json:"compute_micros"`
+	// TableBytes is the memory allocated for the DP table by this request;
+	// it is zero when a cached table or a cached plan served the order.
+	TableBytes int `json:"table_bytes"`
 }
 
+// Options tunes a single optimization without changing the configured pack sizes.
+type Options struct {
+	// Explain attaches an Explanation to the returned plan.
+	Explain bool
+	// Usage attaches the ResourceUsage of the optimization to the returned plan.
+	Usage bool
+	// SnapToExact rounds the order up to the next exactly fulfillable total, so
+	// the reported order equals the shipped total.
+	SnapToExact bool
+	// PreferExactWithin snaps the order like SnapToExact, but only when the
+	// next exactly fulfillable total is at most this many items above the
+	// order. Otherwise the order is kept and the plan reports the overfill.
+	// Because only whole packs ship, that total is always the one with minimum
+	// overfill, so the shipped packs never change; only the order does.
+	// Zero disables it.
+	PreferExactWithin int
+	// SortByCount orders Packs by count descending, ties by size descending,
+	// instead of by size.
+	SortByCount bool
+	// MaxItemsPerShipment splits the order into shipments of at most this many
+	// items each (see splitIntoShipments). Zero means a single shipment.
+	MaxItemsPerShipment int
+	// ItemWeight and MaxShipmentWeight split the order like
+	// MaxItemsPerShipment, with a cap of MaxShipmentWeight/ItemWeight items:
+	// shipped items, overfill included, weigh ItemWeight each. With both caps
+	// set, the tighter one applies. Zero disables them; both must be set.
+	ItemWeight        int
+	MaxShipmentWeight int
+	// MaxTotal caps the shipped total: the plan must ship a total within
+	// [itemsOrdered, MaxTotal], or ErrMaxTotalUnreachable is returned. Zero
+	// means no cap.
+	MaxTotal int
+	// LimitOverfill caps the overfill at MaxOverfill items (zero accepts exact
+	// plans only): a plan over it fails with ErrOverfillExceeded.
+	LimitOverfill bool
+	MaxOverfill   int
+	// MaxPacks caps the physical packs a plan may ship. The fewest-pack plan
+	// is still chosen; ErrTooManyPacks is returned only when even it exceeds
+	// the cap. Zero means no cap.
+	MaxPacks int
+	// PalletCapacity attaches a PalletBreakdown of the plan's packs onto
+	// pallets of this many items (see palletize). Zero disables it.
+	PalletCapacity int
+	// SwitchPenalty models the cost of switching pack sizes while picking:
+	// among the combinations reaching the minimum-overfill total, the one
+	// minimizing packs + SwitchPenalty*distinct sizes is shipped (see
+	// applySwitchPenalty). Zero keeps the fewest packs.
+	SwitchPenalty int
+	// Objective breaks ties between breakdowns of the minimum-overfill total:
+	// ObjectiveFewestPacks or ObjectiveFewestSizes. Empty uses
+	// Config.DefaultObjective (see resolveObjective).
+	Objective string
+	// NearestExact sets Plan.NearestExactBelow and Plan.NearestExactAbove so
+	// clients can suggest exact order quantities.
+	NearestExact bool
+	// DisplayUnit sets Plan.DisplayTotalItems, rounding halves up. Zero
+	// disables it.
+	DisplayUnit int
+	// Savings sets Plan.PacksSavedVsNaive.
+	Savings bool
+	// Timestamp sets Plan.ComputedAt for audit trails.
+	Timestamp bool
+	// FillVsOrder sets Plan.PackFill (see packFill).
+	FillVsOrder bool
+	// EchoInput sets Plan.Input for the configured catalog.
+	EchoInput bool
+	// MaxTableEntries raises (or lowers) the DP table limit for this
+	// optimization, up to maxTableEntriesCeiling, for trusted callers that
+	// need larger orders. Zero keeps maxTableEntries.
+	MaxTableEntries int
+	// SkipBreakdown leaves Plan.Packs (and each shipment's) empty for callers
+	// that only need the totals. The packs are still reconstructed, and
+	// counted in the pack usage statistics, before they are left out.
+	SkipBreakdown bool
+}
+
+// clock returns the current time; tests replace it to get deterministic
+// timestamps.
+var clock = time.Now
+
 // Optimize computes the fulfillment plan that meets or exceeds itemsOrdered
 // with minimum overfill and, for that total, the minimum number of packs.
 func Optimize(itemsOrdered int) (Plan, error) {
+	return OptimizeWithOptions(context.Background(), itemsOrdered, Options{})
+}
+
+// OptimizeWithOptions behaves like Optimize and applies opts to the result.
+// ctx carries the trace the optimization phases are recorded under and
+// cancels the DP like OptimizeContext. Served
+// plans are counted in the pack usage statistics (see PackUsage). It fails
+// with ErrCatalogReloading while a catalog reload is in progress.
+func OptimizeWithOptions(ctx context.Context, itemsOrdered int, opts Options) (Plan, error) {
+	if CatalogReloading() {
+		return Plan{}, ErrCatalogReloading
+	}
+
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	packSizes, version := packSizeService.GetCatalog()
+	plan, err := optimizeTraced(ctx, itemsOrdered, packSizes, opts)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan.PlanID = planID(itemsOrdered, packSizes, version, opts)
+	if opts.EchoInput {
+		plan.Input = newPlanInput(itemsOrdered, plan, packSizes, version, opts)
+	}
+	LabelPacks(&plan)
+
+	recordPackUsage(plan)
+	return servedPlan(plan, opts), nil
+}
+
+// omitBreakdown returns plan without the packs of its breakdown and
+// shipments, for Options.SkipBreakdown. plan may be shared: its shipments are
+// copied rather than modified.
+func omitBreakdown(plan Plan) Plan {
+	plan.Packs = nil
+	plan.Shipments = slices.Clone(plan.Shipments)
+	for i := range plan.Shipments {
+		plan.Shipments[i].Packs = nil
+	}
+	return plan
+}
+
+// OptimizeWithLimit behaves like OptimizeWith but fails with
+// ErrOverfillExceeded instead of shipping more than itemsOrdered+maxOverfill
+// items. A zero maxOverfill accepts exact plans only.
+func OptimizeWithLimit(itemsOrdered int, packSizes []int, maxOverfill int) (Plan, error) {
+	return optimizeTraced(context.Background(), itemsOrdered, packSizes, Options{LimitOverfill: true, MaxOverfill: maxOverfill})
+}
+
+// OptimizeWithMaxPacks behaves like OptimizeWith but fails with
+// ErrTooManyPacks when even the fewest-pack plan ships more than maxPacks packs.
+func OptimizeWithMaxPacks(itemsOrdered int, packSizes []int, maxPacks int) (Plan, error) {
+	if maxPacks <= 0 {
+		return Plan{}, fmt.Errorf("%w: got %d", ErrInvalidMaxPacks, maxPacks)
+	}
+	return optimizeTraced(context.Background(), itemsOrdered, packSizes, Options{MaxPacks: maxPacks})
+}
+
+// OptimizeWith computes the same plan as Optimize against explicit packSizes
+// instead of the configured ones.
+func OptimizeWith(itemsOrdered int, packSizes []int) (Plan, error) {
+	return OptimizeContext(context.Background(), itemsOrdered, packSizes)
+}
+
+// OptimizeContext is OptimizeWith with cancellation: the DP checks ctx every
+// ctxCheckInterval totals and gives up with ctx.Err(), so work for a client
+// that went away stops promptly.
+func OptimizeContext(ctx context.Context, itemsOrdered int, packSizes []int) (Plan, error) {
+	return optimizeTraced(ctx, itemsOrdered, packSizes, Options{})
+}
+
+func optimizeTraced(ctx context.Context, itemsOrdered int, packSizes []int, opts Options) (Plan, error) {
+	ctx, span := tracer().Start(ctx, "service.Optimize", trace.WithAttributes(
+		attribute.Int("items_ordered", itemsOrdered),
+	))
+	defer span.End()
+
+	plan, err := optimize(ctx, itemsOrdered, packSizes, opts)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return Plan{}, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("total_items", plan.TotalItems),
+		attribute.Int("total_packs", plan.TotalPacks),
+	)
+	return plan, nil
+}
+
+func optimize(ctx context.Context, itemsOrdered int, packSizes []int, opts Options) (Plan, error) {
+	start := time.Now()
+
 	if itemsOrdered <= 0 {
 		return Plan{}, ErrInvalidItemsOrdered
 	}
@@ -39,29 +379,245 @@ func Optimize(itemsOrdered int) (Plan, error) {
 		return Plan{}, fmt.Errorf("%w: %d exceeds max value %d", ErrInvalidItemsOrdered, itemsOrdered, maxInt32Value)
 	}
 
-	packSizeService, err := GetPackSizeService()
+	normalized, err := NormalizePackSizes(packSizes)
 	if err != nil {
 		return Plan{}, err
 	}
-
-	packSizes := packSizeService.GetPackSizes()
-
-	normalized, err := NormalizePackSizes(packSizes)
+	objective, err := resolveObjective(opts)
 	if err != nil {
 		return Plan{}, err
 	}
-
-	table, err := newPackingTable(itemsOrdered, normalized)
+	capacity, err := shipmentCapacity(opts, normalized)
 	if err != nil {
 		return Plan{}, err
 	}
-	table.buildOptimalPackingTable()
+	tableLimit := maxTableEntries
+	if opts.MaxTableEntries != 0 {
+		if opts.MaxTableEntries < 0 || opts.MaxTableEntries > maxTableEntriesCeiling {
+			return Plan{}, fmt.Errorf("%w: %d must be between 1 and %d", ErrInvalidTableLimit, opts.MaxTableEntries, maxTableEntriesCeiling)
+		}
+		tableLimit = opts.MaxTableEntries
+	}
+
+	// Explanations and nearest exact totals need the table, so they always
+	// bypass the result cache. Plans only a raised table limit allows are
+	// never stored: the cache would serve them to callers without it.
+	plan, hit := Plan{}, false
+	if !opts.Explain && !opts.NearestExact {
+		plan, hit = cachedPlan(itemsOrdered, normalized)
+	}
+	cacheable := tableLimit <= maxTableEntries
+
+	var table packingTable
+	tableCached := true
+	switch {
+	case hit:
+	case !opts.Explain && !opts.NearestExact && useDivisiblePlan(itemsOrdered, normalized, tableLimit):
+		plan = divisiblePlan(itemsOrdered, normalized)
+		if cacheable {
+			storePlan(plan, normalized)
+		}
+	default:
+		// Served plans always carry their breakdown: pack usage counts it
+		// even when SkipBreakdown leaves it out of the response.
+		plan, table, tableCached, err = computePlan(ctx, itemsOrdered, normalized, tableLimit, true)
+		if err != nil {
+			return Plan{}, err
+		}
+		if cacheable {
+			storePlan(plan, normalized)
+		}
+	}
 
+	if opts.Explain {
+		// Explains the single-shipment optimum, before any split.
+		plan.Explanation = table.explain(plan.TotalItems)
+	}
+	if opts.NearestExact {
+		plan.NearestExactBelow, plan.NearestExactAbove = table.nearestExact(plan.TotalItems)
+	}
+	if objective == ObjectiveFewestSizes && opts.Objective == "" {
+		// The server default never fails an order the catalog can plan: past
+		// the enumeration's work bound it keeps the fewest packs instead.
+		if _, err := switchPenaltyCandidates(plan.TotalItems, normalized); err != nil {
+			objective = ObjectiveFewestPacks
+			plan.ObjectiveFallback = true
+		}
+	}
+	switch objective {
+	case ObjectiveSwitchPenalty:
+		if err := applySwitchPenalty(&plan, opts.SwitchPenalty, opts.MaxPacks, normalized); err != nil {
+			return Plan{}, err
+		}
+	case ObjectiveFewestSizes:
+		if err := applyFewestSizes(&plan, opts.MaxPacks, normalized); err != nil {
+			return Plan{}, err
+		}
+	}
+	if capacity > 0 {
+		if err := applyShipmentCap(&plan, capacity, normalized); err != nil {
+			return Plan{}, err
+		}
+	}
+	if opts.MaxTotal > 0 && plan.TotalItems > opts.MaxTotal {
+		return Plan{}, fmt.Errorf("%w: the smallest total for %d items is %d, above %d", ErrMaxTotalUnreachable, itemsOrdered, plan.TotalItems, opts.MaxTotal)
+	}
+	if opts.MaxPacks > 0 && plan.TotalPacks > opts.MaxPacks {
+		return Plan{}, fmt.Errorf("%w: the fewest packs for %d items is %d, above %d", ErrTooManyPacks, itemsOrdered, plan.TotalPacks, opts.MaxPacks)
+	}
+	if opts.LimitOverfill {
+		if opts.MaxOverfill < 0 {
+			return Plan{}, fmt.Errorf("%w: got %d", ErrInvalidMaxOverfill, opts.MaxOverfill)
+		}
+		if overfill := plan.TotalItems - itemsOrdered; overfill > opts.MaxOverfill {
+			return Plan{}, fmt.Errorf("%w: the smallest total for %d items is %d, overfilling by %d (max %d)", ErrOverfillExceeded, itemsOrdered, plan.TotalItems, overfill, opts.MaxOverfill)
+		}
+	}
+	if opts.SortByCount {
+		sortPacksByCount(plan.Packs)
+		for _, shipment := range plan.Shipments {
+			sortPacksByCount(shipment.Packs)
+		}
+	}
+	if opts.SnapToExact || (opts.PreferExactWithin > 0 && plan.TotalItems-itemsOrdered <= opts.PreferExactWithin) {
+		// TotalItems is already the smallest reachable total >= itemsOrdered.
+		plan.OriginalItemsOrdered = itemsOrdered
+		plan.ItemsOrdered = plan.TotalItems
+	}
+	if opts.PalletCapacity > 0 {
+		// Packs may have been reordered by SortByCount; palletize needs size order.
+		bySize := slices.Clone(plan.Packs)
+		slices.SortFunc(bySize, func(a, b PackBreakdown) int { return cmp.Compare(b.Size, a.Size) })
+		plan.Pallets = palletize(bySize, opts.PalletCapacity)
+	}
+	setOverfillMetrics(&plan)
+	for i := range plan.Shipments {
+		setOverfillMetrics(&plan.Shipments[i])
+	}
+	setPlanStatus(&plan)
+	if opts.ItemWeight > 0 {
+		plan.Weight = plan.TotalItems * opts.ItemWeight
+		for i := range plan.Shipments {
+			plan.Shipments[i].Weight = plan.Shipments[i].TotalItems * opts.ItemWeight
+		}
+	}
+	if opts.FillVsOrder {
+		plan.PackFill = packFill(plan)
+	}
+	if opts.DisplayUnit > 0 {
+		display := (plan.TotalItems + opts.DisplayUnit/2) / opts.DisplayUnit * opts.DisplayUnit
+		plan.DisplayTotalItems = &display
+	}
+	if opts.Savings {
+		plan.PacksSavedVsNaive = packsSavedVsNaive(plan, normalized)
+	}
+	if opts.Timestamp {
+		plan.ComputedAt = clock().UTC().Format(time.RFC3339)
+	}
+	if opts.Usage {
+		plan.Usage = &ResourceUsage{
+			ComputeMicros: time.Since(start).Microseconds(),
+		}
+		if !tableCached {
+			plan.Usage.TableBytes = table.sizeBytes()
+		}
+	}
+
+	return plan, nil
+}
+
+// setPlanStatus fills Plan.Status from the plan's final shape: a split into
+// several shipments is capped, otherwise Optimal decides.
+func setPlanStatus(plan *Plan) {
+	switch {
+	case len(plan.Shipments) > 1:
+		plan.Status = PlanStatusCapped
+	case plan.Optimal:
+		plan.Status = PlanStatusOptimal
+	default:
+		plan.Status = PlanStatusApproximate
+	}
+}
+
+// setOverfillMetrics fills Plan.Overfill, Plan.WastePercent and
+// Plan.OverfillSource from the plan's final totals, after options such as
+// snapping changed them.
+//
+// Totals along the reconstructed path increase and are all reachable, so none
+// before the last can reach the order: it would be a smaller fulfillable total
+// than the chosen one. The pack that crossed the order is therefore always the
+// last step, DrivingSize.
+func setOverfillMetrics(plan *Plan) {
+	plan.Overfill = max(plan.TotalItems-plan.ItemsOrdered, 0)
+	plan.OverfillSource = 0
+	if plan.Overfill > 0 {
+		plan.OverfillSource = plan.DrivingSize
+	}
+	plan.WastePercent = 0
+	if plan.TotalItems > 0 {
+		plan.WastePercent = math.Round(float64(plan.Overfill)*10000/float64(plan.TotalItems)) / 100
+	}
+}
+
+// packsSavedVsNaive compares plan with the naive baseline of shipping its
+// total in the smallest pack only, ceil(total/smallest) packs.
+func packsSavedVsNaive(plan Plan, sortedPackSizes []int) *int {
+	smallest := sortedPackSizes[len(sortedPackSizes)-1]
+	naivePacks := (plan.TotalItems + smallest - 1) / smallest
+	saved := naivePacks - plan.TotalPacks
+	return &saved
+}
+
+// testTableHook, when set by tests, can alter the table between the DP and
+// the backtrack, e.g. to corrupt pointers and exercise verifyBreakdown.
+var testTableHook func(*packingTable)
+
+// computePlan runs the DP for itemsOrdered, reusing the shared table when it
+// covers the order. cached reports whether it did. A new table may hold up to
+// tableLimit entries. A DP build is abandoned with ctx.Err() once ctx is done.
+// Without withBreakdown the backtrack is skipped and the plan has no Packs;
+// its totals and DrivingSize come straight from the table.
+func computePlan(ctx context.Context, itemsOrdered int, sortedPackSizes []int, tableLimit int, withBreakdown bool) (plan Plan, table packingTable, cached bool, err error) {
+	planComputations.Add(1)
+
+	_, buildSpan := tracer().Start(ctx, "service.buildPackingTable")
+	table, cached = cachedTableFor(itemsOrdered, sortedPackSizes)
+	if !cached {
+		table, err = newPackingTableLimit(itemsOrdered, sortedPackSizes, tableLimit)
+		if err != nil {
+			buildSpan.End()
+			return Plan{}, packingTable{}, false, err
+		}
+		if err := table.buildOptimalPackingTableContext(ctx); err != nil {
+			buildSpan.End()
+			return Plan{}, packingTable{}, false, err
+		}
+	}
+	buildSpan.SetAttributes(
+		attribute.Int("table_entries", len(table.minPacks)),
+		attribute.Bool("table_cached", cached),
+	)
+	buildSpan.End()
+
+	_, chooseSpan := tracer().Start(ctx, "service.chooseFulfillmentTotal")
 	chosenTotal := table.chooseFulfillmentTotal()
+	chooseSpan.End()
 
-	breakdown, err := table.buildBreakdown(chosenTotal)
-	if err != nil {
-		return Plan{}, err
+	if testTableHook != nil {
+		testTableHook(&table)
+	}
+
+	var breakdown []PackBreakdown
+	if withBreakdown {
+		_, breakdownSpan := tracer().Start(ctx, "service.buildBreakdown")
+		breakdown, err = table.buildBreakdown(chosenTotal)
+		if err == nil {
+			err = table.verifyBreakdown(chosenTotal, breakdown)
+		}
+		breakdownSpan.End()
+		if err != nil {
+			return Plan{}, packingTable{}, false, err
+		}
 	}
 
 	return Plan{
@@ -69,7 +625,10 @@ func Optimize(itemsOrdered int) (Plan, error) {
 		TotalItems:   chosenTotal,
 		TotalPacks:   table.minPacks[chosenTotal],
 		Packs:        breakdown,
-	}, nil
+		DrivingSize:  table.prevPack[chosenTotal],
+		Optimal:      true,
+		Algorithm:    AlgorithmDP,
+	}, table, cached, nil
 }
 
 type packingTable struct {
@@ -87,6 +646,12 @@ type packingTable struct {
 // All totals start as unreachable except total=0 (the base case), and
 // backtracking pointers are seeded so buildBreakdown can reconstruct a valid plan.
 func newPackingTable(itemsOrdered int, sortedPackSizes []int) (packingTable, error) {
+	return newPackingTableLimit(itemsOrdered, sortedPackSizes, maxTableEntries)
+}
+
+// newPackingTableLimit is newPackingTable with a table of up to tableLimit
+// entries instead of maxTableEntries.
+func newPackingTableLimit(itemsOrdered int, sortedPackSizes []int, tableLimit int) (packingTable, error) {
 	// unset marks entries that do not have a predecessor yet.
 	const unset = -1
 
@@ -101,8 +666,8 @@ func newPackingTable(itemsOrdered int, sortedPackSizes []int) (packingTable, err
 	if fulfillmentLimit64 <= 0 {
 		return packingTable{}, fmt.Errorf("%w: invalid fulfillment range", ErrOptimizationTooLarge)
 	}
-	if fulfillmentLimit64+1 > maxTableEntries {
-		return packingTable{}, fmt.Errorf("%w: requires %d table entries (max %d)", ErrOptimizationTooLarge, fulfillmentLimit64+1, maxTableEntries)
+	if fulfillmentLimit64+1 > int64(tableLimit) {
+		return packingTable{}, fmt.Errorf("%w: requires %d table entries (max %d)", ErrOptimizationTooLarge, fulfillmentLimit64+1, tableLimit)
 	}
 	fulfillmentLimit := int(fulfillmentLimit64)
 
@@ -137,10 +702,30 @@ func newPackingTable(itemsOrdered int, sortedPackSizes []int) (packingTable, err
 	}, nil
 }
 
+// sizeBytes returns the memory held by the table's per-total slices.
+func (t *packingTable) sizeBytes() int {
+	const intBytes = strconv.IntSize / 8
+	return (len(t.minPacks) + len(t.prevTotal) + len(t.prevPack)) * intBytes
+}
+
 // buildOptimalPackingTable populates minPacks and backtracking pointers for
 // every reachable total up to fulfillmentLimit.
 func (t *packingTable) buildOptimalPackingTable() {
+	// A background context never cancels, so no error can be returned.
+	_ = t.buildOptimalPackingTableContext(context.Background())
+}
+
+// buildOptimalPackingTableContext is buildOptimalPackingTable with
+// cancellation: it checks ctx every ctxCheckInterval totals and returns
+// ctx.Err() if the build was abandoned, leaving the table incomplete.
+func (t *packingTable) buildOptimalPackingTableContext(ctx context.Context) error {
 	for total := 1; total < len(t.minPacks); total++ {
+		if total%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
 		for _, packSize := range t.sortedPackSizes {
 			predecessor := total - packSize
 			if predecessor < 0 || t.minPacks[predecessor] == t.unreachablePacks {
@@ -157,22 +742,35 @@ func (t *packingTable) buildOptimalPackingTable() {
 			}
 		}
 	}
+
+	return nil
 }
 
 // chooseFulfillmentTotal returns the smallest reachable total that is
-// at least itemsOrdered, satisfying the no-underfill constraint.
+// at least itemsOrdered, satisfying the no-underfill constraint. Since it is
+// the smallest, no other total can satisfy an upper bound it exceeds, which
+// is how Options.MaxTotal and Options.MaxOverfill are enforced: rejecting it
+// is the same as stopping the scan at the bound.
 func (t *packingTable) chooseFulfillmentTotal() int {
-	for total := t.itemsOrdered; total < len(t.minPacks); total++ {
-		if t.minPacks[total] != t.unreachablePacks {
-			// When a total that can be fulfilled is found, return it immediately. This ensures we get the closest fulfillment without going under.
-			return total
-		}
+	// The first total that can be fulfilled is the closest one without going under.
+	if total, ok := t.firstReachable(t.itemsOrdered, t.fulfillmentLimit); ok {
+		return total
 	}
 	// Invariant: at least one total in range is reachable (the next multiple
 	// of the largest pack size is always within fulfillmentLimit).
 	return t.fulfillmentLimit
 }
 
+// firstReachable returns the smallest reachable total in [from, to].
+func (t *packingTable) firstReachable(from, to int) (int, bool) {
+	for total := from; total <= to && total < len(t.minPacks); total++ {
+		if t.minPacks[total] != t.unreachablePacks {
+			return total, true
+		}
+	}
+	return 0, false
+}
+
 // buildBreakdown reconstructs the chosen solution by following prevTotal and
 // prevPack from chosenTotal back to zero, then groups counts by pack size.
 func (t *packingTable) buildBreakdown(chosenTotal int) ([]PackBreakdown, error) {
@@ -194,3 +792,54 @@ func (t *packingTable) buildBreakdown(chosenTotal int) ([]PackBreakdown, error)
 
 	return breakdown, nil
 }
+
+// verifyBreakdown re-sums a reconstructed breakdown and checks it against the
+// DP state, so a backtracking bug fails loudly instead of shipping a plan that
+// does not match its totals. It also enforces the run-length encoding: one
+// entry per size, each with a positive count.
+func (t *packingTable) verifyBreakdown(chosenTotal int, breakdown []PackBreakdown) error {
+	items := 0
+	seen := make(map[int]bool, len(breakdown))
+	for _, pack := range breakdown {
+		if pack.Count <= 0 || seen[pack.Size] {
+			return fmt.Errorf("%w: breakdown is not run-length encoded at size %d", errReconstructPlan, pack.Size)
+		}
+		seen[pack.Size] = true
+		items += pack.Size * pack.Count
+	}
+	packs := TotalPhysicalPacks(breakdown)
+
+	if items != chosenTotal || packs != t.minPacks[chosenTotal] {
+		return fmt.Errorf("%w: breakdown has %d items in %d packs, want %d in %d", errReconstructPlan, items, packs, chosenTotal, t.minPacks[chosenTotal])
+	}
+	return nil
+}
+
+// sortPacksByCount puts the most numerous pack sizes first, breaking ties
+// toward larger sizes. It runs after buildBreakdown so internal ordering is untouched.
+func sortPacksByCount(packs []PackBreakdown) {
+	slices.SortFunc(packs, func(a, b PackBreakdown) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		return cmp.Compare(b.Size, a.Size)
+	})
+}
+
+// explain lists the unreachable totals in [itemsOrdered, chosenTotal), which are
+// the reason the plan overfills. The list is capped at maxExplainGapTotals.
+func (t *packingTable) explain(chosenTotal int) *Explanation {
+	explanation := &Explanation{GapTotals: []int{}}
+	for total := t.itemsOrdered; total < chosenTotal; total++ {
+		if t.minPacks[total] != t.unreachablePacks {
+			continue
+		}
+		if len(explanation.GapTotals) == maxExplainGapTotals {
+			explanation.GapTotalsTruncated = true
+			break
+		}
+		explanation.GapTotals = append(explanation.GapTotals, total)
+	}
+
+	return explanation
+}
@@ -1,8 +1,14 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func setOptimizerPackSizes(t *testing.T, packSizes []int) {
@@ -176,6 +182,7 @@ func TestOptimize_InvalidItemsOrdered(t *testing.T) {
 
 func TestOptimize_BigNumbersReturnsRangeError(t *testing.T) {
 	maxInt32 := int(^uint32(0) >> 1)
+	setTestConfig(t, func(cfg *Config) { cfg.MaxPackSize = maxInt32 })
 	setOptimizerPackSizes(t, []int{maxInt32 - 1000, maxInt32 - 999})
 
 	_, err := Optimize(maxInt32 - 100)
@@ -183,3 +190,733 @@ func TestOptimize_BigNumbersReturnsRangeError(t *testing.T) {
 		t.Fatalf("expected ErrOptimizationTooLarge, got %v", err)
 	}
 }
+
+func TestOptimizeWithOptions_ExplainListsGapTotals(t *testing.T) {
+	setOptimizerPackSizes(t, []int{6, 10})
+
+	plan, err := OptimizeWithOptions(context.Background(), 13, Options{Explain: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	if plan.TotalItems != 16 {
+		t.Fatalf("TotalItems = %d, want 16", plan.TotalItems)
+	}
+	if plan.Explanation == nil {
+		t.Fatal("expected explanation to be set")
+	}
+	if !reflect.DeepEqual(plan.Explanation.GapTotals, []int{13, 14, 15}) {
+		t.Fatalf("GapTotals = %v, want [13 14 15]", plan.Explanation.GapTotals)
+	}
+	if plan.Explanation.GapTotalsTruncated {
+		t.Fatal("expected GapTotalsTruncated to be false")
+	}
+}
+
+func TestOptimizeWithOptions_ExplainTruncatesGapTotals(t *testing.T) {
+	setOptimizerPackSizes(t, []int{1000})
+
+	plan, err := OptimizeWithOptions(context.Background(), 1, Options{Explain: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	if len(plan.Explanation.GapTotals) != maxExplainGapTotals {
+		t.Fatalf("len(GapTotals) = %d, want %d", len(plan.Explanation.GapTotals), maxExplainGapTotals)
+	}
+	if !plan.Explanation.GapTotalsTruncated {
+		t.Fatal("expected GapTotalsTruncated to be true")
+	}
+}
+
+func TestOptimize_OmitsExplanationByDefault(t *testing.T) {
+	setOptimizerPackSizes(t, []int{6, 10})
+
+	plan, err := Optimize(13)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if plan.Explanation != nil {
+		t.Fatalf("expected no explanation, got %+v", plan.Explanation)
+	}
+}
+
+func TestOptimizeWithOptions_UsageReportsTableBytes(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	plan, err := OptimizeWithOptions(context.Background(), 251, Options{Usage: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	if plan.Usage == nil {
+		t.Fatal("expected usage to be set")
+	}
+
+	// fulfillmentLimit = 251 + 5000 - 1, so the table holds 5251 entries in
+	// each of its three int slices.
+	want := 3 * 5251 * (strconv.IntSize / 8)
+	if plan.Usage.TableBytes != want {
+		t.Fatalf("TableBytes = %d, want %d", plan.Usage.TableBytes, want)
+	}
+	if plan.Usage.ComputeMicros < 0 {
+		t.Fatalf("ComputeMicros = %d, want >= 0", plan.Usage.ComputeMicros)
+	}
+}
+
+func TestOptimize_OmitsUsageByDefault(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	plan, err := Optimize(251)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if plan.Usage != nil {
+		t.Fatalf("expected no usage, got %+v", plan.Usage)
+	}
+}
+
+func TestOptimizeWith_IgnoresConfiguredPackSizes(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	plan, err := OptimizeWith(21, []int{10, 20})
+	if err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+	if plan.TotalItems != 30 || plan.TotalPacks != 2 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+}
+
+func TestOptimize_DrivingSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []int
+		ordered   int
+		want      int
+	}{
+		{name: "single small pack", packSizes: []int{250, 500, 1000, 2000, 5000}, ordered: 1, want: 250},
+		{name: "rounds up to the next pack", packSizes: []int{250, 500, 1000, 2000, 5000}, ordered: 251, want: 500},
+		{name: "mixed plan", packSizes: []int{250, 500, 1000, 2000, 5000}, ordered: 501, want: 500},
+		{name: "large order", packSizes: []int{250, 500, 1000, 2000, 5000}, ordered: 12001, want: 5000},
+		{name: "non-standard packs", packSizes: []int{6, 10}, ordered: 13, want: 10},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, tc.packSizes)
+
+			plan, err := Optimize(tc.ordered)
+			if err != nil {
+				t.Fatalf("Optimize returned error: %v", err)
+			}
+			if plan.DrivingSize != tc.want {
+				t.Fatalf("DrivingSize = %d, want %d", plan.DrivingSize, tc.want)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_SnapToExact(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	plan, err := OptimizeWithOptions(context.Background(), 300, Options{SnapToExact: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	if plan.OriginalItemsOrdered != 300 || plan.ItemsOrdered != 500 || plan.TotalItems != 500 {
+		t.Fatalf("unexpected snapped plan: %+v", plan)
+	}
+}
+
+func TestOptimizeWithOptions_SnapToExactKeepsExactOrders(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	plan, err := OptimizeWithOptions(context.Background(), 750, Options{SnapToExact: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	if plan.OriginalItemsOrdered != 750 || plan.ItemsOrdered != 750 {
+		t.Fatalf("unexpected snapped plan: %+v", plan)
+	}
+}
+
+func TestOptimize_OverfillMetrics(t *testing.T) {
+	tests := []struct {
+		name         string
+		ordered      int
+		opts         Options
+		wantOverfill int
+		wantWaste    float64
+	}{
+		{name: "overfilled order", ordered: 251, wantOverfill: 249, wantWaste: 49.8},
+		{name: "exact order", ordered: 750, wantOverfill: 0, wantWaste: 0},
+		{name: "rounded percentage", ordered: 12001, wantOverfill: 249, wantWaste: 2.03},
+		{name: "snapped order", ordered: 251, opts: Options{SnapToExact: true}, wantOverfill: 0, wantWaste: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+			plan, err := OptimizeWithOptions(context.Background(), tc.ordered, tc.opts)
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+			if plan.Overfill != tc.wantOverfill || plan.WastePercent != tc.wantWaste {
+				t.Fatalf("overfill = %d, waste = %v, want %d and %v", plan.Overfill, plan.WastePercent, tc.wantOverfill, tc.wantWaste)
+			}
+		})
+	}
+}
+
+func TestOptimize_OverfillSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		ordered int
+		want    int
+	}{
+		{name: "single pack overshoots", ordered: 251, want: 500},
+		{name: "last step of a multi-pack path", ordered: 501, want: 500},
+		{name: "exact order has no source", ordered: 750, want: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := OptimizeWith(tc.ordered, []int{250, 500})
+			if err != nil {
+				t.Fatalf("OptimizeWith returned error: %v", err)
+			}
+			if plan.OverfillSource != tc.want {
+				t.Fatalf("OverfillSource = %d, want %d (plan %+v)", plan.OverfillSource, tc.want, plan)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_ShipmentOverfillMetrics(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	plan, err := OptimizeWithOptions(context.Background(), 1001, Options{MaxItemsPerShipment: 500})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	last := plan.Shipments[len(plan.Shipments)-1]
+	if last.ItemsOrdered != 1 || last.Overfill != 249 {
+		t.Fatalf("last shipment = %+v, want 1 ordered with overfill 249", last)
+	}
+	if plan.Overfill != plan.TotalItems-1001 {
+		t.Fatalf("Overfill = %d, want %d", plan.Overfill, plan.TotalItems-1001)
+	}
+}
+
+func TestOptimizeWithOptions_DisplayUnit(t *testing.T) {
+	tests := []struct {
+		name        string
+		unit        int
+		wantDisplay int
+	}{
+		{name: "rounds down", unit: 1000, wantDisplay: 12000},
+		{name: "rounds half up", unit: 500, wantDisplay: 12500},
+		{name: "already a multiple", unit: 250, wantDisplay: 12250},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+			plan, err := OptimizeWithOptions(context.Background(), 12001, Options{DisplayUnit: tc.unit})
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+			if plan.TotalItems != 12250 {
+				t.Fatalf("TotalItems = %d, want the true total 12250", plan.TotalItems)
+			}
+			if plan.DisplayTotalItems == nil || *plan.DisplayTotalItems != tc.wantDisplay {
+				t.Fatalf("DisplayTotalItems = %v, want %d", derefOrNil(plan.DisplayTotalItems), tc.wantDisplay)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_Savings(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	plan, err := OptimizeWithOptions(context.Background(), 12001, Options{Savings: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	// 12250 items take 49 packs of 250 against the plan's 4.
+	if plan.PacksSavedVsNaive == nil || *plan.PacksSavedVsNaive != 45 {
+		t.Fatalf("PacksSavedVsNaive = %v, want 45", derefOrNil(plan.PacksSavedVsNaive))
+	}
+
+	plan, err = OptimizeWithOptions(context.Background(), 1, Options{Savings: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if plan.PacksSavedVsNaive == nil || *plan.PacksSavedVsNaive != 0 {
+		t.Fatalf("PacksSavedVsNaive = %v, want 0", derefOrNil(plan.PacksSavedVsNaive))
+	}
+}
+
+func TestOptimizeWithOptions_Timestamp(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	previous := clock
+	fixed := time.Date(2026, 3, 14, 15, 9, 26, 535, time.FixedZone("BRT", -3*60*60))
+	clock = func() time.Time { return fixed }
+	t.Cleanup(func() { clock = previous })
+
+	plan, err := OptimizeWithOptions(context.Background(), 251, Options{Timestamp: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if plan.ComputedAt != "2026-03-14T18:09:26Z" {
+		t.Fatalf("ComputedAt = %q, want 2026-03-14T18:09:26Z", plan.ComputedAt)
+	}
+
+	plan, err = OptimizeWithOptions(context.Background(), 251, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if plan.ComputedAt != "" {
+		t.Fatalf("ComputedAt = %q, want it unset without Options.Timestamp", plan.ComputedAt)
+	}
+}
+
+func TestOptimizeWithOptions_SortByCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []int
+		ordered   int
+		packs     []PackBreakdown
+	}{
+		{
+			name:      "smaller size with more packs comes first",
+			packSizes: []int{6, 10},
+			ordered:   28,
+			packs:     []PackBreakdown{{Size: 6, Count: 3}, {Size: 10, Count: 1}},
+		},
+		{
+			name:      "ties are ordered by size descending",
+			packSizes: []int{250, 500, 1000, 2000, 5000},
+			ordered:   12001,
+			packs:     []PackBreakdown{{Size: 5000, Count: 2}, {Size: 2000, Count: 1}, {Size: 250, Count: 1}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, tc.packSizes)
+
+			plan, err := OptimizeWithOptions(context.Background(), tc.ordered, Options{SortByCount: true})
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+			if !reflect.DeepEqual(plan.Packs, tc.packs) {
+				t.Fatalf("Packs = %+v, want %+v", plan.Packs, tc.packs)
+			}
+		})
+	}
+}
+
+func TestOptimize_ExactPathsReportOptimal(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	plan, err := Optimize(251)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if !plan.Optimal {
+		t.Fatal("expected Optimize to report an optimal plan")
+	}
+
+	plan, err = OptimizeWith(13, []int{6, 10})
+	if err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+	if !plan.Optimal {
+		t.Fatal("expected OptimizeWith to report an optimal plan")
+	}
+}
+
+func TestOptimizeWithOptions_PreferExactWithin(t *testing.T) {
+	tests := []struct {
+		name         string
+		window       int
+		itemsOrdered int
+		original     int
+	}{
+		{name: "exact total inside the window", window: 200, itemsOrdered: 500, original: 300},
+		{name: "exact total outside the window", window: 100, itemsOrdered: 300, original: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500})
+
+			plan, err := OptimizeWithOptions(context.Background(), 300, Options{PreferExactWithin: tc.window})
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+
+			if plan.TotalItems != 500 {
+				t.Fatalf("TotalItems = %d, want 500", plan.TotalItems)
+			}
+			if plan.ItemsOrdered != tc.itemsOrdered || plan.OriginalItemsOrdered != tc.original {
+				t.Fatalf("ItemsOrdered = %d, OriginalItemsOrdered = %d, want %d and %d", plan.ItemsOrdered, plan.OriginalItemsOrdered, tc.itemsOrdered, tc.original)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_MaxTotal(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    int
+		maxTotal int
+		want     int
+		wantErr  bool
+	}{
+		{name: "total within cap", order: 251, maxTotal: 500, want: 500},
+		{name: "exact order at cap", order: 750, maxTotal: 750, want: 750},
+		{name: "tight cap makes order infeasible", order: 251, maxTotal: 499, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500})
+
+			plan, err := OptimizeWithOptions(context.Background(), tc.order, Options{MaxTotal: tc.maxTotal})
+			if tc.wantErr {
+				if !errors.Is(err, ErrMaxTotalUnreachable) {
+					t.Fatalf("error = %v, want ErrMaxTotalUnreachable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+			if plan.TotalItems != tc.want {
+				t.Fatalf("TotalItems = %d, want %d", plan.TotalItems, tc.want)
+			}
+		})
+	}
+}
+
+func TestOptimize_VerificationCatchesCorruptBacktrack(t *testing.T) {
+	resetResultCache(t)
+	resetCachedTable(t)
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	testTableHook = func(table *packingTable) {
+		// 12250 is reached by adding a 5000 to 7250; claim it was a 500.
+		table.prevPack[12250] = 500
+	}
+	t.Cleanup(func() { testTableHook = nil })
+
+	if _, err := Optimize(12001); !errors.Is(err, errReconstructPlan) {
+		t.Fatalf("error = %v, want errReconstructPlan", err)
+	}
+}
+
+func TestPackingTable_VerifyBreakdown(t *testing.T) {
+	table, err := newPackingTable(251, []int{500, 250})
+	if err != nil {
+		t.Fatalf("newPackingTable returned error: %v", err)
+	}
+	table.buildOptimalPackingTable()
+
+	if err := table.verifyBreakdown(500, []PackBreakdown{{Size: 500, Count: 1}}); err != nil {
+		t.Fatalf("verifyBreakdown rejected a valid breakdown: %v", err)
+	}
+	if err := table.verifyBreakdown(500, []PackBreakdown{{Size: 250, Count: 2}}); !errors.Is(err, errReconstructPlan) {
+		t.Fatalf("error = %v, want errReconstructPlan for a non-minimal pack count", err)
+	}
+	if err := table.verifyBreakdown(500, []PackBreakdown{{Size: 500, Count: 1}, {Size: 500, Count: 0}}); !errors.Is(err, errReconstructPlan) {
+		t.Fatalf("error = %v, want errReconstructPlan for a flattened breakdown", err)
+	}
+}
+
+func TestOptimize_PathologicalBreakdownStaysRunLengthEncoded(t *testing.T) {
+	plan, err := OptimizeWith(500000, []int{53})
+	if err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+
+	want := []PackBreakdown{{Size: 53, Count: 9434}}
+	if !reflect.DeepEqual(plan.Packs, want) {
+		t.Fatalf("Packs = %v, want %v", plan.Packs, want)
+	}
+	if got := TotalPhysicalPacks(plan.Packs); got != 9434 || got != plan.TotalPacks {
+		t.Fatalf("TotalPhysicalPacks = %d, want 9434 (TotalPacks %d)", got, plan.TotalPacks)
+	}
+
+	encoded, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("marshal plan: %v", err)
+	}
+	if len(encoded) > 256 {
+		t.Fatalf("encoded plan is %d bytes, want a compact breakdown: %s", len(encoded), encoded)
+	}
+}
+
+func TestOptimizeWithLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		ordered     int
+		maxOverfill int
+		wantErr     error
+		wantTotal   int
+	}{
+		{name: "within limit", ordered: 251, maxOverfill: 249, wantTotal: 500},
+		{name: "above limit", ordered: 251, maxOverfill: 248, wantErr: ErrOverfillExceeded},
+		{name: "zero accepts exact plans", ordered: 750, maxOverfill: 0, wantTotal: 750},
+		{name: "zero rejects overfill", ordered: 751, maxOverfill: 0, wantErr: ErrOverfillExceeded},
+		{name: "negative limit", ordered: 251, maxOverfill: -1, wantErr: ErrInvalidMaxOverfill},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := OptimizeWithLimit(tc.ordered, []int{250, 500}, tc.maxOverfill)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OptimizeWithLimit returned error: %v", err)
+			}
+			if plan.TotalItems != tc.wantTotal {
+				t.Fatalf("TotalItems = %d, want %d", plan.TotalItems, tc.wantTotal)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithMaxPacks(t *testing.T) {
+	tests := []struct {
+		name     string
+		ordered  int
+		maxPacks int
+		wantErr  error
+	}{
+		{name: "within cap", ordered: 12001, maxPacks: 4},
+		{name: "fewest packs above cap", ordered: 12001, maxPacks: 3, wantErr: ErrTooManyPacks},
+		{name: "non-positive cap", ordered: 12001, maxPacks: 0, wantErr: ErrInvalidMaxPacks},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := OptimizeWithMaxPacks(tc.ordered, []int{250, 500, 1000, 2000, 5000}, tc.maxPacks)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OptimizeWithMaxPacks returned error: %v", err)
+			}
+			if plan.TotalPacks != 4 || plan.TotalItems != 12250 {
+				t.Fatalf("unexpected plan: %+v", plan)
+			}
+		})
+	}
+}
+
+func TestOptimizeContext(t *testing.T) {
+	sizes := []int{3, 7}
+
+	plan, err := OptimizeContext(context.Background(), 11, sizes)
+	if err != nil {
+		t.Fatalf("OptimizeContext returned error: %v", err)
+	}
+	if plan.TotalItems != 12 {
+		t.Fatalf("total_items = %d, want 12", plan.TotalItems)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Large enough for the DP to reach a cancellation check.
+	if _, err := OptimizeContext(ctx, 4*ctxCheckInterval, sizes); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestOptimizeWithOptions_MaxTableEntries(t *testing.T) {
+	setOptimizerPackSizes(t, []int{3, 5})
+	const order = 2_500_000
+
+	if _, err := OptimizeWithOptions(t.Context(), order, Options{}); !errors.Is(err, ErrOptimizationTooLarge) {
+		t.Fatalf("expected ErrOptimizationTooLarge by default, got %v", err)
+	}
+
+	plan, err := OptimizeWithOptions(t.Context(), order, Options{MaxTableEntries: 2_600_000})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions with a raised limit returned error: %v", err)
+	}
+	if plan.TotalItems != order || plan.TotalPacks != order/5 {
+		t.Fatalf("unexpected plan: total %d in %d packs", plan.TotalItems, plan.TotalPacks)
+	}
+
+	tests := []struct {
+		name    string
+		order   int
+		limit   int
+		wantErr error
+	}{
+		{name: "raised limit still too small", order: order, limit: 2_000_001, wantErr: ErrOptimizationTooLarge},
+		{name: "order beyond the ceiling", order: maxTableEntriesCeiling, limit: maxTableEntriesCeiling, wantErr: ErrOptimizationTooLarge},
+		{name: "limit above the ceiling", order: order, limit: maxTableEntriesCeiling + 1, wantErr: ErrInvalidTableLimit},
+		{name: "negative limit", order: order, limit: -1, wantErr: ErrInvalidTableLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := OptimizeWithOptions(t.Context(), tt.order, Options{MaxTableEntries: tt.limit}); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_SkipBreakdown(t *testing.T) {
+	setOptimizerPackSizes(t, []int{23, 31, 53})
+	const order = 500_000
+
+	want, err := OptimizeWithOptions(t.Context(), order, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	plan, err := OptimizeWithOptions(t.Context(), order, Options{SkipBreakdown: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions with SkipBreakdown returned error: %v", err)
+	}
+	if plan.Packs != nil {
+		t.Fatalf("Packs = %v, want none", plan.Packs)
+	}
+	if plan.TotalItems != want.TotalItems || plan.TotalPacks != want.TotalPacks || plan.Overfill != want.Overfill || plan.DrivingSize != want.DrivingSize {
+		t.Fatalf("totals = %+v, want those of %+v", plan, want)
+	}
+
+	encoded, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("marshal plan: %v", err)
+	}
+	if bytes.Contains(encoded, []byte(`"packs"`)) {
+		t.Fatalf("expected no packs in %s", encoded)
+	}
+
+	// Options that rework the packs still get the right totals.
+	sizes, err := OptimizeWithOptions(t.Context(), 20, Options{SkipBreakdown: true, Objective: ObjectiveFewestSizes})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if sizes.Packs != nil || sizes.TotalItems != 23 || sizes.TotalPacks != 1 {
+		t.Fatalf("unexpected plan: %+v", sizes)
+	}
+}
+
+func BenchmarkComputePlan_Breakdown(b *testing.B) {
+	sizes := []int{53}
+	for _, tc := range []struct {
+		name          string
+		withBreakdown bool
+	}{
+		{name: "breakdown", withBreakdown: true},
+		{name: "totals only", withBreakdown: false},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, _, _, err := computePlan(b.Context(), 500_000, sizes, maxTableEntries, tc.withBreakdown); err != nil {
+					b.Fatalf("computePlan returned error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanStatus(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+	sizes := []int{250, 500}
+
+	tests := []struct {
+		name     string
+		optimize func() (Plan, error)
+		want     string
+	}{
+		{
+			name:     "exact DP",
+			optimize: func() (Plan, error) { return OptimizeWithOptions(t.Context(), 251, Options{}) },
+			want:     PlanStatusOptimal,
+		},
+		{
+			name:     "divisible fast path",
+			optimize: func() (Plan, error) { return OptimizeWithOptions(t.Context(), 1200, Options{SortByCount: true}) },
+			want:     PlanStatusOptimal,
+		},
+		{
+			name:     "fits under the shipment cap",
+			optimize: func() (Plan, error) { return OptimizeWithOptions(t.Context(), 501, Options{MaxItemsPerShipment: 1000}) },
+			want:     PlanStatusOptimal,
+		},
+		{
+			name: "split by the shipment cap",
+			optimize: func() (Plan, error) {
+				return OptimizeWithOptions(t.Context(), 1200, Options{MaxItemsPerShipment: 1000})
+			},
+			want: PlanStatusCapped,
+		},
+		{
+			name: "split by the weight cap",
+			optimize: func() (Plan, error) {
+				return OptimizeWithOptions(t.Context(), 1200, Options{ItemWeight: 2, MaxShipmentWeight: 2000})
+			},
+			want: PlanStatusCapped,
+		},
+		{
+			name:     "greedy heuristic",
+			optimize: func() (Plan, error) { return OptimizeGreedy(10_000_001, sizes) },
+			want:     PlanStatusApproximate,
+		},
+		{
+			name: "batch",
+			optimize: func() (Plan, error) {
+				plans, err := OptimizeBatch([]int{251}, sizes)
+				if err != nil {
+					return Plan{}, err
+				}
+				return plans[0], nil
+			},
+			want: PlanStatusOptimal,
+		},
+		{
+			name:     "beyond the int32 ceiling",
+			optimize: func() (Plan, error) { return OptimizeLarge(3_000_000_001, []int{500_000, 1_000_000}) },
+			want:     PlanStatusOptimal,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := tc.optimize()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if plan.Status != tc.want {
+				t.Fatalf("Status = %q, want %q", plan.Status, tc.want)
+			}
+			for i, shipment := range plan.Shipments {
+				if shipment.Status != "" {
+					t.Fatalf("shipment %d has status %q, want none", i, shipment.Status)
+				}
+			}
+		})
+	}
+}
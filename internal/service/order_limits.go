@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"math"
+)
+
+// OrderLimits are the largest orders the optimizer serves from a catalog,
+// derived from the size of the table each path builds.
+type OrderLimits struct {
+	// Exact is the largest order OptimizeWithOptions and OptimizeBatch
+	// serve: their table spans the order plus the largest pack.
+	Exact int
+	// Large is the largest order above the int32 ceiling OptimizeLarge
+	// serves, or zero when the catalog's sizes share too small a divisor to
+	// reach beyond the ceiling.
+	Large int
+}
+
+// OrderLimitsFor returns the OrderLimits of packSizes. tableLimit is
+// Options.MaxTableEntries: zero keeps maxTableEntries. Callers check orders
+// against them up front; the optimizers still enforce their own guards.
+func OrderLimitsFor(packSizes []int, tableLimit int) (OrderLimits, error) {
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return OrderLimits{}, err
+	}
+	if tableLimit == 0 {
+		tableLimit = maxTableEntries
+	}
+	if tableLimit < 0 || tableLimit > maxTableEntriesCeiling {
+		return OrderLimits{}, fmt.Errorf("%w: %d must be between 1 and %d", ErrInvalidTableLimit, tableLimit, maxTableEntriesCeiling)
+	}
+
+	largest := normalized[0]
+	limits := OrderLimits{Exact: max(min(tableLimit-largest, maxInt32Value), 0)}
+
+	// OptimizeLarge solves ceil(order/g) units against the sizes divided by
+	// g, with the default table limit.
+	g := largest
+	for _, size := range normalized[1:] {
+		g = gcd(g, size)
+	}
+	units := int64(min(maxTableEntries-largest/g, maxInt32Value))
+	if large := units * int64(g); large > maxInt32Value {
+		limits.Large = int(min(large, int64(math.MaxInt)-int64(largest)))
+	}
+	return limits, nil
+}
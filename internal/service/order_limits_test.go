@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestOrderLimitsFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		sizes      []int
+		tableLimit int
+		want       OrderLimits
+	}{
+		{name: "default catalog", sizes: []int{250, 500, 1000, 2000, 5000}, want: OrderLimits{Exact: 1_995_000}},
+		{name: "coprime sizes", sizes: []int{23, 31, 53}, want: OrderLimits{Exact: 1_999_947}},
+		{name: "raised table limit", sizes: []int{250, 500, 1000, 2000, 5000}, tableLimit: 5_000_000, want: OrderLimits{Exact: 4_995_000}},
+		{name: "pallet sizes", sizes: []int{250_000, 500_000, 1_000_000}, want: OrderLimits{Exact: 1_000_000, Large: 499_999_000_000}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.want.Large > 0 && strconv.IntSize < 64 {
+				t.Skip("orders beyond int32 need a 64-bit int")
+			}
+			got, err := OrderLimitsFor(tc.sizes, tc.tableLimit)
+			if err != nil {
+				t.Fatalf("OrderLimitsFor returned error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("OrderLimitsFor = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOrderLimitsFor_MatchOptimizers(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("orders beyond int32 need a 64-bit int")
+	}
+	sizes := []int{250_000, 500_000, 1_000_000}
+	limits, err := OrderLimitsFor(sizes, 0)
+	if err != nil {
+		t.Fatalf("OrderLimitsFor returned error: %v", err)
+	}
+
+	if _, err := OptimizeWith(limits.Exact, sizes); err != nil {
+		t.Fatalf("OptimizeWith(%d) returned error: %v", limits.Exact, err)
+	}
+	if _, err := OptimizeWith(limits.Exact+1, sizes); !errors.Is(err, ErrOptimizationTooLarge) {
+		t.Fatalf("OptimizeWith(%d) error = %v, want ErrOptimizationTooLarge", limits.Exact+1, err)
+	}
+	if _, err := OptimizeLarge(int64(limits.Large), sizes); err != nil {
+		t.Fatalf("OptimizeLarge(%d) returned error: %v", limits.Large, err)
+	}
+	if _, err := OptimizeLarge(int64(limits.Large)+1, sizes); !errors.Is(err, ErrOptimizationTooLarge) {
+		t.Fatalf("OptimizeLarge(%d) error = %v, want ErrOptimizationTooLarge", limits.Large+1, err)
+	}
+}
+
+func TestOrderLimitsFor_InvalidInput(t *testing.T) {
+	if _, err := OrderLimitsFor([]int{250, 500}, maxTableEntriesCeiling+1); !errors.Is(err, ErrInvalidTableLimit) {
+		t.Fatalf("error = %v, want ErrInvalidTableLimit", err)
+	}
+	if _, err := OrderLimitsFor(nil, 0); !errors.Is(err, ErrInvalidPackSizes) {
+		t.Fatalf("error = %v, want ErrInvalidPackSizes", err)
+	}
+}
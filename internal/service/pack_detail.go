@@ -0,0 +1,93 @@
+package service
+
+import "math"
+
+// Bounds of the scan behind DescribePackSizes: orders up to
+// primaryScanSpan times the largest pack, at most maxPrimaryScanOrders of
+// them, and at most about maxPrimaryScanWork breakdown steps in total.
+const (
+	primaryScanSpan      = 10
+	maxPrimaryScanOrders = 100_000
+	maxPrimaryScanWork   = 10_000_000
+)
+
+// PackSizeDetail annotates a pack size with the orders it is the primary
+// choice for: the size shipping the most items in the optimal plan, ties
+// going to the larger size. The fields are nil when the size is never
+// primary within the scanned orders.
+type PackSizeDetail struct {
+	Size            int  `json:"size"`
+	PrimaryMinOrder *int `json:"primary_min_order"`
+	PrimaryMaxOrder *int `json:"primary_max_order"`
+	// PrimaryOrders counts the scanned orders the size is primary for; the
+	// range between min and max may include orders where it is not.
+	PrimaryOrders int `json:"primary_orders"`
+}
+
+// CatalogDetail is the result of DescribePackSizes.
+type CatalogDetail struct {
+	// ScanMax is the largest order scanned; a range ending there may extend
+	// beyond it.
+	ScanMax int              `json:"scan_max"`
+	Sizes   []PackSizeDetail `json:"sizes"`
+}
+
+// DescribePackSizes scans orders from 1 to a bounded maximum, optimizing each
+// from one shared DP table, and reports for every pack size the order range
+// where it is the primary choice of the plan, for catalog explorers.
+//
+// The scan covers primaryScanSpan times the largest pack, so every size gets
+// a chance to dominate, capped at maxPrimaryScanOrders orders. Reconstructing
+// the plan for order o walks about o/smallest packs, so the cap is lowered
+// further for catalogs with small packs to keep the total under
+// maxPrimaryScanWork steps.
+func DescribePackSizes(packSizes []int) (CatalogDetail, error) {
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return CatalogDetail{}, err
+	}
+	largest, smallest := normalized[0], normalized[len(normalized)-1]
+
+	// Walking every plan up to n costs about n^2/(2*smallest) steps.
+	workCap := int(math.Sqrt(2 * maxPrimaryScanWork * float64(smallest)))
+	scanMax := min(primaryScanSpan*largest, maxPrimaryScanOrders, workCap)
+
+	table, err := newPackingTable(scanMax, normalized)
+	if err != nil {
+		return CatalogDetail{}, err
+	}
+	table.buildOptimalPackingTable()
+
+	details := make([]PackSizeDetail, len(normalized))
+	index := make(map[int]int, len(normalized))
+	for i, size := range normalized {
+		details[i].Size = size
+		index[size] = i
+	}
+
+	for order := 1; order <= scanMax; order++ {
+		table = table.forOrder(order)
+		packs, err := table.buildBreakdown(table.chooseFulfillmentTotal())
+		if err != nil {
+			return CatalogDetail{}, err
+		}
+
+		// Packs are sorted by size descending, so a strict comparison
+		// breaks ties toward the larger size.
+		primary := packs[0]
+		for _, pack := range packs[1:] {
+			if pack.Size*pack.Count > primary.Size*primary.Count {
+				primary = pack
+			}
+		}
+
+		detail := &details[index[primary.Size]]
+		if detail.PrimaryMinOrder == nil {
+			detail.PrimaryMinOrder = &order
+		}
+		detail.PrimaryMaxOrder = &order
+		detail.PrimaryOrders++
+	}
+
+	return CatalogDetail{ScanMax: scanMax, Sizes: details}, nil
+}
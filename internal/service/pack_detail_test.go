@@ -0,0 +1,57 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescribePackSizes(t *testing.T) {
+	got, err := DescribePackSizes([]int{500, 250})
+	if err != nil {
+		t.Fatalf("DescribePackSizes returned error: %v", err)
+	}
+
+	// Up to 250 items one 250 pack ships them; from 251 on the optimal plan
+	// never holds more than one 250, so the 500s carry most of it.
+	want := CatalogDetail{
+		ScanMax: 5000,
+		Sizes: []PackSizeDetail{
+			{Size: 500, PrimaryMinOrder: intPtr(251), PrimaryMaxOrder: intPtr(5000), PrimaryOrders: 4750},
+			{Size: 250, PrimaryMinOrder: intPtr(1), PrimaryMaxOrder: intPtr(250), PrimaryOrders: 250},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DescribePackSizes = %+v, want %+v", got, want)
+	}
+}
+
+func TestDescribePackSizes_ScanBounds(t *testing.T) {
+	tests := []struct {
+		name        string
+		sizes       []int
+		wantScanMax int
+	}{
+		{name: "span of the largest pack", sizes: []int{23, 31, 53}, wantScanMax: 530},
+		{name: "order cap", sizes: []int{5000, 20_000}, wantScanMax: maxPrimaryScanOrders},
+		{name: "work cap for small packs", sizes: []int{1, 100_000}, wantScanMax: 4472},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DescribePackSizes(tt.sizes)
+			if err != nil {
+				t.Fatalf("DescribePackSizes returned error: %v", err)
+			}
+			if got.ScanMax != tt.wantScanMax {
+				t.Fatalf("ScanMax = %d, want %d", got.ScanMax, tt.wantScanMax)
+			}
+			orders := 0
+			for _, detail := range got.Sizes {
+				orders += detail.PrimaryOrders
+			}
+			if orders != got.ScanMax {
+				t.Fatalf("primary orders sum to %d, want every scanned order (%d)", orders, got.ScanMax)
+			}
+		})
+	}
+}
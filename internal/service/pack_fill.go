@@ -0,0 +1,71 @@
+package service
+
+import (
+	"math"
+	"slices"
+)
+
+// PackFill is a run of Count packs of Size whose items count toward the
+// order in the same proportion.
+type PackFill struct {
+	Size  int `json:"size"`
+	Count int `json:"count"`
+	// FillVsOrder is the percentage of each pack's items that count toward
+	// the order rather than overfill, rounded to two decimals.
+	FillVsOrder float64 `json:"fill_vs_order"`
+}
+
+// packFill splits the plan's packs by how much of them counts toward the
+// order. Packs are always full, so the overfill is attributed to whole packs:
+// first to the pack that crossed the order (Plan.OverfillSource), then to the
+// largest packs. In a single-shipment plan the overfill is smaller than the
+// crossing pack, so one pack at most is partly overfill; split shipments can
+// overfill by more. Runs keep the plan's pack order, with each run's full,
+// partial and wasted packs listed in that order.
+func packFill(plan Plan) []PackFill {
+	waste := make([]int, len(plan.Packs))
+	remaining := plan.Overfill
+
+	order := make([]int, len(plan.Packs))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		sa, sb := plan.Packs[a].Size, plan.Packs[b].Size
+		switch {
+		case sa == plan.OverfillSource && sb != plan.OverfillSource:
+			return -1
+		case sb == plan.OverfillSource && sa != plan.OverfillSource:
+			return 1
+		}
+		return sb - sa
+	})
+	for _, i := range order {
+		if remaining == 0 {
+			break
+		}
+		pack := plan.Packs[i]
+		waste[i] = min(remaining, pack.Size*pack.Count)
+		remaining -= waste[i]
+	}
+
+	fills := make([]PackFill, 0, len(plan.Packs)+1)
+	for i, pack := range plan.Packs {
+		wasted, partial := waste[i]/pack.Size, waste[i]%pack.Size
+		full := pack.Count - wasted
+		if partial > 0 {
+			full--
+		}
+		if full > 0 {
+			fills = append(fills, PackFill{Size: pack.Size, Count: full, FillVsOrder: 100})
+		}
+		if partial > 0 {
+			percent := math.Round(float64(pack.Size-partial)*10000/float64(pack.Size)) / 100
+			fills = append(fills, PackFill{Size: pack.Size, Count: 1, FillVsOrder: percent})
+		}
+		if wasted > 0 {
+			fills = append(fills, PackFill{Size: pack.Size, Count: wasted, FillVsOrder: 0})
+		}
+	}
+	return fills
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPackFill(t *testing.T) {
+	tests := []struct {
+		name string
+		plan Plan
+		want []PackFill
+	}{
+		{
+			name: "exact plan is fully used",
+			plan: Plan{Packs: []PackBreakdown{{Size: 500, Count: 2}}},
+			want: []PackFill{{Size: 500, Count: 2, FillVsOrder: 100}},
+		},
+		{
+			name: "overfill on the crossing pack",
+			plan: Plan{Packs: []PackBreakdown{{Size: 500, Count: 2}, {Size: 250, Count: 1}}, Overfill: 100, OverfillSource: 250},
+			want: []PackFill{{Size: 500, Count: 2, FillVsOrder: 100}, {Size: 250, Count: 1, FillVsOrder: 60}},
+		},
+		{
+			name: "crossing pack inside a run",
+			plan: Plan{Packs: []PackBreakdown{{Size: 500, Count: 2}, {Size: 250, Count: 1}}, Overfill: 100, OverfillSource: 500},
+			want: []PackFill{{Size: 500, Count: 1, FillVsOrder: 100}, {Size: 500, Count: 1, FillVsOrder: 80}, {Size: 250, Count: 1, FillVsOrder: 100}},
+		},
+		{
+			name: "split overfill spans packs",
+			plan: Plan{Packs: []PackBreakdown{{Size: 500, Count: 3}, {Size: 250, Count: 1}}, Overfill: 900, OverfillSource: 250},
+			want: []PackFill{{Size: 500, Count: 1, FillVsOrder: 100}, {Size: 500, Count: 1, FillVsOrder: 70}, {Size: 500, Count: 1, FillVsOrder: 0}, {Size: 250, Count: 1, FillVsOrder: 0}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := packFill(tc.plan); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("packFill = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_FillVsOrder(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	plan, err := OptimizeWithOptions(t.Context(), 251, Options{FillVsOrder: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	want := []PackFill{{Size: 500, Count: 1, FillVsOrder: 50.2}}
+	if !reflect.DeepEqual(plan.PackFill, want) {
+		t.Fatalf("PackFill = %+v, want %+v", plan.PackFill, want)
+	}
+}
@@ -1,20 +1,42 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 )
 
 var defaultPackSizes = []int{250, 500, 1000, 2000, 5000}
 
+var ErrPackSizeNotFound = errors.New("pack size is not configured")
+
 // NormalizePackSizes validates pack sizes, removes duplicates, and returns
-// a descending-sorted slice so larger packs are evaluated first.
+// a descending-sorted slice so larger packs are evaluated first. At most
+// Config.MaxPackSizes distinct sizes are accepted. Results for recently seen
+// inputs come from a bounded cache (see Config.NormalizeCacheSize); the
+// returned slice is always the caller's own.
 func NormalizePackSizes(packSizes []int) ([]int, error) {
 	if len(packSizes) == 0 {
 		return nil, ErrInvalidPackSizes
 	}
 
+	cfg := currentConfig()
+	if normalized, ok := cachedNormalization(packSizes, cfg); ok {
+		return normalized, nil
+	}
+	normalized, err := normalizePackSizes(packSizes, cfg)
+	if err != nil {
+		return nil, err
+	}
+	storeNormalization(packSizes, normalized, cfg)
+	return normalized, nil
+}
+
+func normalizePackSizes(packSizes []int, cfg Config) ([]int, error) {
+	maxPackSize := cfg.MaxPackSize
+
 	// seen removes duplicates to improve optimization performance.
 	seen := make(map[int]struct{}, len(packSizes))
 	normalized := make([]int, 0, len(packSizes))
@@ -25,7 +47,13 @@ func NormalizePackSizes(packSizes []int) ([]int, error) {
 		if size > maxInt32Value {
 			return nil, fmt.Errorf("%w: %d exceeds int32 max value %d", ErrInvalidPackSizes, size, maxInt32Value)
 		}
+		if size > maxPackSize {
+			return nil, fmt.Errorf("%w: %d exceeds max pack size %d", ErrPackSizeTooLarge, size, maxPackSize)
+		}
 		if _, duplicate := seen[size]; duplicate {
+			if cfg.StrictDuplicates {
+				return nil, fmt.Errorf("%w: duplicate %d", ErrInvalidPackSizes, size)
+			}
 			continue
 		}
 
@@ -36,6 +64,9 @@ func NormalizePackSizes(packSizes []int) ([]int, error) {
 	if len(normalized) == 0 {
 		return nil, ErrInvalidPackSizes
 	}
+	if len(normalized) > cfg.MaxPackSizes {
+		return nil, fmt.Errorf("%w: %d distinct sizes exceed the maximum of %d", ErrTooManyPackSizes, len(normalized), cfg.MaxPackSizes)
+	}
 
 	sort.Sort(sort.Reverse(sort.IntSlice(normalized)))
 	return normalized, nil
@@ -45,24 +76,50 @@ func NormalizePackSizes(packSizes []int) ([]int, error) {
 type PackSizeService interface {
 	GetPackSizes() []int
 	SetPackSizes(packSizes []int) error
+	// RemovePackSize removes one configured size; it fails with
+	// ErrPackSizeNotFound when size is not configured and with
+	// ErrInvalidPackSizes when it is the last one.
+	RemovePackSize(size int) error
+	// AddPackSizes merges sizes into the configured ones; sizes already
+	// configured are ignored.
+	AddPackSizes(sizes []int) error
+	// GetCatalog returns the pack sizes together with the catalog version,
+	// which increases with every successful update.
+	GetCatalog() (packSizes []int, version uint64)
 }
 
 // InMemoryPackSizeService stores pack sizes in memory and is safe for concurrent use.
+// packSizes are those of the active profile; profiles holds the others.
 type InMemoryPackSizeService struct {
 	mu        sync.RWMutex
 	packSizes []int
+	version   uint64
+	active    string
+	profiles  map[string][]int
+	listeners []func(packSizes []int)
 }
 
 var (
 	packSizeServiceOnce     sync.Once
-	packSizeServiceInstance PackSizeService
+	packSizeServiceInstance *InMemoryPackSizeService
 	packSizeServiceInitErr  error
 )
 
 // GetPackSizeService returns the singleton pack size service.
 func GetPackSizeService() (PackSizeService, error) {
 	packSizeServiceOnce.Do(func() {
-		packSizeServiceInstance, packSizeServiceInitErr = NewInMemoryPackSizeService(defaultPackSizes)
+		var inMemory *InMemoryPackSizeService
+		inMemory, packSizeServiceInitErr = NewInMemoryPackSizeService(defaultPackSizes)
+		if packSizeServiceInitErr != nil {
+			return
+		}
+
+		inMemory.OnChange(startTableWarmup)
+		inMemory.OnChange(startPrimeOrders)
+		inMemory.OnChange(resetPackUsage)
+		inMemory.OnChange(prunePackSKUs)
+		inMemory.OnChange(broadcastCatalog)
+		packSizeServiceInstance = inMemory
 	})
 
 	if packSizeServiceInitErr != nil {
@@ -81,6 +138,8 @@ func NewInMemoryPackSizeService(initialPackSizes []int) (*InMemoryPackSizeServic
 
 	return &InMemoryPackSizeService{
 		packSizes: normalized,
+		version:   1,
+		active:    DefaultProfile,
 	}, nil
 }
 
@@ -94,6 +153,15 @@ func (s *InMemoryPackSizeService) GetPackSizes() []int {
 	return result
 }
 
+// GetCatalog returns a copy of the configured pack sizes and their version,
+// read together so they always match.
+func (s *InMemoryPackSizeService) GetCatalog() ([]int, uint64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.packSizes), s.version
+}
+
 // SetPackSizes validates and replaces currently configured pack sizes.
 func (s *InMemoryPackSizeService) SetPackSizes(packSizes []int) error {
 	normalized, err := NormalizePackSizes(packSizes)
@@ -105,5 +173,77 @@ func (s *InMemoryPackSizeService) SetPackSizes(packSizes []int) error {
 	defer s.mu.Unlock()
 
 	s.packSizes = normalized
+	s.version++
+	s.notifyLocked()
 	return nil
 }
+
+// AddPackSizes merges sizes into the configured pack sizes, as one update.
+// Sizes already configured, or repeated in sizes, are ignored even with
+// StrictDuplicates; when nothing is new the catalog and its version stay
+// unchanged.
+func (s *InMemoryPackSizeService) AddPackSizes(sizes []int) error {
+	if len(sizes) == 0 {
+		return ErrInvalidPackSizes
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged := slices.Clone(s.packSizes)
+	for _, size := range sizes {
+		if !slices.Contains(merged, size) {
+			merged = append(merged, size)
+		}
+	}
+	normalized, err := NormalizePackSizes(merged)
+	if err != nil {
+		return err
+	}
+	if len(normalized) == len(s.packSizes) {
+		return nil
+	}
+
+	s.packSizes = normalized
+	s.version++
+	s.notifyLocked()
+	return nil
+}
+
+// RemovePackSize removes size from the configured pack sizes, as one update.
+func (s *InMemoryPackSizeService) RemovePackSize(size int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := slices.Index(s.packSizes, size)
+	if index < 0 {
+		return fmt.Errorf("%w: %d", ErrPackSizeNotFound, size)
+	}
+	if len(s.packSizes) == 1 {
+		return fmt.Errorf("%w: %d is the last pack size", ErrInvalidPackSizes, size)
+	}
+
+	s.packSizes = slices.Delete(s.packSizes, index, index+1)
+	s.version++
+	s.notifyLocked()
+	return nil
+}
+
+// OnChange registers fn to be called with the new pack sizes after every
+// successful update. Listeners run while the update holds the write lock, so
+// notifications arrive in update order; they must return quickly and must not
+// call back into the service.
+func (s *InMemoryPackSizeService) OnChange(fn func(packSizes []int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners = append(s.listeners, fn)
+}
+
+func (s *InMemoryPackSizeService) notifyLocked() {
+	for _, listener := range s.listeners {
+		packSizes := make([]int, len(s.packSizes))
+		copy(packSizes, s.packSizes)
+		listener(packSizes)
+	}
+}
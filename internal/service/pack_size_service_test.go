@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -66,6 +67,41 @@ func TestNormalizePackSizes(t *testing.T) {
 	}
 }
 
+func TestNormalizePackSizes_Duplicates(t *testing.T) {
+	tests := []struct {
+		name   string
+		strict bool
+		want   []int
+	}{
+		{name: "lenient drops duplicates", strict: false, want: []int{500, 250}},
+		{name: "strict rejects duplicates", strict: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setTestConfig(t, func(cfg *Config) { cfg.StrictDuplicates = tc.strict })
+
+			got, err := NormalizePackSizes([]int{250, 250, 500})
+			if tc.strict {
+				if !errors.Is(err, ErrInvalidPackSizes) || !strings.Contains(err.Error(), "duplicate 250") {
+					t.Fatalf("expected ErrInvalidPackSizes naming 250, got %v", err)
+				}
+				if ValidatePackSizes([]int{250, 250, 500}).Valid() {
+					t.Fatal("Valid() = true, want false in strict mode")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NormalizePackSizes returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("NormalizePackSizes() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestNewInMemoryPackSizeService(t *testing.T) {
 	service, err := NewInMemoryPackSizeService([]int{250, 500, 1000})
 	if err != nil {
@@ -174,3 +210,97 @@ func TestGetPackSizeService_SingletonConcurrent(t *testing.T) {
 		}
 	}
 }
+
+func TestInMemoryPackSizeService_GetCatalogVersion(t *testing.T) {
+	svc, err := NewInMemoryPackSizeService([]int{250, 500})
+	if err != nil {
+		t.Fatalf("NewInMemoryPackSizeService returned error: %v", err)
+	}
+
+	_, initial := svc.GetCatalog()
+	if err := svc.SetPackSizes([]int{0}); err == nil {
+		t.Fatal("expected error for invalid pack sizes")
+	}
+	if _, version := svc.GetCatalog(); version != initial {
+		t.Fatalf("version after failed update = %d, want %d", version, initial)
+	}
+
+	if err := svc.SetPackSizes([]int{6, 10}); err != nil {
+		t.Fatalf("SetPackSizes returned error: %v", err)
+	}
+	sizes, version := svc.GetCatalog()
+	if version != initial+1 {
+		t.Fatalf("version = %d, want %d", version, initial+1)
+	}
+	if !reflect.DeepEqual(sizes, []int{10, 6}) {
+		t.Fatalf("pack sizes = %v, want [10 6]", sizes)
+	}
+}
+
+func TestInMemoryPackSizeService_RemovePackSize(t *testing.T) {
+	svc, err := NewInMemoryPackSizeService([]int{250, 500, 1000})
+	if err != nil {
+		t.Fatalf("NewInMemoryPackSizeService returned error: %v", err)
+	}
+	var notified []int
+	svc.OnChange(func(packSizes []int) { notified = packSizes })
+	_, initial := svc.GetCatalog()
+
+	if err := svc.RemovePackSize(500); err != nil {
+		t.Fatalf("RemovePackSize returned error: %v", err)
+	}
+	sizes, version := svc.GetCatalog()
+	if !reflect.DeepEqual(sizes, []int{1000, 250}) || version != initial+1 {
+		t.Fatalf("catalog = %v version %d, want [1000 250] version %d", sizes, version, initial+1)
+	}
+	if !reflect.DeepEqual(notified, []int{1000, 250}) {
+		t.Fatalf("listener got %v, want [1000 250]", notified)
+	}
+
+	if err := svc.RemovePackSize(500); !errors.Is(err, ErrPackSizeNotFound) {
+		t.Fatalf("expected ErrPackSizeNotFound, got %v", err)
+	}
+	if err := svc.RemovePackSize(1000); err != nil {
+		t.Fatalf("RemovePackSize returned error: %v", err)
+	}
+	if err := svc.RemovePackSize(250); !errors.Is(err, ErrInvalidPackSizes) {
+		t.Fatalf("expected ErrInvalidPackSizes removing the last size, got %v", err)
+	}
+	if sizes := svc.GetPackSizes(); !reflect.DeepEqual(sizes, []int{250}) {
+		t.Fatalf("pack sizes = %v, want [250]", sizes)
+	}
+}
+
+func TestInMemoryPackSizeService_AddPackSizes(t *testing.T) {
+	setTestConfig(t, func(cfg *Config) { cfg.StrictDuplicates = true })
+
+	svc, err := NewInMemoryPackSizeService([]int{250, 500})
+	if err != nil {
+		t.Fatalf("NewInMemoryPackSizeService returned error: %v", err)
+	}
+	_, initial := svc.GetCatalog()
+
+	if err := svc.AddPackSizes([]int{1500, 1500, 250}); err != nil {
+		t.Fatalf("AddPackSizes returned error: %v", err)
+	}
+	sizes, version := svc.GetCatalog()
+	if !reflect.DeepEqual(sizes, []int{1500, 500, 250}) || version != initial+1 {
+		t.Fatalf("catalog = %v version %d, want [1500 500 250] version %d", sizes, version, initial+1)
+	}
+
+	if err := svc.AddPackSizes([]int{500}); err != nil {
+		t.Fatalf("AddPackSizes returned error for a duplicate: %v", err)
+	}
+	if _, after := svc.GetCatalog(); after != version {
+		t.Fatalf("version after adding a duplicate = %d, want %d", after, version)
+	}
+
+	for _, invalid := range [][]int{nil, {0}} {
+		if err := svc.AddPackSizes(invalid); !errors.Is(err, ErrInvalidPackSizes) {
+			t.Fatalf("AddPackSizes(%v): expected ErrInvalidPackSizes, got %v", invalid, err)
+		}
+	}
+	if sizes := svc.GetPackSizes(); !reflect.DeepEqual(sizes, []int{1500, 500, 250}) {
+		t.Fatalf("pack sizes after rejected adds = %v", sizes)
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxSKULength bounds a pack SKU, which picking systems print as a barcode.
+const maxSKULength = 64
+
+var ErrInvalidPackSKUs = errors.New("pack SKUs must label configured pack sizes")
+
+// packSKUs labels configured pack sizes with the SKU or barcode picking
+// systems scan. Sizes without a label have none; labels of sizes dropped from
+// the catalog are dropped with them (see prunePackSKUs). version counts the
+// changes, so stored labeled plans can tell when their labels are stale.
+var packSKUs = struct {
+	mu      sync.RWMutex
+	bySize  map[int]string
+	version uint64
+}{
+	bySize: make(map[int]string),
+}
+
+// SetPackSKUs replaces every pack SKU with skus, keyed by pack size. Each
+// size must be configured, and each SKU non-empty, without surrounding
+// spaces and at most maxSKULength characters. An empty map removes every
+// label.
+func SetPackSKUs(skus map[int]string) error {
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		return err
+	}
+
+	// Catalog listeners prune under packSKUs.mu while the catalog is locked,
+	// so the catalog is never read with packSKUs.mu held.
+	configured, version := packSizeService.GetCatalog()
+	for size, sku := range skus {
+		if !slices.Contains(configured, size) {
+			return fmt.Errorf("%w: %d is not a configured pack size", ErrInvalidPackSKUs, size)
+		}
+		if sku == "" || sku != strings.TrimSpace(sku) || len(sku) > maxSKULength {
+			return fmt.Errorf("%w: SKU %q of %d must be 1 to %d characters without surrounding spaces", ErrInvalidPackSKUs, sku, size, maxSKULength)
+		}
+	}
+
+	bySize := make(map[int]string, len(skus))
+	for size, sku := range skus {
+		bySize[size] = sku
+	}
+	packSKUs.mu.Lock()
+	packSKUs.bySize = bySize
+	packSKUs.version++
+	packSKUs.mu.Unlock()
+
+	// An update that landed since the read was pruned before the store.
+	if current, currentVersion := packSizeService.GetCatalog(); currentVersion != version {
+		prunePackSKUs(current)
+	}
+	return nil
+}
+
+// PackSKUs returns a copy of the pack SKUs, keyed by pack size.
+func PackSKUs() map[int]string {
+	packSKUs.mu.RLock()
+	defer packSKUs.mu.RUnlock()
+
+	skus := make(map[int]string, len(packSKUs.bySize))
+	for size, sku := range packSKUs.bySize {
+		skus[size] = sku
+	}
+	return skus
+}
+
+// prunePackSKUs drops the labels of sizes no longer in the catalog, so a size
+// removed and added back starts unlabeled.
+func prunePackSKUs(packSizes []int) {
+	packSKUs.mu.Lock()
+	defer packSKUs.mu.Unlock()
+
+	for size := range packSKUs.bySize {
+		if !slices.Contains(packSizes, size) {
+			delete(packSKUs.bySize, size)
+			packSKUs.version++
+		}
+	}
+}
+
+func packSKUsVersion() uint64 {
+	packSKUs.mu.RLock()
+	defer packSKUs.mu.RUnlock()
+
+	return packSKUs.version
+}
+
+// LabelPacks sets the SKU of every pack of plan and its shipments that has
+// one. OptimizeWithOptions and OptimizeIdempotent label their plans; plans of
+// the configured catalog computed from its pack sizes, such as batch plans,
+// are labeled with it.
+func LabelPacks(plan *Plan) {
+	packSKUs.mu.RLock()
+	defer packSKUs.mu.RUnlock()
+
+	if len(packSKUs.bySize) == 0 {
+		return
+	}
+	label := func(packs []PackBreakdown) {
+		for i := range packs {
+			packs[i].SKU = packSKUs.bySize[packs[i].Size]
+		}
+	}
+	label(plan.Packs)
+	for i := range plan.Shipments {
+		label(plan.Shipments[i].Packs)
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func setTestPackSKUs(t *testing.T, skus map[int]string) {
+	t.Helper()
+
+	if err := SetPackSKUs(skus); err != nil {
+		t.Fatalf("SetPackSKUs returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = SetPackSKUs(nil) })
+}
+
+func TestOptimizeWithOptions_PackSKUs(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000})
+	setTestPackSKUs(t, map[int]string{1000: "BOX-L", 250: "BOX-S"})
+
+	plan, err := OptimizeWithOptions(t.Context(), 1251, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	want := []PackBreakdown{{Size: 1000, Count: 1, SKU: "BOX-L"}, {Size: 500, Count: 1}}
+	if !reflect.DeepEqual(plan.Packs, want) {
+		t.Fatalf("Packs = %+v, want %+v", plan.Packs, want)
+	}
+
+	split, err := OptimizeWithOptions(t.Context(), 1251, Options{MaxItemsPerShipment: 1000})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	for _, shipment := range split.Shipments {
+		for _, pack := range shipment.Packs {
+			if pack.SKU != map[int]string{1000: "BOX-L", 250: "BOX-S"}[pack.Size] {
+				t.Fatalf("shipment pack %+v has the wrong SKU", pack)
+			}
+		}
+	}
+
+	// The result cache and OptimizeWith never carry labels.
+	unlabeled, err := OptimizeWith(1251, []int{250, 500, 1000})
+	if err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+	if unlabeled.Packs[0].SKU != "" {
+		t.Fatalf("OptimizeWith packs = %+v, want no SKUs", unlabeled.Packs)
+	}
+}
+
+func TestOptimizeIdempotent_PackSKUs(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000})
+	setTestPackSKUs(t, map[int]string{1000: "BOX-L", 250: "BOX-S"})
+
+	plain, err := OptimizeWithOptions(t.Context(), 1251, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	idempotent, err := OptimizeIdempotent(t.Context(), 1251, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+	if !reflect.DeepEqual(idempotent.Packs, plain.Packs) {
+		t.Fatalf("idempotent packs = %+v, want %+v", idempotent.Packs, plain.Packs)
+	}
+
+	// A stored result never keeps labels an update replaced.
+	setTestPackSKUs(t, map[int]string{1000: "CRATE-L"})
+	relabeled, err := OptimizeIdempotent(t.Context(), 1251, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+	want := []PackBreakdown{{Size: 1000, Count: 1, SKU: "CRATE-L"}, {Size: 500, Count: 1}}
+	if !reflect.DeepEqual(relabeled.Packs, want) {
+		t.Fatalf("packs after relabeling = %+v, want %+v", relabeled.Packs, want)
+	}
+}
+
+func TestSetPackSKUs_Invalid(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+	setTestPackSKUs(t, map[int]string{250: "BOX-S"})
+
+	for name, skus := range map[string]map[int]string{
+		"unknown size": {1000: "BOX-L"},
+		"empty sku":    {250: ""},
+		"padded sku":   {250: " BOX-S"},
+		"long sku":     {250: strings.Repeat("X", maxSKULength+1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := SetPackSKUs(skus); !errors.Is(err, ErrInvalidPackSKUs) {
+				t.Fatalf("error = %v, want ErrInvalidPackSKUs", err)
+			}
+			if got := PackSKUs(); !reflect.DeepEqual(got, map[int]string{250: "BOX-S"}) {
+				t.Fatalf("PackSKUs = %v, want the previous labels", got)
+			}
+		})
+	}
+}
+
+func TestPackSKUs_PrunedWithTheirSizes(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000})
+	setTestPackSKUs(t, map[int]string{250: "BOX-S", 1000: "BOX-L"})
+
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	if err := packSizeService.RemovePackSize(1000); err != nil {
+		t.Fatalf("RemovePackSize returned error: %v", err)
+	}
+	if err := packSizeService.AddPackSizes([]int{1000}); err != nil {
+		t.Fatalf("AddPackSizes returned error: %v", err)
+	}
+
+	if got := PackSKUs(); !reflect.DeepEqual(got, map[int]string{250: "BOX-S"}) {
+		t.Fatalf("PackSKUs = %v, want only the label of the kept size", got)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"slices"
+	"sync"
+)
+
+// PackSizeUsage counts how a pack size has been used by served optimizations.
+type PackSizeUsage struct {
+	Size int `json:"size"`
+	// Plans is the number of plans that shipped at least one pack of Size.
+	Plans int `json:"plans"`
+	// Packs is the number of packs of Size shipped across those plans.
+	Packs int `json:"packs"`
+}
+
+// PackUsageReport summarizes pack size usage since the counters were last
+// reset (see Config.CumulativePackUsage).
+type PackUsageReport struct {
+	Optimizations int             `json:"optimizations"`
+	PackSizes     []PackSizeUsage `json:"pack_sizes"`
+}
+
+var packUsage = struct {
+	mu            sync.Mutex
+	optimizations int
+	bySize        map[int]*PackSizeUsage
+}{
+	bySize: make(map[int]*PackSizeUsage),
+}
+
+// recordPackUsage counts the packs of a served plan.
+func recordPackUsage(plan Plan) {
+	packUsage.mu.Lock()
+	defer packUsage.mu.Unlock()
+
+	packUsage.optimizations++
+	for _, pack := range plan.Packs {
+		usage, ok := packUsage.bySize[pack.Size]
+		if !ok {
+			usage = &PackSizeUsage{Size: pack.Size}
+			packUsage.bySize[pack.Size] = usage
+		}
+		usage.Plans++
+		usage.Packs += pack.Count
+	}
+}
+
+// resetPackUsage clears the counters after a catalog change unless
+// Config.CumulativePackUsage is set.
+func resetPackUsage([]int) {
+	if currentConfig().CumulativePackUsage {
+		return
+	}
+
+	packUsage.mu.Lock()
+	defer packUsage.mu.Unlock()
+
+	packUsage.optimizations = 0
+	clear(packUsage.bySize)
+}
+
+// PackUsage returns the usage counters of every configured pack size, plus
+// any size no longer configured that still has counts, largest first.
+func PackUsage() (PackUsageReport, error) {
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		return PackUsageReport{}, err
+	}
+	configured := packSizeService.GetPackSizes()
+
+	packUsage.mu.Lock()
+	defer packUsage.mu.Unlock()
+
+	report := PackUsageReport{
+		Optimizations: packUsage.optimizations,
+		PackSizes:     make([]PackSizeUsage, 0, len(configured)+len(packUsage.bySize)),
+	}
+	for _, size := range configured {
+		usage := PackSizeUsage{Size: size}
+		if counted, ok := packUsage.bySize[size]; ok {
+			usage = *counted
+		}
+		report.PackSizes = append(report.PackSizes, usage)
+	}
+	for size, counted := range packUsage.bySize {
+		if !slices.Contains(configured, size) {
+			report.PackSizes = append(report.PackSizes, *counted)
+		}
+	}
+	slices.SortFunc(report.PackSizes, func(a, b PackSizeUsage) int {
+		return b.Size - a.Size
+	})
+
+	return report, nil
+}
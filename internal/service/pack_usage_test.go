@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// clearPackUsage zeroes the usage counters before and after the test,
+// whatever Config.CumulativePackUsage says.
+func clearPackUsage(t *testing.T) {
+	t.Helper()
+
+	reset := func() {
+		packUsage.mu.Lock()
+		defer packUsage.mu.Unlock()
+		packUsage.optimizations = 0
+		clear(packUsage.bySize)
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestPackUsage_CountsServedPlans(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000})
+	clearPackUsage(t)
+
+	for _, order := range []int{251, 1, 750} {
+		if _, err := Optimize(order); err != nil {
+			t.Fatalf("Optimize(%d) returned error: %v", order, err)
+		}
+	}
+
+	report, err := PackUsage()
+	if err != nil {
+		t.Fatalf("PackUsage returned error: %v", err)
+	}
+
+	want := PackUsageReport{
+		Optimizations: 3,
+		PackSizes: []PackSizeUsage{
+			{Size: 1000, Plans: 0, Packs: 0},
+			{Size: 500, Plans: 2, Packs: 2},
+			{Size: 250, Plans: 2, Packs: 2},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("PackUsage() = %+v, want %+v", report, want)
+	}
+}
+
+func TestPackUsage_CountsSkippedBreakdowns(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000})
+	clearPackUsage(t)
+
+	opts := Options{SkipBreakdown: true}
+	for _, optimize := range []func(context.Context, int, Options) (Plan, error){OptimizeWithOptions, OptimizeIdempotent, OptimizeIdempotent} {
+		plan, err := optimize(t.Context(), 751, opts)
+		if err != nil {
+			t.Fatalf("optimize returned error: %v", err)
+		}
+		if plan.Packs != nil {
+			t.Fatalf("Packs = %v, want none", plan.Packs)
+		}
+	}
+
+	report, err := PackUsage()
+	if err != nil {
+		t.Fatalf("PackUsage returned error: %v", err)
+	}
+	want := PackUsageReport{
+		Optimizations: 3,
+		PackSizes: []PackSizeUsage{
+			{Size: 1000, Plans: 3, Packs: 3},
+			{Size: 500, Plans: 0, Packs: 0},
+			{Size: 250, Plans: 0, Packs: 0},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("PackUsage() = %+v, want %+v", report, want)
+	}
+}
+
+func TestPackUsage_StatelessOptimizationsAreNotCounted(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+	clearPackUsage(t)
+
+	if _, err := OptimizeWith(251, []int{250, 500}); err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+
+	report, err := PackUsage()
+	if err != nil {
+		t.Fatalf("PackUsage returned error: %v", err)
+	}
+	if report.Optimizations != 0 {
+		t.Fatalf("Optimizations = %d, want 0", report.Optimizations)
+	}
+}
+
+func TestPackUsage_CatalogChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		cumulative bool
+		want       []PackSizeUsage
+	}{
+		{
+			name: "resets by default",
+			want: []PackSizeUsage{{Size: 10}, {Size: 6}},
+		},
+		{
+			name:       "cumulative keeps retired sizes",
+			cumulative: true,
+			want:       []PackSizeUsage{{Size: 500, Plans: 1, Packs: 1}, {Size: 10}, {Size: 6}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500})
+			setTestConfig(t, func(cfg *Config) { cfg.CumulativePackUsage = tc.cumulative })
+			clearPackUsage(t)
+
+			if _, err := Optimize(500); err != nil {
+				t.Fatalf("Optimize returned error: %v", err)
+			}
+			setOptimizerPackSizes(t, []int{6, 10})
+
+			report, err := PackUsage()
+			if err != nil {
+				t.Fatalf("PackUsage returned error: %v", err)
+			}
+			if !reflect.DeepEqual(report.PackSizes, tc.want) {
+				t.Fatalf("PackSizes = %+v, want %+v", report.PackSizes, tc.want)
+			}
+		})
+	}
+}
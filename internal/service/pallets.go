@@ -0,0 +1,56 @@
+package service
+
+// PalletBreakdown expresses a plan as full pallets plus the packs left over.
+type PalletBreakdown struct {
+	// Capacity is the number of items a full pallet holds.
+	Capacity    int `json:"capacity"`
+	FullPallets int `json:"full_pallets"`
+	// Remainder lists the packs not on a full pallet, by size descending.
+	Remainder      []PackBreakdown `json:"remainder"`
+	RemainderItems int             `json:"remainder_items"`
+}
+
+// palletize loads packs (sorted by size descending) onto pallets of capacity
+// items. Each pallet is filled greedily, taking as many of the largest
+// remaining packs as fit before moving to smaller ones; it counts as full only
+// when it holds exactly capacity items. Loading stops at the first pallet that
+// cannot be filled exactly, and every pack left becomes the remainder.
+func palletize(packs []PackBreakdown, capacity int) *PalletBreakdown {
+	remaining := make([]PackBreakdown, len(packs))
+	copy(remaining, packs)
+
+	result := &PalletBreakdown{Capacity: capacity}
+	for {
+		take := make([]int, len(remaining))
+		space := capacity
+		for i, pack := range remaining {
+			take[i] = min(pack.Count, space/pack.Size)
+			space -= take[i] * pack.Size
+		}
+		if space != 0 {
+			break
+		}
+
+		// The same pallet can be repeated until one of its sizes runs out.
+		repeats := -1
+		for i, count := range take {
+			if count > 0 && (repeats < 0 || remaining[i].Count/count < repeats) {
+				repeats = remaining[i].Count / count
+			}
+		}
+		for i, count := range take {
+			remaining[i].Count -= count * repeats
+		}
+		result.FullPallets += repeats
+	}
+
+	result.Remainder = make([]PackBreakdown, 0, len(remaining))
+	for _, pack := range remaining {
+		if pack.Count > 0 {
+			result.Remainder = append(result.Remainder, pack)
+			result.RemainderItems += pack.Size * pack.Count
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestPalletize(t *testing.T) {
+	tests := []struct {
+		name     string
+		packs    []PackBreakdown
+		capacity int
+		want     *PalletBreakdown
+	}{
+		{
+			name:     "full pallets plus remainder",
+			packs:    []PackBreakdown{{Size: 5000, Count: 2}, {Size: 2000, Count: 1}, {Size: 250, Count: 1}},
+			capacity: 5000,
+			want: &PalletBreakdown{
+				Capacity:       5000,
+				FullPallets:    2,
+				Remainder:      []PackBreakdown{{Size: 2000, Count: 1}, {Size: 250, Count: 1}},
+				RemainderItems: 2250,
+			},
+		},
+		{
+			name:     "mixed packs fill a pallet",
+			packs:    []PackBreakdown{{Size: 500, Count: 5}, {Size: 250, Count: 3}},
+			capacity: 1000,
+			want: &PalletBreakdown{
+				Capacity:       1000,
+				FullPallets:    3,
+				Remainder:      []PackBreakdown{{Size: 250, Count: 1}},
+				RemainderItems: 250,
+			},
+		},
+		{
+			name:     "capacity below every pack",
+			packs:    []PackBreakdown{{Size: 500, Count: 1}},
+			capacity: 100,
+			want: &PalletBreakdown{
+				Capacity:       100,
+				Remainder:      []PackBreakdown{{Size: 500, Count: 1}},
+				RemainderItems: 500,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := palletize(tc.packs, tc.capacity)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("palletize() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_PalletCapacity(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	plan, err := OptimizeWithOptions(context.Background(), 12001, Options{PalletCapacity: 5000, SortByCount: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if plan.Pallets == nil || plan.Pallets.FullPallets != 2 || plan.Pallets.RemainderItems != 2250 {
+		t.Fatalf("Pallets = %+v, want 2 full pallets and 2250 remainder items", plan.Pallets)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// planID derives a plan's ID from its inputs rather than its outputs, so
+// clients can reconcile results without storing the request: the order, the
+// normalized catalog, the catalog version and the non-zero options. Zero
+// options are left out so adding an option never changes existing IDs. The
+// objective is hashed as resolved, so a DEFAULT_OBJECTIVE change changes the
+// IDs it changes plans for, and an explicit fewest_packs hashes like none.
+//
+// The catalog is hashed along with its version because versions restart at
+// one when the process does.
+func planID(itemsOrdered int, packSizes []int, version uint64, opts Options) string {
+	sorted := slices.Clone(packSizes)
+	slices.SortFunc(sorted, func(a, b int) int { return b - a })
+
+	h := sha256.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(itemsOrdered))
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], version)
+	h.Write(buf[:])
+	h.Write([]byte(catalogHash(sorted)))
+
+	// The table limit decides whether a plan can be computed, never which.
+	opts.MaxTableEntries = 0
+	// A switch penalty is hashed as its own option.
+	if opts.SwitchPenalty == 0 {
+		// The plan was computed, so the objective resolved.
+		opts.Objective, _ = resolveObjective(opts)
+		if opts.Objective == ObjectiveFewestPacks {
+			opts.Objective = ""
+		}
+	}
+	value := reflect.ValueOf(opts)
+	for i := range value.NumField() {
+		if field := value.Field(i); !field.IsZero() {
+			fmt.Fprintf(h, ";%s=%v", value.Type().Field(i).Name, field.Interface())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
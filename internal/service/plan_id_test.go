@@ -0,0 +1,81 @@
+package service
+
+import "testing"
+
+func TestPlanID_StableForIdenticalInputs(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000})
+
+	first, err := Optimize(251)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	second, err := Optimize(251)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	idempotent, err := OptimizeIdempotent(t.Context(), 251, Options{})
+	if err != nil {
+		t.Fatalf("OptimizeIdempotent returned error: %v", err)
+	}
+
+	if first.PlanID == "" {
+		t.Fatal("expected a plan ID")
+	}
+	if second.PlanID != first.PlanID || idempotent.PlanID != first.PlanID {
+		t.Fatalf("plan IDs differ for identical inputs: %q, %q, %q", first.PlanID, second.PlanID, idempotent.PlanID)
+	}
+}
+
+func TestPlanID_ChangesWithInputs(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000})
+
+	base, err := Optimize(251)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	otherOrder, err := Optimize(252)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	withOptions, err := OptimizeWithOptions(t.Context(), 251, Options{SortByCount: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	// Setting the same sizes again still bumps the catalog version.
+	setOptimizerPackSizes(t, []int{250, 500, 1000})
+	newVersion, err := Optimize(251)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+
+	seen := map[string]string{base.PlanID: "base"}
+	for name, plan := range map[string]Plan{"order": otherOrder, "options": withOptions, "catalog version": newVersion} {
+		if previous, ok := seen[plan.PlanID]; ok {
+			t.Fatalf("changing the %s kept plan ID %q of %s", name, plan.PlanID, previous)
+		}
+		seen[plan.PlanID] = name
+	}
+}
+
+func TestPlanID_IgnoresPackSizeOrder(t *testing.T) {
+	if planID(251, []int{250, 500}, 1, Options{}) != planID(251, []int{500, 250}, 1, Options{}) {
+		t.Fatal("expected the plan ID to ignore pack size order")
+	}
+}
+
+func TestPlanID_HashesResolvedObjective(t *testing.T) {
+	defaultID := planID(251, []int{250, 500}, 1, Options{})
+	if explicit := planID(251, []int{250, 500}, 1, Options{Objective: ObjectiveFewestPacks}); explicit != defaultID {
+		t.Fatalf("explicit fewest_packs ID = %q, want the default %q", explicit, defaultID)
+	}
+
+	setTestConfig(t, func(cfg *Config) { cfg.DefaultObjective = ObjectiveFewestSizes })
+	serverDefault := planID(251, []int{250, 500}, 1, Options{})
+	if serverDefault == defaultID {
+		t.Fatal("changing DEFAULT_OBJECTIVE kept the plan ID")
+	}
+	if explicit := planID(251, []int{250, 500}, 1, Options{Objective: ObjectiveFewestSizes}); explicit != serverDefault {
+		t.Fatalf("explicit fewest_sizes ID = %q, want the server default's %q", explicit, serverDefault)
+	}
+}
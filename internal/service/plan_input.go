@@ -0,0 +1,31 @@
+package service
+
+import "slices"
+
+// PlanInput is the effective request a plan was computed for, so clients can
+// spot when server-side defaults or the catalog differ from what they expect.
+type PlanInput struct {
+	// ItemsOrdered is the order as requested; ResolvedItemsOrdered is the
+	// order actually planned, which differs when it was snapped to an exact
+	// total.
+	ItemsOrdered         int    `json:"items_ordered"`
+	ResolvedItemsOrdered int    `json:"resolved_items_ordered"`
+	Objective            string `json:"objective"`
+	CatalogVersion       uint64 `json:"catalog_version"`
+	PackSizes            []int  `json:"pack_sizes"`
+}
+
+func newPlanInput(itemsOrdered int, plan Plan, packSizes []int, version uint64, opts Options) *PlanInput {
+	// The plan was computed, so the objective resolved.
+	objective, _ := resolveObjective(opts)
+	if plan.ObjectiveFallback {
+		objective = ObjectiveFewestPacks
+	}
+	return &PlanInput{
+		ItemsOrdered:         itemsOrdered,
+		ResolvedItemsOrdered: plan.ItemsOrdered,
+		Objective:            objective,
+		CatalogVersion:       version,
+		PackSizes:            slices.Clone(packSizes),
+	}
+}
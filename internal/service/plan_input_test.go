@@ -0,0 +1,56 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanInput_EchoesEffectiveRequest(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	_, version := packSizeService.GetCatalog()
+
+	tests := []struct {
+		name string
+		opts Options
+		want PlanInput
+	}{
+		{
+			name: "snapped order",
+			opts: Options{EchoInput: true, SnapToExact: true},
+			want: PlanInput{ItemsOrdered: 251, ResolvedItemsOrdered: 500, Objective: ObjectiveFewestPacks, CatalogVersion: version, PackSizes: []int{500, 250}},
+		},
+		{
+			name: "switch penalty",
+			opts: Options{EchoInput: true, SwitchPenalty: 2},
+			want: PlanInput{ItemsOrdered: 251, ResolvedItemsOrdered: 251, Objective: ObjectiveSwitchPenalty, CatalogVersion: version, PackSizes: []int{500, 250}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := OptimizeWithOptions(t.Context(), 251, tt.opts)
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+			if plan.Input == nil || !reflect.DeepEqual(*plan.Input, tt.want) {
+				t.Fatalf("input = %+v, want %+v", plan.Input, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanInput_OnlyWithEchoInput(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	plan, err := Optimize(251)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if plan.Input != nil {
+		t.Fatalf("expected no input echo, got %+v", plan.Input)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultProfile names the profile a pack size service starts with.
+const DefaultProfile = "default"
+
+// maxProfileNameLength bounds profile names, which appear in URLs.
+const maxProfileNameLength = 64
+
+var (
+	ErrProfileNotFound    = errors.New("pack size profile not found")
+	ErrInvalidProfileName = errors.New("profile names must be 1 to 64 letters, digits, '-' or '_'")
+)
+
+// PackSizeProfile is one named pack size lineup, e.g. one brand's.
+type PackSizeProfile struct {
+	Name      string `json:"name"`
+	PackSizes []int  `json:"pack_sizes"`
+	Active    bool   `json:"active"`
+}
+
+// ProfilePackSizeService stores several named pack size profiles, one of
+// which is active. The PackSizeService methods read and update the active
+// profile, so optimizations always use it.
+type ProfilePackSizeService interface {
+	PackSizeService
+	// SetProfile creates or replaces the named profile; replacing the active
+	// one is a catalog update like SetPackSizes.
+	SetProfile(name string, sizes []int) error
+	// GetProfile returns the pack sizes of the named profile, or
+	// ErrProfileNotFound.
+	GetProfile(name string) ([]int, error)
+	// ListProfiles returns every profile, sorted by name.
+	ListProfiles() []PackSizeProfile
+	// ActivateProfile makes the named profile the catalog; it fails with
+	// ErrProfileNotFound for an unknown name.
+	ActivateProfile(name string) error
+}
+
+// GetProfilePackSizeService returns the singleton pack size service with its
+// profile methods.
+func GetProfilePackSizeService() (ProfilePackSizeService, error) {
+	if _, err := GetPackSizeService(); err != nil {
+		return nil, err
+	}
+	return packSizeServiceInstance, nil
+}
+
+func validateProfileName(name string) error {
+	if name == "" || len(name) > maxProfileNameLength {
+		return fmt.Errorf("%w: got %q", ErrInvalidProfileName, name)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return fmt.Errorf("%w: got %q", ErrInvalidProfileName, name)
+		}
+	}
+	return nil
+}
+
+// SetProfile validates sizes and stores them under name.
+func (s *InMemoryPackSizeService) SetProfile(name string, sizes []int) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	normalized, err := NormalizePackSizes(sizes)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name == s.active {
+		s.packSizes = normalized
+		s.version++
+		s.notifyLocked()
+		return nil
+	}
+	if s.profiles == nil {
+		s.profiles = make(map[string][]int)
+	}
+	s.profiles[name] = normalized
+	return nil
+}
+
+// GetProfile returns a copy of the named profile's pack sizes.
+func (s *InMemoryPackSizeService) GetProfile(name string) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if name == s.active {
+		return slices.Clone(s.packSizes), nil
+	}
+	sizes, ok := s.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrProfileNotFound, name)
+	}
+	return slices.Clone(sizes), nil
+}
+
+// ListProfiles returns a copy of every profile, the active one flagged.
+func (s *InMemoryPackSizeService) ListProfiles() []PackSizeProfile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profiles := []PackSizeProfile{{Name: s.active, PackSizes: slices.Clone(s.packSizes), Active: true}}
+	for name, sizes := range s.profiles {
+		profiles = append(profiles, PackSizeProfile{Name: name, PackSizes: slices.Clone(sizes)})
+	}
+	slices.SortFunc(profiles, func(a, b PackSizeProfile) int { return strings.Compare(a.Name, b.Name) })
+	return profiles
+}
+
+// ActivateProfile swaps the named profile in as the catalog, as one update;
+// activating the active profile changes nothing.
+func (s *InMemoryPackSizeService) ActivateProfile(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name == s.active {
+		return nil
+	}
+	sizes, ok := s.profiles[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrProfileNotFound, name)
+	}
+
+	// The active profile lives in packSizes, so only inactive ones are
+	// kept in profiles.
+	s.profiles[s.active] = s.packSizes
+	delete(s.profiles, name)
+	s.active = name
+	s.packSizes = sizes
+	s.version++
+	s.notifyLocked()
+	return nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInMemoryPackSizeService_Profiles(t *testing.T) {
+	svc, err := NewInMemoryPackSizeService([]int{250, 500})
+	if err != nil {
+		t.Fatalf("NewInMemoryPackSizeService returned error: %v", err)
+	}
+	var notified []int
+	svc.OnChange(func(packSizes []int) { notified = packSizes })
+	_, initial := svc.GetCatalog()
+
+	if err := svc.SetProfile("brand-b", []int{30, 10, 20, 10}); err != nil {
+		t.Fatalf("SetProfile returned error: %v", err)
+	}
+	if sizes, version := svc.GetCatalog(); !reflect.DeepEqual(sizes, []int{500, 250}) || version != initial {
+		t.Fatalf("storing an inactive profile changed the catalog to %v version %d", sizes, version)
+	}
+	if sizes, err := svc.GetProfile("brand-b"); err != nil || !reflect.DeepEqual(sizes, []int{30, 20, 10}) {
+		t.Fatalf("GetProfile(brand-b) = %v, %v", sizes, err)
+	}
+
+	if err := svc.ActivateProfile("brand-b"); err != nil {
+		t.Fatalf("ActivateProfile returned error: %v", err)
+	}
+	sizes, version := svc.GetCatalog()
+	if !reflect.DeepEqual(sizes, []int{30, 20, 10}) || version != initial+1 {
+		t.Fatalf("catalog = %v version %d, want [30 20 10] version %d", sizes, version, initial+1)
+	}
+	if !reflect.DeepEqual(notified, []int{30, 20, 10}) {
+		t.Fatalf("listener got %v, want [30 20 10]", notified)
+	}
+
+	// Single-list updates apply to the active profile only.
+	if err := svc.AddPackSizes([]int{40}); err != nil {
+		t.Fatalf("AddPackSizes returned error: %v", err)
+	}
+	want := []PackSizeProfile{
+		{Name: "brand-b", PackSizes: []int{40, 30, 20, 10}, Active: true},
+		{Name: DefaultProfile, PackSizes: []int{500, 250}},
+	}
+	if got := svc.ListProfiles(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListProfiles = %+v, want %+v", got, want)
+	}
+
+	if err := svc.ActivateProfile("brand-b"); err != nil {
+		t.Fatalf("ActivateProfile of the active profile returned error: %v", err)
+	}
+	if _, after := svc.GetCatalog(); after != initial+2 {
+		t.Fatalf("version after re-activating = %d, want %d", after, initial+2)
+	}
+
+	if err := svc.SetProfile("brand-b", []int{7}); err != nil {
+		t.Fatalf("SetProfile on the active profile returned error: %v", err)
+	}
+	if sizes := svc.GetPackSizes(); !reflect.DeepEqual(sizes, []int{7}) {
+		t.Fatalf("pack sizes after replacing the active profile = %v, want [7]", sizes)
+	}
+
+	if err := svc.ActivateProfile(DefaultProfile); err != nil {
+		t.Fatalf("ActivateProfile(default) returned error: %v", err)
+	}
+	if sizes := svc.GetPackSizes(); !reflect.DeepEqual(sizes, []int{500, 250}) {
+		t.Fatalf("pack sizes after switching back = %v, want [500 250]", sizes)
+	}
+}
+
+func TestInMemoryPackSizeService_ProfileErrors(t *testing.T) {
+	svc, err := NewInMemoryPackSizeService([]int{250, 500})
+	if err != nil {
+		t.Fatalf("NewInMemoryPackSizeService returned error: %v", err)
+	}
+
+	for _, name := range []string{"", "brand b", "brand/b", strings.Repeat("a", maxProfileNameLength+1)} {
+		if err := svc.SetProfile(name, []int{10}); !errors.Is(err, ErrInvalidProfileName) {
+			t.Fatalf("SetProfile(%q): expected ErrInvalidProfileName, got %v", name, err)
+		}
+	}
+	if err := svc.SetProfile("brand-b", []int{0}); !errors.Is(err, ErrInvalidPackSizes) {
+		t.Fatalf("expected ErrInvalidPackSizes, got %v", err)
+	}
+	if _, err := svc.GetProfile("brand-b"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+	if err := svc.ActivateProfile("brand-b"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+func TestGetProfilePackSizeService_SharesTheSingleton(t *testing.T) {
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		t.Fatalf("GetPackSizeService returned error: %v", err)
+	}
+	profiles, err := GetProfilePackSizeService()
+	if err != nil {
+		t.Fatalf("GetProfilePackSizeService returned error: %v", err)
+	}
+	if PackSizeService(profiles) != packSizeService {
+		t.Fatal("expected GetProfilePackSizeService to return the pack size service singleton")
+	}
+}
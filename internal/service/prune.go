@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxPruneOrders bounds the order distribution accepted by SuggestPrunablePackSizes.
+const maxPruneOrders = 1000
+
+var ErrInvalidOrderDistribution = errors.New("orders must contain at least one order")
+
+// PruneSuggestion reports which pack sizes an order distribution never uses.
+type PruneSuggestion struct {
+	PackSizes []int `json:"pack_sizes"`
+	// UnusedPackSizes could be removed without changing any plan in the
+	// distribution, in descending order.
+	UnusedPackSizes []int `json:"unused_pack_sizes"`
+	// Usage counts the packs of each size shipped across the distribution.
+	Usage          map[int]int `json:"usage"`
+	OrdersAnalyzed int         `json:"orders_analyzed"`
+}
+
+// SuggestPrunablePackSizes optimizes every order in orders against packSizes
+// and reports the sizes that no plan uses.
+func SuggestPrunablePackSizes(orders []int, packSizes []int) (PruneSuggestion, error) {
+	if len(orders) == 0 {
+		return PruneSuggestion{}, ErrInvalidOrderDistribution
+	}
+	if len(orders) > maxPruneOrders {
+		return PruneSuggestion{}, fmt.Errorf("%w: %d orders exceeds max %d", ErrInvalidOrderDistribution, len(orders), maxPruneOrders)
+	}
+
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return PruneSuggestion{}, err
+	}
+
+	usage := make(map[int]int, len(normalized))
+	for _, size := range normalized {
+		usage[size] = 0
+	}
+
+	for i, ordered := range orders {
+		plan, err := OptimizeWith(ordered, normalized)
+		if err != nil {
+			return PruneSuggestion{}, fmt.Errorf("orders[%d]: %w", i, err)
+		}
+		for _, pack := range plan.Packs {
+			usage[pack.Size] += pack.Count
+		}
+	}
+
+	unused := make([]int, 0, len(normalized))
+	for _, size := range normalized {
+		if usage[size] == 0 {
+			unused = append(unused, size)
+		}
+	}
+
+	return PruneSuggestion{
+		PackSizes:       normalized,
+		UnusedPackSizes: unused,
+		Usage:           usage,
+		OrdersAnalyzed:  len(orders),
+	}, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSuggestPrunablePackSizes_FlagsRedundantSize(t *testing.T) {
+	got, err := SuggestPrunablePackSizes([]int{1000, 5000, 6000, 11000}, []int{1000, 2000, 5000})
+	if err != nil {
+		t.Fatalf("SuggestPrunablePackSizes returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.UnusedPackSizes, []int{2000}) {
+		t.Fatalf("UnusedPackSizes = %v, want [2000]", got.UnusedPackSizes)
+	}
+	if got.Usage[5000] != 4 || got.Usage[1000] != 3 || got.Usage[2000] != 0 {
+		t.Fatalf("unexpected usage: %v", got.Usage)
+	}
+	if got.OrdersAnalyzed != 4 {
+		t.Fatalf("OrdersAnalyzed = %d, want 4", got.OrdersAnalyzed)
+	}
+}
+
+func TestSuggestPrunablePackSizes_AllSizesUsed(t *testing.T) {
+	got, err := SuggestPrunablePackSizes([]int{251, 501}, []int{250, 500})
+	if err != nil {
+		t.Fatalf("SuggestPrunablePackSizes returned error: %v", err)
+	}
+	if len(got.UnusedPackSizes) != 0 {
+		t.Fatalf("UnusedPackSizes = %v, want none", got.UnusedPackSizes)
+	}
+}
+
+func TestSuggestPrunablePackSizes_InvalidInput(t *testing.T) {
+	if _, err := SuggestPrunablePackSizes(nil, []int{250}); !errors.Is(err, ErrInvalidOrderDistribution) {
+		t.Fatalf("expected ErrInvalidOrderDistribution, got %v", err)
+	}
+	if _, err := SuggestPrunablePackSizes(make([]int, maxPruneOrders+1), []int{250}); !errors.Is(err, ErrInvalidOrderDistribution) {
+		t.Fatalf("expected ErrInvalidOrderDistribution for oversized distribution, got %v", err)
+	}
+	if _, err := SuggestPrunablePackSizes([]int{250, 0}, []int{250}); !errors.Is(err, ErrInvalidItemsOrdered) {
+		t.Fatalf("expected ErrInvalidItemsOrdered, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"container/list"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// planComputations counts plans computed by running the DP, as opposed to
+// being served from the result cache. Tests use it to observe cache hits.
+var planComputations atomic.Int64
+
+type resultCacheKey struct {
+	catalog      string
+	itemsOrdered int
+}
+
+type resultCacheEntry struct {
+	key  resultCacheKey
+	plan Plan
+}
+
+// resultCache holds the plans of the most recently used distinct orders, up
+// to Config.ResultCacheSize, keyed by catalog so a catalog change never
+// serves a stale plan. Only option-free plans are stored.
+var resultCache = struct {
+	mu      sync.Mutex
+	order   *list.List
+	entries map[resultCacheKey]*list.Element
+}{
+	order:   list.New(),
+	entries: make(map[resultCacheKey]*list.Element),
+}
+
+// primeWait tracks primings started after catalog changes.
+var primeWait sync.WaitGroup
+
+// cachedPlan returns a copy of the cached plan for itemsOrdered under
+// sortedPackSizes, if any.
+func cachedPlan(itemsOrdered int, sortedPackSizes []int) (Plan, bool) {
+	if currentConfig().ResultCacheSize <= 0 {
+		return Plan{}, false
+	}
+
+	key := resultCacheKey{catalog: catalogHash(sortedPackSizes), itemsOrdered: itemsOrdered}
+
+	resultCache.mu.Lock()
+	defer resultCache.mu.Unlock()
+
+	elem, ok := resultCache.entries[key]
+	if !ok {
+		return Plan{}, false
+	}
+	resultCache.order.MoveToFront(elem)
+
+	plan := elem.Value.(*resultCacheEntry).plan
+	plan.Packs = slices.Clone(plan.Packs)
+	return plan, true
+}
+
+// storePlan caches a copy of plan, evicting the least recently used orders
+// beyond Config.ResultCacheSize.
+func storePlan(plan Plan, sortedPackSizes []int) {
+	size := currentConfig().ResultCacheSize
+	if size <= 0 {
+		return
+	}
+
+	key := resultCacheKey{catalog: catalogHash(sortedPackSizes), itemsOrdered: plan.ItemsOrdered}
+	plan.Packs = slices.Clone(plan.Packs)
+
+	resultCache.mu.Lock()
+	defer resultCache.mu.Unlock()
+
+	if elem, ok := resultCache.entries[key]; ok {
+		elem.Value.(*resultCacheEntry).plan = plan
+		resultCache.order.MoveToFront(elem)
+		return
+	}
+
+	resultCache.entries[key] = resultCache.order.PushFront(&resultCacheEntry{key: key, plan: plan})
+	for resultCache.order.Len() > size {
+		oldest := resultCache.order.Back()
+		resultCache.order.Remove(oldest)
+		delete(resultCache.entries, oldest.Value.(*resultCacheEntry).key)
+	}
+}
+
+// PrimeResultCache computes and caches the plans of Config.PrimeOrders for the
+// configured pack sizes, so those orders are served without computing on their
+// first request. It fails on the first order that cannot be optimized.
+func PrimeResultCache() error {
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		return err
+	}
+
+	return primeOrders(packSizeService.GetPackSizes(), currentConfig().PrimeOrders)
+}
+
+func primeOrders(packSizes []int, orders []int) error {
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return err
+	}
+
+	for _, itemsOrdered := range orders {
+		if _, ok := cachedPlan(itemsOrdered, normalized); ok {
+			continue
+		}
+		if _, err := OptimizeWith(itemsOrdered, normalized); err != nil {
+			return fmt.Errorf("prime order %d: %w", itemsOrdered, err)
+		}
+	}
+	return nil
+}
+
+// startPrimeOrders re-primes Config.PrimeOrders for a new catalog in the
+// background. Orders were validated at startup, so errors are not reported.
+func startPrimeOrders(packSizes []int) {
+	orders := currentConfig().PrimeOrders
+	if len(orders) == 0 {
+		return
+	}
+
+	primeWait.Add(1)
+	go func() {
+		defer primeWait.Done()
+		_ = primeOrders(packSizes, orders)
+	}()
+}
+
+// waitForPrimeOrders blocks until every background priming has finished.
+func waitForPrimeOrders() {
+	primeWait.Wait()
+}
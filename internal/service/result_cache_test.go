@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// resetResultCache empties the result cache before and after the test.
+func resetResultCache(t *testing.T) {
+	t.Helper()
+
+	reset := func() {
+		resultCache.mu.Lock()
+		defer resultCache.mu.Unlock()
+		resultCache.order.Init()
+		clear(resultCache.entries)
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestResultCache_ServesRepeatedOrders(t *testing.T) {
+	resetResultCache(t)
+	setTestConfig(t, func(cfg *Config) { cfg.ResultCacheSize = 10 })
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	first, err := Optimize(12001)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+
+	before := planComputations.Load()
+	second, err := Optimize(12001)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if got := planComputations.Load() - before; got != 0 {
+		t.Fatalf("repeated order computed %d times, want 0", got)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("cached plan = %+v, want %+v", second, first)
+	}
+}
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	resetResultCache(t)
+	setTestConfig(t, func(cfg *Config) { cfg.ResultCacheSize = 2 })
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	for _, order := range []int{1, 251, 1} {
+		if _, err := Optimize(order); err != nil {
+			t.Fatalf("Optimize(%d) returned error: %v", order, err)
+		}
+	}
+	if _, err := Optimize(501); err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+
+	normalized := []int{500, 250}
+	if _, ok := cachedPlan(251, normalized); ok {
+		t.Fatal("least recently used order 251 is still cached")
+	}
+	for _, order := range []int{1, 501} {
+		if _, ok := cachedPlan(order, normalized); !ok {
+			t.Fatalf("order %d is not cached", order)
+		}
+	}
+}
+
+func TestResultCache_OptionsDoNotLeakIntoCache(t *testing.T) {
+	resetResultCache(t)
+	setTestConfig(t, func(cfg *Config) { cfg.ResultCacheSize = 10 })
+	setOptimizerPackSizes(t, []int{3, 5})
+
+	if _, err := OptimizeWithOptions(t.Context(), 7, Options{SortByCount: true, SnapToExact: true}); err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	plan, err := Optimize(7)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	fresh, err := OptimizeWith(7, []int{3, 5})
+	if err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+	// Only plans for the configured catalog carry an ID.
+	plan.PlanID = ""
+	if !reflect.DeepEqual(plan, fresh) {
+		t.Fatalf("cached plan = %+v, want %+v", plan, fresh)
+	}
+}
+
+func TestResultCache_SkipsPlansOfRaisedTableLimits(t *testing.T) {
+	resetResultCache(t)
+	setTestConfig(t, func(cfg *Config) { cfg.ResultCacheSize = 10 })
+	setOptimizerPackSizes(t, []int{23, 31, 53})
+
+	if _, err := OptimizeWithOptions(t.Context(), 3_000_000, Options{MaxTableEntries: 3_100_000}); err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if _, ok := cachedPlan(3_000_000, []int{53, 31, 23}); ok {
+		t.Fatal("a plan only the raised table limit allows was cached")
+	}
+	if _, err := Optimize(3_000_000); !errors.Is(err, ErrOptimizationTooLarge) {
+		t.Fatalf("Optimize error = %v, want ErrOptimizationTooLarge without the raised limit", err)
+	}
+}
+
+func TestPrimeResultCache_PrimedOrdersSkipCompute(t *testing.T) {
+	resetResultCache(t)
+	setTestConfig(t, func(cfg *Config) {
+		cfg.ResultCacheSize = 10
+		cfg.PrimeOrders = []int{251, 12001}
+	})
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+	waitForPrimeOrders()
+
+	if err := PrimeResultCache(); err != nil {
+		t.Fatalf("PrimeResultCache returned error: %v", err)
+	}
+
+	before := planComputations.Load()
+	for _, order := range []int{251, 12001} {
+		if _, err := Optimize(order); err != nil {
+			t.Fatalf("Optimize(%d) returned error: %v", order, err)
+		}
+	}
+	if got := planComputations.Load() - before; got != 0 {
+		t.Fatalf("primed orders computed %d times, want 0", got)
+	}
+}
+
+func TestPrimeResultCache_RePrimesAfterCatalogChange(t *testing.T) {
+	resetResultCache(t)
+	setTestConfig(t, func(cfg *Config) {
+		cfg.ResultCacheSize = 10
+		cfg.PrimeOrders = []int{13}
+	})
+	t.Cleanup(waitForPrimeOrders)
+
+	setOptimizerPackSizes(t, []int{6, 10})
+	waitForPrimeOrders()
+
+	before := planComputations.Load()
+	plan, err := Optimize(13)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if got := planComputations.Load() - before; got != 0 {
+		t.Fatalf("primed order computed %d times after catalog change, want 0", got)
+	}
+	if plan.TotalItems != 16 {
+		t.Fatalf("TotalItems = %d, want 16 for the new catalog", plan.TotalItems)
+	}
+}
+
+func TestPrimeResultCache_RejectsUnoptimizableOrder(t *testing.T) {
+	resetResultCache(t)
+	setTestConfig(t, func(cfg *Config) {
+		cfg.ResultCacheSize = 10
+		cfg.PrimeOrders = []int{maxTableEntries}
+	})
+	setOptimizerPackSizes(t, []int{250, 500})
+	waitForPrimeOrders()
+
+	if err := PrimeResultCache(); err == nil {
+		t.Fatal("expected error for an order beyond the table limit")
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidShipmentCap    = errors.New("max_items_per_shipment is smaller than every pack size")
+	ErrTooManyShipments      = errors.New("order needs too many shipments")
+	ErrInvalidShipmentWeight = errors.New("item_weight and max_shipment_weight must both be positive")
+	ErrShipmentTooLight      = errors.New("max_shipment_weight cannot carry a single pack")
+)
+
+// maxShipments bounds how many shipments a single order may be split into.
+const maxShipments = 1000
+
+// splitIntoShipments splits itemsOrdered into shipments of at most capacity
+// items each. Shipments are filled to the cap: every shipment but the last
+// carries the largest reachable total not above capacity, and the last one is
+// the minimum-overfill plan for the remainder, provided it fits under the cap.
+// When the remainder's best total would exceed the cap, one more full
+// shipment is taken and the loop continues. Each shipment is optimal for its
+// share, but the split as a whole may overfill more than an unconstrained plan.
+func splitIntoShipments(itemsOrdered, capacity int, sortedPackSizes []int) ([]Plan, error) {
+	table, err := newCoveringTable(sortedPackSizes, capacity)
+	if err != nil {
+		return nil, err
+	}
+	table.buildOptimalPackingTable()
+
+	fullTotal := 0
+	for total := capacity; total > 0; total-- {
+		if table.minPacks[total] != table.unreachablePacks {
+			fullTotal = total
+			break
+		}
+	}
+	if fullTotal == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidShipmentCap, capacity)
+	}
+	if itemsOrdered/fullTotal >= maxShipments {
+		return nil, fmt.Errorf("%w: more than %d shipments of at most %d items", ErrTooManyShipments, maxShipments, capacity)
+	}
+
+	full, err := table.shipment(fullTotal, fullTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	var shipments []Plan
+	for remaining := itemsOrdered; remaining > 0; remaining -= fullTotal {
+		if remaining <= capacity {
+			if total, ok := table.firstReachable(remaining, capacity); ok {
+				last, err := table.shipment(remaining, total)
+				if err != nil {
+					return nil, err
+				}
+				return append(shipments, last), nil
+			}
+		}
+		shipments = append(shipments, full)
+	}
+
+	return shipments, nil
+}
+
+// shipmentCapacity returns the items a shipment may carry under opts: the
+// tighter of Options.MaxItemsPerShipment and the weight cap, or zero when
+// neither is set. A weight cap is the items whose summed weight stays within
+// MaxShipmentWeight; splitting then follows splitIntoShipments, so the same
+// fill-to-the-cap heuristic bounds the overfill.
+func shipmentCapacity(opts Options, sortedPackSizes []int) (int, error) {
+	if opts.ItemWeight == 0 && opts.MaxShipmentWeight == 0 {
+		return opts.MaxItemsPerShipment, nil
+	}
+	if opts.ItemWeight <= 0 || opts.MaxShipmentWeight <= 0 || opts.ItemWeight > maxInt32Value || opts.MaxShipmentWeight > maxInt32Value {
+		return 0, fmt.Errorf("%w: got %d and %d", ErrInvalidShipmentWeight, opts.ItemWeight, opts.MaxShipmentWeight)
+	}
+
+	capacity := opts.MaxShipmentWeight / opts.ItemWeight
+	smallest := sortedPackSizes[len(sortedPackSizes)-1]
+	if capacity < smallest {
+		return 0, fmt.Errorf("%w: %d fits %d items, the smallest pack holds %d", ErrShipmentTooLight, opts.MaxShipmentWeight, capacity, smallest)
+	}
+	if opts.MaxItemsPerShipment > 0 {
+		capacity = min(capacity, opts.MaxItemsPerShipment)
+	}
+	return capacity, nil
+}
+
+// shipment builds the plan shipping total items for a share of itemsOrdered.
+func (t *packingTable) shipment(itemsOrdered, total int) (Plan, error) {
+	breakdown, err := t.buildBreakdown(total)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return Plan{
+		ItemsOrdered: itemsOrdered,
+		TotalItems:   total,
+		TotalPacks:   t.minPacks[total],
+		Packs:        breakdown,
+		DrivingSize:  t.prevPack[total],
+		Optimal:      true,
+		Algorithm:    AlgorithmDP,
+	}, nil
+}
+
+// asShipment returns the shipment shipping all of plan: the same fields as a
+// shipment of splitIntoShipments, without the request-level extras (such as
+// an Explanation) plan may carry.
+func asShipment(plan Plan) Plan {
+	return Plan{
+		ItemsOrdered: plan.ItemsOrdered,
+		TotalItems:   plan.TotalItems,
+		TotalPacks:   plan.TotalPacks,
+		Packs:        append([]PackBreakdown(nil), plan.Packs...),
+		DrivingSize:  plan.DrivingSize,
+		Optimal:      plan.Optimal,
+		Algorithm:    plan.Algorithm,
+	}
+}
+
+// applyShipmentCap replaces plan's totals and packs with the sum of its
+// shipments under capacity. Every split feature (Options.MaxItemsPerShipment,
+// the weight cap) goes through it, so plans have one shape whichever cap
+// applies: Shipments holds one Plan per shipment and the top level is their
+// aggregate, reported as AlgorithmShipmentSplit. A plan that already fits
+// ships as one shipment and keeps its algorithm.
+func applyShipmentCap(plan *Plan, capacity int, sortedPackSizes []int) error {
+	if plan.TotalItems <= capacity {
+		plan.Shipments = []Plan{asShipment(*plan)}
+		return nil
+	}
+
+	shipments, err := splitIntoShipments(plan.ItemsOrdered, capacity, sortedPackSizes)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[int]int)
+	plan.TotalItems, plan.TotalPacks = 0, 0
+	for _, shipment := range shipments {
+		plan.TotalItems += shipment.TotalItems
+		plan.TotalPacks += shipment.TotalPacks
+		for _, pack := range shipment.Packs {
+			counts[pack.Size] += pack.Count
+		}
+	}
+
+	plan.Packs = plan.Packs[:0]
+	for _, size := range sortedPackSizes {
+		if count := counts[size]; count > 0 {
+			plan.Packs = append(plan.Packs, PackBreakdown{Size: size, Count: count})
+		}
+	}
+	plan.DrivingSize = shipments[len(shipments)-1].DrivingSize
+	// Filling shipments to the cap is a heuristic over the whole order.
+	plan.Optimal = false
+	plan.Algorithm = AlgorithmShipmentSplit
+	plan.Shipments = shipments
+	return nil
+}
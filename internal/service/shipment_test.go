@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeWithOptions_MaxItemsPerShipment(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       int
+		capacity    int
+		wantTotals  []int
+		wantPacks   []PackBreakdown
+		wantOptimal bool
+		// wantAlgorithm is the top-level algorithm; shipments are always dp.
+		wantAlgorithm string
+	}{
+		{
+			name:          "splits into two shipments under the cap",
+			order:         1200,
+			capacity:      1000,
+			wantTotals:    []int{1000, 250},
+			wantPacks:     []PackBreakdown{{Size: 500, Count: 2}, {Size: 250, Count: 1}},
+			wantOptimal:   false,
+			wantAlgorithm: AlgorithmShipmentSplit,
+		},
+		{
+			name:          "remainder overfilling the cap takes another full shipment",
+			order:         300,
+			capacity:      400,
+			wantTotals:    []int{250, 250},
+			wantPacks:     []PackBreakdown{{Size: 250, Count: 2}},
+			wantOptimal:   false,
+			wantAlgorithm: AlgorithmShipmentSplit,
+		},
+		{
+			name:          "plan within the cap ships once",
+			order:         501,
+			capacity:      1000,
+			wantTotals:    []int{750},
+			wantPacks:     []PackBreakdown{{Size: 500, Count: 1}, {Size: 250, Count: 1}},
+			wantOptimal:   true,
+			wantAlgorithm: AlgorithmGreedyDivisible,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500})
+
+			plan, err := OptimizeWithOptions(t.Context(), tc.order, Options{MaxItemsPerShipment: tc.capacity})
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+
+			var totals []int
+			sum := 0
+			for _, shipment := range plan.Shipments {
+				if shipment.TotalItems > tc.capacity {
+					t.Fatalf("shipment %+v exceeds cap %d", shipment, tc.capacity)
+				}
+				totals = append(totals, shipment.TotalItems)
+				sum += shipment.TotalItems
+			}
+			if !reflect.DeepEqual(totals, tc.wantTotals) {
+				t.Fatalf("shipment totals = %v, want %v", totals, tc.wantTotals)
+			}
+			if plan.TotalItems != sum || sum < tc.order {
+				t.Fatalf("TotalItems = %d, shipments sum to %d, order %d", plan.TotalItems, sum, tc.order)
+			}
+			if !reflect.DeepEqual(plan.Packs, tc.wantPacks) {
+				t.Fatalf("Packs = %+v, want %+v", plan.Packs, tc.wantPacks)
+			}
+			if plan.Optimal != tc.wantOptimal || plan.Algorithm != tc.wantAlgorithm {
+				t.Fatalf("Optimal = %t, Algorithm = %q; want %t, %q", plan.Optimal, plan.Algorithm, tc.wantOptimal, tc.wantAlgorithm)
+			}
+			for _, shipment := range plan.Shipments {
+				if !shipment.Optimal || shipment.Algorithm == AlgorithmShipmentSplit {
+					t.Fatalf("shipment %+v should be an optimal plan of its share", shipment)
+				}
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_SingleShipmentHasSplitShape(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	plan, err := OptimizeWithOptions(t.Context(), 501, Options{MaxItemsPerShipment: 1000, Explain: true, NearestExact: true})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	if plan.Explanation == nil || plan.NearestExactAbove == nil {
+		t.Fatalf("expected the request-level extras on the plan: %+v", plan)
+	}
+
+	want := Plan{
+		ItemsOrdered:   501,
+		TotalItems:     750,
+		TotalPacks:     2,
+		Packs:          []PackBreakdown{{Size: 500, Count: 1}, {Size: 250, Count: 1}},
+		Overfill:       249,
+		WastePercent:   33.2,
+		OverfillSource: 500,
+		DrivingSize:    500,
+		Optimal:        true,
+		Algorithm:      AlgorithmDP,
+	}
+	if len(plan.Shipments) != 1 || !reflect.DeepEqual(plan.Shipments[0], want) {
+		t.Fatalf("Shipments = %+v, want [%+v]", plan.Shipments, want)
+	}
+}
+
+func TestOptimizeWithOptions_MaxItemsPerShipmentErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    int
+		capacity int
+		wantErr  error
+	}{
+		{name: "cap below every pack", order: 300, capacity: 100, wantErr: ErrInvalidShipmentCap},
+		{name: "too many shipments", order: 1_000_000, capacity: 250, wantErr: ErrTooManyShipments},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500})
+
+			_, err := OptimizeWithOptions(t.Context(), tc.order, Options{MaxItemsPerShipment: tc.capacity})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_ShipmentWeight(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       int
+		opts        Options
+		wantTotals  []int
+		wantWeights []int
+	}{
+		{
+			name:        "weight forces a split item count alone would not",
+			order:       1000,
+			opts:        Options{ItemWeight: 2, MaxShipmentWeight: 1000, MaxItemsPerShipment: 1000},
+			wantTotals:  []int{500, 500},
+			wantWeights: []int{1000, 1000},
+		},
+		{
+			name:        "tighter item cap wins",
+			order:       1000,
+			opts:        Options{ItemWeight: 1, MaxShipmentWeight: 1000, MaxItemsPerShipment: 250},
+			wantTotals:  []int{250, 250, 250, 250},
+			wantWeights: []int{250, 250, 250, 250},
+		},
+		{
+			name:        "light enough ships once",
+			order:       1000,
+			opts:        Options{ItemWeight: 3, MaxShipmentWeight: 3000},
+			wantTotals:  []int{1000},
+			wantWeights: []int{3000},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500, 1000})
+
+			plan, err := OptimizeWithOptions(t.Context(), tc.order, tc.opts)
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+
+			var totals, weights []int
+			for _, shipment := range plan.Shipments {
+				if shipment.Weight > tc.opts.MaxShipmentWeight {
+					t.Fatalf("shipment %+v exceeds weight %d", shipment, tc.opts.MaxShipmentWeight)
+				}
+				totals = append(totals, shipment.TotalItems)
+				weights = append(weights, shipment.Weight)
+			}
+			if !reflect.DeepEqual(totals, tc.wantTotals) || !reflect.DeepEqual(weights, tc.wantWeights) {
+				t.Fatalf("shipments = %v weighing %v, want %v weighing %v", totals, weights, tc.wantTotals, tc.wantWeights)
+			}
+			if plan.Weight != plan.TotalItems*tc.opts.ItemWeight {
+				t.Fatalf("Weight = %d, want %d", plan.Weight, plan.TotalItems*tc.opts.ItemWeight)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_ShipmentWeightErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr error
+	}{
+		{name: "missing item weight", opts: Options{MaxShipmentWeight: 1000}, wantErr: ErrInvalidShipmentWeight},
+		{name: "negative shipment weight", opts: Options{ItemWeight: 1, MaxShipmentWeight: -1}, wantErr: ErrInvalidShipmentWeight},
+		{name: "too light for any pack", opts: Options{ItemWeight: 5, MaxShipmentWeight: 1000}, wantErr: ErrShipmentTooLight},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{250, 500})
+
+			_, err := OptimizeWithOptions(t.Context(), 300, tc.opts)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
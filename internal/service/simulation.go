@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+)
+
+// Bounds of one SimulateDemand call.
+const (
+	maxSimulationSamples = 100_000
+	maxSimulationWorkers = 64
+)
+
+var ErrInvalidSimulation = errors.New("invalid simulation")
+
+// Simulation describes a demand simulation: Samples orders drawn uniformly
+// from [MinOrder, MaxOrder] by a generator seeded with Seed, optimized by
+// Workers goroutines (zero uses Config.SimulationWorkers).
+type Simulation struct {
+	Samples  int
+	MinOrder int
+	MaxOrder int
+	Seed     uint64
+	Workers  int
+}
+
+// SimulationResult aggregates the plans of every simulated order. It depends
+// only on the simulation and the catalog, never on Workers.
+type SimulationResult struct {
+	Seed              uint64 `json:"seed"`
+	Samples           int    `json:"samples"`
+	TotalItemsOrdered int    `json:"total_items_ordered"`
+	TotalItems        int    `json:"total_items"`
+	TotalPacks        int    `json:"total_packs"`
+	TotalOverfill     int    `json:"total_overfill"`
+	MaxOverfill       int    `json:"max_overfill"`
+	// ExactOrders counts the samples shipped without overfill.
+	ExactOrders  int     `json:"exact_orders"`
+	MeanPacks    float64 `json:"mean_packs"`
+	MeanOverfill float64 `json:"mean_overfill"`
+	// PackDemand counts the packs of each size across all samples, in
+	// descending size order; sizes no sample uses are listed with zero.
+	PackDemand []PackBreakdown `json:"pack_demand"`
+}
+
+// simulationTotals is the part of a SimulationResult workers accumulate.
+// Every field is an integer sum or maximum, so merging partial totals gives
+// the same result whichever worker planned which sample.
+type simulationTotals struct {
+	itemsOrdered int
+	items        int
+	packs        int
+	overfill     int
+	maxOverfill  int
+	exactOrders  int
+	demand       map[int]int
+}
+
+func (s *simulationTotals) add(plan Plan) {
+	s.itemsOrdered += plan.ItemsOrdered
+	s.items += plan.TotalItems
+	s.packs += plan.TotalPacks
+	s.overfill += plan.Overfill
+	s.maxOverfill = max(s.maxOverfill, plan.Overfill)
+	if plan.Overfill == 0 {
+		s.exactOrders++
+	}
+	for _, pack := range plan.Packs {
+		s.demand[pack.Size] += pack.Count
+	}
+}
+
+func (s *simulationTotals) merge(other simulationTotals) {
+	s.itemsOrdered += other.itemsOrdered
+	s.items += other.items
+	s.packs += other.packs
+	s.overfill += other.overfill
+	s.maxOverfill = max(s.maxOverfill, other.maxOverfill)
+	s.exactOrders += other.exactOrders
+	for size, count := range other.demand {
+		s.demand[size] += count
+	}
+}
+
+// SimulateDemand optimizes sim.Samples random orders against packSizes and
+// aggregates their plans, to estimate overfill and pack demand under a demand
+// profile. Samples are drawn serially up front, so a fixed seed gives the
+// same orders and, as aggregation is order-independent, the same result for
+// any worker count. Workers share one read-only table and normalized catalog.
+func SimulateDemand(ctx context.Context, sim Simulation, packSizes []int) (SimulationResult, error) {
+	if err := sim.validate(); err != nil {
+		return SimulationResult{}, err
+	}
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	planOrder, err := batchPlanner(ctx, sim.MaxOrder, normalized)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	rng := rand.New(rand.NewPCG(sim.Seed, sim.Seed))
+	orders := make([]int, sim.Samples)
+	for i := range orders {
+		orders[i] = sim.MinOrder + rng.IntN(sim.MaxOrder-sim.MinOrder+1)
+	}
+
+	workers := sim.workerCount()
+	partials := make([]simulationTotals, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		partials[w].demand = make(map[int]int, len(normalized))
+		wg.Go(func() {
+			// Worker w plans every workers-th sample; the split only affects
+			// which partial a sample lands in, never the merged totals.
+			for i := w; i < len(orders); i += workers {
+				if err := ctx.Err(); err != nil {
+					errs[w] = err
+					return
+				}
+				plan, err := planOrder(orders[i])
+				if err != nil {
+					errs[w] = fmt.Errorf("samples[%d]: %w", i, err)
+					return
+				}
+				partials[w].add(plan)
+			}
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return SimulationResult{}, err
+	}
+
+	totals := simulationTotals{demand: make(map[int]int, len(normalized))}
+	for _, partial := range partials {
+		totals.merge(partial)
+	}
+
+	result := SimulationResult{
+		Seed:              sim.Seed,
+		Samples:           sim.Samples,
+		TotalItemsOrdered: totals.itemsOrdered,
+		TotalItems:        totals.items,
+		TotalPacks:        totals.packs,
+		TotalOverfill:     totals.overfill,
+		MaxOverfill:       totals.maxOverfill,
+		ExactOrders:       totals.exactOrders,
+		MeanPacks:         float64(totals.packs) / float64(sim.Samples),
+		MeanOverfill:      float64(totals.overfill) / float64(sim.Samples),
+		PackDemand:        make([]PackBreakdown, 0, len(normalized)),
+	}
+	for _, size := range normalized {
+		result.PackDemand = append(result.PackDemand, PackBreakdown{Size: size, Count: totals.demand[size]})
+	}
+	return result, nil
+}
+
+func (s Simulation) validate() error {
+	if s.Samples <= 0 || s.Samples > maxSimulationSamples {
+		return fmt.Errorf("%w: samples must be between 1 and %d, got %d", ErrInvalidSimulation, maxSimulationSamples, s.Samples)
+	}
+	if s.MinOrder <= 0 || s.MaxOrder < s.MinOrder || s.MaxOrder > maxInt32Value {
+		return fmt.Errorf("%w: orders must satisfy 1 <= min_order <= max_order <= %d, got %d and %d", ErrInvalidSimulation, maxInt32Value, s.MinOrder, s.MaxOrder)
+	}
+	if s.Workers < 0 || s.Workers > maxSimulationWorkers {
+		return fmt.Errorf("%w: workers must be between 0 and %d, got %d", ErrInvalidSimulation, maxSimulationWorkers, s.Workers)
+	}
+	return nil
+}
+
+// workerCount resolves a zero Workers to the configured default, then to
+// GOMAXPROCS, and never runs more workers than samples.
+func (s Simulation) workerCount() int {
+	workers := s.Workers
+	if workers == 0 {
+		workers = currentConfig().SimulationWorkers
+	}
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return max(min(workers, s.Samples, maxSimulationWorkers), 1)
+}
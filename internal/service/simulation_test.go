@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestSimulateDemand_ParallelMatchesSerial runs many samples across several
+// workers; run with -race, it also checks workers share the table and
+// catalog without races.
+func TestSimulateDemand_ParallelMatchesSerial(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []int
+		sim       Simulation
+	}{
+		{
+			name:      "default catalog",
+			packSizes: []int{250, 500, 1000, 2000, 5000},
+			sim:       Simulation{Samples: 5000, MinOrder: 1, MaxOrder: 20000, Seed: 42},
+		},
+		{
+			name:      "coprime catalog",
+			packSizes: []int{23, 31, 53},
+			sim:       Simulation{Samples: 5000, MinOrder: 1, MaxOrder: 5000, Seed: 7},
+		},
+		{
+			name:      "divisible catalog",
+			packSizes: []int{10, 100, 1000},
+			sim:       Simulation{Samples: 2000, MinOrder: 500, MaxOrder: 900000, Seed: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serial := tt.sim
+			serial.Workers = 1
+			want, err := SimulateDemand(context.Background(), serial, tt.packSizes)
+			if err != nil {
+				t.Fatalf("serial SimulateDemand returned error: %v", err)
+			}
+
+			for _, workers := range []int{2, 8, 33} {
+				parallel := tt.sim
+				parallel.Workers = workers
+				got, err := SimulateDemand(context.Background(), parallel, tt.packSizes)
+				if err != nil {
+					t.Fatalf("SimulateDemand with %d workers returned error: %v", workers, err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("SimulateDemand with %d workers = %+v, want serial %+v", workers, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSimulateDemand_MatchesOptimizeWith(t *testing.T) {
+	packSizes := []int{250, 500, 1000, 2000, 5000}
+	sim := Simulation{Samples: 1, MinOrder: 12001, MaxOrder: 12001, Seed: 1, Workers: 4}
+
+	got, err := SimulateDemand(context.Background(), sim, packSizes)
+	if err != nil {
+		t.Fatalf("SimulateDemand returned error: %v", err)
+	}
+	plan, err := OptimizeWith(12001, packSizes)
+	if err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+
+	if got.TotalItemsOrdered != 12001 || got.TotalItems != plan.TotalItems || got.TotalPacks != plan.TotalPacks || got.TotalOverfill != plan.Overfill {
+		t.Fatalf("SimulateDemand = %+v, want totals of %+v", got, plan)
+	}
+	want := []PackBreakdown{{Size: 5000, Count: 2}, {Size: 2000, Count: 1}, {Size: 1000}, {Size: 500}, {Size: 250, Count: 1}}
+	if !reflect.DeepEqual(got.PackDemand, want) {
+		t.Fatalf("PackDemand = %+v, want %+v", got.PackDemand, want)
+	}
+}
+
+func TestSimulateDemand_SeedChangesSamples(t *testing.T) {
+	packSizes := []int{23, 31, 53}
+	sim := Simulation{Samples: 200, MinOrder: 1, MaxOrder: 10000, Seed: 1}
+
+	first, err := SimulateDemand(context.Background(), sim, packSizes)
+	if err != nil {
+		t.Fatalf("SimulateDemand returned error: %v", err)
+	}
+	sim.Seed = 2
+	second, err := SimulateDemand(context.Background(), sim, packSizes)
+	if err != nil {
+		t.Fatalf("SimulateDemand returned error: %v", err)
+	}
+
+	if first.TotalItemsOrdered == second.TotalItemsOrdered {
+		t.Fatalf("seeds 1 and 2 drew the same %d items", first.TotalItemsOrdered)
+	}
+}
+
+func TestSimulateDemand_ConfiguredWorkers(t *testing.T) {
+	setTestConfig(t, func(cfg *Config) { cfg.SimulationWorkers = 3 })
+
+	if got := (Simulation{Samples: 10}).workerCount(); got != 3 {
+		t.Fatalf("workerCount() = %d, want 3", got)
+	}
+	if got := (Simulation{Samples: 10, Workers: 5}).workerCount(); got != 5 {
+		t.Fatalf("workerCount() with Workers 5 = %d, want 5", got)
+	}
+	if got := (Simulation{Samples: 2, Workers: 5}).workerCount(); got != 2 {
+		t.Fatalf("workerCount() with 2 samples = %d, want 2", got)
+	}
+}
+
+func TestSimulateDemand_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		sim  Simulation
+	}{
+		{name: "no samples", sim: Simulation{MinOrder: 1, MaxOrder: 10}},
+		{name: "too many samples", sim: Simulation{Samples: maxSimulationSamples + 1, MinOrder: 1, MaxOrder: 10}},
+		{name: "zero min order", sim: Simulation{Samples: 1, MaxOrder: 10}},
+		{name: "min above max", sim: Simulation{Samples: 1, MinOrder: 11, MaxOrder: 10}},
+		{name: "negative workers", sim: Simulation{Samples: 1, MinOrder: 1, MaxOrder: 10, Workers: -1}},
+		{name: "too many workers", sim: Simulation{Samples: 1, MinOrder: 1, MaxOrder: 10, Workers: maxSimulationWorkers + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SimulateDemand(context.Background(), tt.sim, []int{250, 500})
+			if !errors.Is(err, ErrInvalidSimulation) {
+				t.Fatalf("SimulateDemand error = %v, want ErrInvalidSimulation", err)
+			}
+		})
+	}
+}
+
+func TestSimulateDemand_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := SimulateDemand(ctx, Simulation{Samples: 100, MinOrder: 1, MaxOrder: 100, Workers: 4}, []int{23, 31, 53})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SimulateDemand error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import "fmt"
+
+// maxSuggestOrder bounds the orders SuggestExactPackSize evaluates, which also
+// bounds the reachability table it builds.
+const maxSuggestOrder = 1_000_000
+
+// PackSizeSuggestion proposes a pack size whose addition lets an order ship exactly.
+type PackSizeSuggestion struct {
+	ItemsOrdered int   `json:"items_ordered"`
+	PackSizes    []int `json:"pack_sizes"`
+	// AlreadyExact is true when the current pack sizes already ship the order exactly.
+	AlreadyExact bool `json:"already_exact"`
+	// Feasible is false when no candidate size makes the order exact.
+	Feasible bool `json:"feasible"`
+	// SuggestedSize is the pack size to add; zero when no addition is needed.
+	SuggestedSize int `json:"suggested_size,omitempty"`
+	// TotalPacks is the number of packs in the exact plan, including the suggestion.
+	TotalPacks int `json:"total_packs,omitempty"`
+}
+
+// SuggestExactPackSize finds the single pack size that, added to packSizes,
+// lets itemsOrdered ship with zero overfill using the fewest packs. Ties are
+// broken toward the smaller size. Candidates are bounded to the range between
+// the smallest and largest current sizes so suggestions fit the catalog (the
+// order itself is always a trivial one-pack answer otherwise).
+//
+// Candidates are evaluated against one reachability table of the current
+// sizes: with a candidate c, the order is exact when order - k*c is reachable
+// for some k >= 1.
+func SuggestExactPackSize(itemsOrdered int, packSizes []int) (PackSizeSuggestion, error) {
+	if itemsOrdered <= 0 {
+		return PackSizeSuggestion{}, ErrInvalidItemsOrdered
+	}
+	if itemsOrdered > maxSuggestOrder {
+		return PackSizeSuggestion{}, fmt.Errorf("%w: suggestions support orders up to %d", ErrOptimizationTooLarge, maxSuggestOrder)
+	}
+
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return PackSizeSuggestion{}, err
+	}
+
+	table, err := newPackingTable(itemsOrdered, normalized)
+	if err != nil {
+		return PackSizeSuggestion{}, err
+	}
+	table.buildOptimalPackingTable()
+
+	suggestion := PackSizeSuggestion{
+		ItemsOrdered: itemsOrdered,
+		PackSizes:    normalized,
+	}
+	if table.minPacks[itemsOrdered] != table.unreachablePacks {
+		suggestion.AlreadyExact = true
+		suggestion.Feasible = true
+		suggestion.TotalPacks = table.minPacks[itemsOrdered]
+		return suggestion, nil
+	}
+
+	minCandidate := normalized[len(normalized)-1]
+	maxCandidate := min(itemsOrdered, normalized[0])
+	for candidate := minCandidate; candidate <= maxCandidate; candidate++ {
+		for count := 1; count*candidate <= itemsOrdered; count++ {
+			rest := table.minPacks[itemsOrdered-count*candidate]
+			if rest == table.unreachablePacks {
+				continue
+			}
+
+			packs := rest + count
+			if !suggestion.Feasible || packs < suggestion.TotalPacks {
+				suggestion.Feasible = true
+				suggestion.SuggestedSize = candidate
+				suggestion.TotalPacks = packs
+			}
+		}
+	}
+
+	return suggestion, nil
+}
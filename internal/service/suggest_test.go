@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSuggestExactPackSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []int
+		ordered   int
+		exact     bool
+		feasible  bool
+		size      int
+		packs     int
+	}{
+		{
+			name:      "single pack covering the order",
+			packSizes: []int{250, 500},
+			ordered:   300,
+			feasible:  true,
+			size:      300,
+			packs:     1,
+		},
+		{
+			name:      "combines the suggestion with existing packs",
+			packSizes: []int{6, 10},
+			ordered:   13,
+			feasible:  true,
+			size:      7,
+			packs:     2,
+		},
+		{
+			name:      "already exact",
+			packSizes: []int{250, 500},
+			ordered:   750,
+			exact:     true,
+			feasible:  true,
+			packs:     2,
+		},
+		{
+			name:      "no candidate within the catalog range",
+			packSizes: []int{250, 500},
+			ordered:   1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SuggestExactPackSize(tc.ordered, tc.packSizes)
+			if err != nil {
+				t.Fatalf("SuggestExactPackSize returned error: %v", err)
+			}
+
+			if got.AlreadyExact != tc.exact || got.Feasible != tc.feasible || got.SuggestedSize != tc.size || got.TotalPacks != tc.packs {
+				t.Fatalf("SuggestExactPackSize() = %+v, want exact=%t feasible=%t size=%d packs=%d", got, tc.exact, tc.feasible, tc.size, tc.packs)
+			}
+		})
+	}
+}
+
+func TestSuggestExactPackSize_Bounds(t *testing.T) {
+	if _, err := SuggestExactPackSize(0, []int{250}); !errors.Is(err, ErrInvalidItemsOrdered) {
+		t.Fatalf("expected ErrInvalidItemsOrdered, got %v", err)
+	}
+	if _, err := SuggestExactPackSize(maxSuggestOrder+1, []int{250}); !errors.Is(err, ErrOptimizationTooLarge) {
+		t.Fatalf("expected ErrOptimizationTooLarge, got %v", err)
+	}
+	if _, err := SuggestExactPackSize(300, []int{}); !errors.Is(err, ErrInvalidPackSizes) {
+		t.Fatalf("expected ErrInvalidPackSizes, got %v", err)
+	}
+}
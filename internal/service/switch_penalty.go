@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+const (
+	// maxSwitchPenaltySizes bounds the pack sizes whose subsets
+	// applySwitchPenalty enumerates.
+	maxSwitchPenaltySizes = 10
+	// maxSwitchPenaltyWork bounds the table cells filled across all subsets.
+	maxSwitchPenaltyWork = 50_000_000
+)
+
+// applySwitchPenalty replaces plan's packs with the combination reaching the
+// same total that minimizes packs + penalty*distinct sizes, ties going to
+// fewer packs. The shipped total never changes, so overfill stays minimal.
+// With maxPacks > 0, combinations of more packs are not considered.
+//
+// Every subset of the sizes that fit in the total is scored by the fewest
+// packs reaching the total exactly with that subset. A subset whose best
+// combination leaves a size unused also scores as its smaller subset, which
+// is enumerated too, so the minimum is exact.
+func applySwitchPenalty(plan *Plan, penalty, maxPacks int, sortedPackSizes []int) error {
+	total := plan.TotalItems
+	candidates, err := switchPenaltyCandidates(total, sortedPackSizes)
+	if err != nil {
+		return err
+	}
+	subsets := 1 << len(candidates)
+
+	bestScore, bestPacks := plan.TotalPacks+penalty*len(plan.Packs), plan.TotalPacks
+	for mask := 1; mask < subsets; mask++ {
+		distinct := bits.OnesCount(uint(mask))
+		// Every size in the subset ships at least once.
+		if lowerBound := distinct + penalty*distinct; lowerBound > bestScore {
+			continue
+		}
+
+		subset := make([]int, 0, distinct)
+		for i, size := range candidates {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, size)
+			}
+		}
+
+		table, err := newCoveringTable(subset, total)
+		if err != nil {
+			return err
+		}
+		table.buildOptimalPackingTable()
+		packs := table.minPacks[total]
+		if packs == table.unreachablePacks || (maxPacks > 0 && packs > maxPacks) {
+			continue
+		}
+
+		score := packs + penalty*distinct
+		if score > bestScore || (score == bestScore && packs >= bestPacks) {
+			continue
+		}
+
+		// The per-size breakdown is the best so far; TotalItems is unchanged.
+		best, err := table.shipment(plan.ItemsOrdered, total)
+		if err != nil {
+			return err
+		}
+		bestScore, bestPacks = score, packs
+		plan.Packs = best.Packs
+		plan.TotalPacks = best.TotalPacks
+		plan.DrivingSize = best.DrivingSize
+	}
+
+	return nil
+}
+
+// switchPenaltyCandidates returns the pack sizes applySwitchPenalty
+// enumerates subsets of for total, failing with ErrOptimizationTooLarge when
+// they exceed maxSwitchPenaltySizes or maxSwitchPenaltyWork.
+func switchPenaltyCandidates(total int, sortedPackSizes []int) ([]int, error) {
+	var candidates []int
+	for _, size := range sortedPackSizes {
+		if size <= total {
+			candidates = append(candidates, size)
+		}
+	}
+	if len(candidates) > maxSwitchPenaltySizes {
+		return nil, fmt.Errorf("%w: switch penalty supports up to %d pack sizes, got %d", ErrOptimizationTooLarge, maxSwitchPenaltySizes, len(candidates))
+	}
+	subsets := 1 << len(candidates)
+	if work := subsets / 2 * len(candidates) * (total + 1); work > maxSwitchPenaltyWork {
+		return nil, fmt.Errorf("%w: switch penalty needs %d steps (max %d)", ErrOptimizationTooLarge, work, maxSwitchPenaltyWork)
+	}
+	return candidates, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeWithOptions_SwitchPenalty(t *testing.T) {
+	tests := []struct {
+		name      string
+		penalty   int
+		wantPacks []PackBreakdown
+	}{
+		{
+			name:      "no penalty keeps fewest packs",
+			penalty:   0,
+			wantPacks: []PackBreakdown{{Size: 6, Count: 2}, {Size: 4, Count: 2}},
+		},
+		{
+			// 4 packs + 1*2 sizes ties 5 packs + 1*1 size; fewer packs win.
+			name:      "tie keeps fewer packs",
+			penalty:   1,
+			wantPacks: []PackBreakdown{{Size: 6, Count: 2}, {Size: 4, Count: 2}},
+		},
+		{
+			name:      "higher penalty favors a single size",
+			penalty:   2,
+			wantPacks: []PackBreakdown{{Size: 4, Count: 5}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			setOptimizerPackSizes(t, []int{4, 6})
+
+			plan, err := OptimizeWithOptions(context.Background(), 20, Options{SwitchPenalty: tc.penalty})
+			if err != nil {
+				t.Fatalf("OptimizeWithOptions returned error: %v", err)
+			}
+
+			if !reflect.DeepEqual(plan.Packs, tc.wantPacks) {
+				t.Fatalf("Packs = %v, want %v", plan.Packs, tc.wantPacks)
+			}
+			wantCount := 0
+			for _, pack := range tc.wantPacks {
+				wantCount += pack.Count
+			}
+			if plan.TotalItems != 20 || plan.TotalPacks != wantCount {
+				t.Fatalf("unexpected totals: %+v", plan)
+			}
+		})
+	}
+}
+
+func TestOptimizeWithOptions_SwitchPenaltyKeepsMinimumOverfill(t *testing.T) {
+	setOptimizerPackSizes(t, []int{250, 500, 1000, 2000, 5000})
+
+	plan, err := OptimizeWithOptions(context.Background(), 12001, Options{SwitchPenalty: 10})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+
+	// 12250 is still shipped. 6x2000 + 250 scores 7 + 2*10, beating the
+	// fewest packs (2x5000 + 2000 + 250: 4 + 3*10) and 49x250 (49 + 10).
+	want := []PackBreakdown{{Size: 2000, Count: 6}, {Size: 250, Count: 1}}
+	if plan.TotalItems != 12250 || !reflect.DeepEqual(plan.Packs, want) {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+}
+
+func TestOptimizeWithOptions_SwitchPenaltyRespectsMaxPacks(t *testing.T) {
+	setOptimizerPackSizes(t, []int{4, 6})
+
+	// A penalty of 2 prefers 5x4, but only 4 packs are allowed.
+	plan, err := OptimizeWithOptions(context.Background(), 20, Options{SwitchPenalty: 2, MaxPacks: 4})
+	if err != nil {
+		t.Fatalf("OptimizeWithOptions returned error: %v", err)
+	}
+	want := []PackBreakdown{{Size: 6, Count: 2}, {Size: 4, Count: 2}}
+	if !reflect.DeepEqual(plan.Packs, want) {
+		t.Fatalf("Packs = %v, want %v", plan.Packs, want)
+	}
+}
+
+func TestApplySwitchPenalty_TooManySizes(t *testing.T) {
+	sizes := make([]int, 0, maxSwitchPenaltySizes+1)
+	for size := maxSwitchPenaltySizes + 1; size >= 1; size-- {
+		sizes = append(sizes, size)
+	}
+	plan := Plan{ItemsOrdered: 100, TotalItems: 100, TotalPacks: 10}
+
+	if err := applySwitchPenalty(&plan, 1, 0, sizes); !errors.Is(err, ErrOptimizationTooLarge) {
+		t.Fatalf("expected ErrOptimizationTooLarge, got %v", err)
+	}
+}
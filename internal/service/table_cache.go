@@ -0,0 +1,235 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// maxTableCacheFileBytes guards against loading corrupted or foreign files.
+// A full-size table (maxTableEntries) stays well below this when gob-encoded.
+const maxTableCacheFileBytes = 64 << 20
+
+var errTableCacheMismatch = errors.New("cached table does not match its key")
+
+// tableSnapshot is the on-disk form of a packingTable. prevTotal is not stored
+// because it is always total - prevPack for reachable totals.
+type tableSnapshot struct {
+	PackSizes        []int
+	FulfillmentLimit int
+	MinPacks         []int
+	PrevPack         []int
+	UnreachablePacks int
+}
+
+var sharedTable struct {
+	mu    sync.RWMutex
+	table *packingTable
+}
+
+// PrepareTableCache makes a packing table for the current pack sizes covering
+// totals up to ceiling available to Optimize. It loads the table from dir when a
+// file for the same catalog and ceiling exists, otherwise it builds the table
+// and persists it there. It reports whether the table was loaded from disk.
+func PrepareTableCache(dir string, ceiling int) (bool, error) {
+	if ceiling <= 0 || ceiling+1 > maxTableEntries {
+		return false, fmt.Errorf("%w: table cache ceiling %d must be between 1 and %d", ErrOptimizationTooLarge, ceiling, maxTableEntries-1)
+	}
+
+	packSizeService, err := GetPackSizeService()
+	if err != nil {
+		return false, err
+	}
+	normalized, err := NormalizePackSizes(packSizeService.GetPackSizes())
+	if err != nil {
+		return false, err
+	}
+
+	path := tableCachePath(dir, normalized, ceiling)
+	table, err := loadPackingTable(path, normalized, ceiling)
+	if err == nil {
+		setCachedTable(&table)
+		return true, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+
+	table, err = newCoveringTable(normalized, ceiling)
+	if err != nil {
+		return false, err
+	}
+	table.buildOptimalPackingTable()
+	setCachedTable(&table)
+
+	if err := savePackingTable(path, table); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// newCoveringTable allocates a table whose fulfillmentLimit is ceiling, so it
+// can serve any order whose own limit does not exceed it.
+func newCoveringTable(sortedPackSizes []int, ceiling int) (packingTable, error) {
+	// newPackingTable derives the limit as itemsOrdered + largest - 1.
+	return newPackingTable(ceiling-sortedPackSizes[0]+1, sortedPackSizes)
+}
+
+// cachedTableFor returns a view of the cached table for itemsOrdered when the
+// cache was built for the same pack sizes and covers the order's range.
+func cachedTableFor(itemsOrdered int, sortedPackSizes []int) (packingTable, bool) {
+	sharedTable.mu.RLock()
+	defer sharedTable.mu.RUnlock()
+
+	cached := sharedTable.table
+	if cached == nil || !slices.Equal(cached.sortedPackSizes, sortedPackSizes) {
+		return packingTable{}, false
+	}
+	if int64(itemsOrdered)+int64(sortedPackSizes[0])-1 > int64(cached.fulfillmentLimit) {
+		return packingTable{}, false
+	}
+
+	return cached.forOrder(itemsOrdered), true
+}
+
+// dropStaleCachedTable releases the cached table if it was built for a
+// different catalog than sortedPackSizes.
+func dropStaleCachedTable(sortedPackSizes []int) {
+	sharedTable.mu.Lock()
+	defer sharedTable.mu.Unlock()
+
+	if sharedTable.table != nil && !slices.Equal(sharedTable.table.sortedPackSizes, sortedPackSizes) {
+		sharedTable.table = nil
+	}
+}
+
+func setCachedTable(table *packingTable) {
+	sharedTable.mu.Lock()
+	defer sharedTable.mu.Unlock()
+
+	sharedTable.table = table
+}
+
+// forOrder returns a copy of a built table that answers for itemsOrdered.
+// The underlying slices are shared and must be treated as read-only.
+func (t packingTable) forOrder(itemsOrdered int) packingTable {
+	t.itemsOrdered = itemsOrdered
+	return t
+}
+
+// catalogHash identifies a normalized catalog independently of input order.
+func catalogHash(sortedPackSizes []int) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, size := range sortedPackSizes {
+		binary.BigEndian.PutUint64(buf[:], uint64(size))
+		h.Write(buf[:])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func tableCachePath(dir string, sortedPackSizes []int, ceiling int) string {
+	return filepath.Join(dir, fmt.Sprintf("table-%s-%d.gob", catalogHash(sortedPackSizes), ceiling))
+}
+
+func savePackingTable(path string, table packingTable) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never observe a partial table.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := table.encode(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func loadPackingTable(path string, sortedPackSizes []int, ceiling int) (packingTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return packingTable{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return packingTable{}, err
+	}
+	if info.Size() > maxTableCacheFileBytes {
+		return packingTable{}, fmt.Errorf("table cache file %s is %d bytes (max %d)", path, info.Size(), maxTableCacheFileBytes)
+	}
+
+	table, err := decodePackingTable(io.LimitReader(file, maxTableCacheFileBytes))
+	if err != nil {
+		return packingTable{}, fmt.Errorf("decode table cache file %s: %w", path, err)
+	}
+	if !slices.Equal(table.sortedPackSizes, sortedPackSizes) || table.fulfillmentLimit != ceiling {
+		return packingTable{}, fmt.Errorf("%s: %w", path, errTableCacheMismatch)
+	}
+
+	return table, nil
+}
+
+func (t packingTable) encode(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(tableSnapshot{
+		PackSizes:        t.sortedPackSizes,
+		FulfillmentLimit: t.fulfillmentLimit,
+		MinPacks:         t.minPacks,
+		PrevPack:         t.prevPack,
+		UnreachablePacks: t.unreachablePacks,
+	})
+}
+
+func decodePackingTable(r io.Reader) (packingTable, error) {
+	var snapshot tableSnapshot
+	if err := gob.NewDecoder(r).Decode(&snapshot); err != nil {
+		return packingTable{}, err
+	}
+
+	entries := snapshot.FulfillmentLimit + 1
+	if snapshot.FulfillmentLimit <= 0 || entries > maxTableEntries ||
+		len(snapshot.MinPacks) != entries || len(snapshot.PrevPack) != entries {
+		return packingTable{}, errTableCacheMismatch
+	}
+	if _, err := NormalizePackSizes(snapshot.PackSizes); err != nil {
+		return packingTable{}, err
+	}
+
+	prevTotal := make([]int, entries)
+	for total := range prevTotal {
+		if snapshot.PrevPack[total] > 0 {
+			prevTotal[total] = total - snapshot.PrevPack[total]
+		} else {
+			prevTotal[total] = snapshot.PrevPack[total]
+		}
+	}
+
+	return packingTable{
+		sortedPackSizes:  snapshot.PackSizes,
+		fulfillmentLimit: snapshot.FulfillmentLimit,
+		minPacks:         snapshot.MinPacks,
+		prevTotal:        prevTotal,
+		prevPack:         snapshot.PrevPack,
+		unreachablePacks: snapshot.UnreachablePacks,
+	}, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func resetCachedTable(t *testing.T) {
+	t.Helper()
+
+	setCachedTable(nil)
+	t.Cleanup(func() { setCachedTable(nil) })
+}
+
+func TestPackingTable_EncodeDecodeRoundTrip(t *testing.T) {
+	table, err := newPackingTable(12001, []int{5000, 2000, 1000, 500, 250})
+	if err != nil {
+		t.Fatalf("newPackingTable returned error: %v", err)
+	}
+	table.buildOptimalPackingTable()
+
+	var buf bytes.Buffer
+	if err := table.encode(&buf); err != nil {
+		t.Fatalf("encode returned error: %v", err)
+	}
+
+	decoded, err := decodePackingTable(&buf)
+	if err != nil {
+		t.Fatalf("decodePackingTable returned error: %v", err)
+	}
+
+	table.itemsOrdered = 0
+	if !reflect.DeepEqual(decoded, table) {
+		t.Fatal("decoded table does not match the original")
+	}
+}
+
+func TestPrepareTableCache_PersistsAndReloads(t *testing.T) {
+	resetCachedTable(t)
+	setOptimizerPackSizes(t, []int{23, 31, 53})
+	dir := t.TempDir()
+
+	loaded, err := PrepareTableCache(dir, 20000)
+	if err != nil {
+		t.Fatalf("PrepareTableCache returned error: %v", err)
+	}
+	if loaded {
+		t.Fatal("expected first PrepareTableCache to build the table")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "table-*.gob"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one persisted table file, got %v (err %v)", files, err)
+	}
+
+	setCachedTable(nil)
+	loaded, err = PrepareTableCache(dir, 20000)
+	if err != nil {
+		t.Fatalf("PrepareTableCache returned error: %v", err)
+	}
+	if !loaded {
+		t.Fatal("expected second PrepareTableCache to load the table from disk")
+	}
+
+	for _, ordered := range []int{1, 54, 500, 12001, 19948} {
+		fromCache, err := Optimize(ordered)
+		if err != nil {
+			t.Fatalf("Optimize(%d) with cache returned error: %v", ordered, err)
+		}
+		if _, ok := cachedTableFor(ordered, []int{53, 31, 23}); !ok {
+			t.Fatalf("expected order %d to be served from the cached table", ordered)
+		}
+
+		setCachedTable(nil)
+		fresh, err := Optimize(ordered)
+		if err != nil {
+			t.Fatalf("Optimize(%d) without cache returned error: %v", ordered, err)
+		}
+		if !reflect.DeepEqual(fromCache, fresh) {
+			t.Fatalf("Optimize(%d) cached = %+v, fresh = %+v", ordered, fromCache, fresh)
+		}
+
+		if _, err := PrepareTableCache(dir, 20000); err != nil {
+			t.Fatalf("PrepareTableCache returned error: %v", err)
+		}
+	}
+}
+
+func TestCachedTableFor_InvalidatedByCatalogChange(t *testing.T) {
+	resetCachedTable(t)
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	if _, err := PrepareTableCache(t.TempDir(), 5000); err != nil {
+		t.Fatalf("PrepareTableCache returned error: %v", err)
+	}
+	if _, ok := cachedTableFor(300, []int{500, 250}); !ok {
+		t.Fatal("expected cached table for the prepared catalog")
+	}
+
+	setOptimizerPackSizes(t, []int{250, 600})
+	if _, ok := cachedTableFor(300, []int{600, 250}); ok {
+		t.Fatal("expected cached table to be ignored after the catalog changed")
+	}
+
+	plan, err := Optimize(300)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	if plan.TotalItems != 500 || plan.TotalPacks != 2 {
+		t.Fatalf("unexpected plan after catalog change: %+v", plan)
+	}
+}
+
+func TestCachedTableFor_OrderBeyondCeilingBuildsFreshTable(t *testing.T) {
+	resetCachedTable(t)
+	setOptimizerPackSizes(t, []int{250, 500})
+
+	if _, err := PrepareTableCache(t.TempDir(), 1000); err != nil {
+		t.Fatalf("PrepareTableCache returned error: %v", err)
+	}
+	if _, ok := cachedTableFor(600, []int{500, 250}); ok {
+		t.Fatal("expected order beyond the ceiling to bypass the cache")
+	}
+}
+
+func TestLoadPackingTable_RejectsOversizedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "table.gob")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create file: %v", err)
+	}
+	if err := file.Truncate(maxTableCacheFileBytes + 1); err != nil {
+		t.Fatalf("truncate file: %v", err)
+	}
+	file.Close()
+
+	if _, err := loadPackingTable(path, []int{250}, 1000); err == nil {
+		t.Fatal("expected oversized table cache file to be rejected")
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Limits of the tiered cost DP, whose work grows with the total times the
+// number of counts tried per size.
+const (
+	maxTieredCostTotal = 50_000
+	maxTieredCostWork  = 100_000_000
+	// maxTierUnitCost keeps plan costs far from int64 overflow.
+	maxTierUnitCost = 1 << 40
+)
+
+var ErrInvalidPricing = errors.New("pricing must list each pack size once with valid discount tiers")
+
+// DiscountTier sets the unit cost of a pack size from MinCount packs upwards.
+// Tiers are all-units discounts: reaching a tier reprices every pack of the size.
+type DiscountTier struct {
+	MinCount int   `json:"min_count"`
+	UnitCost int64 `json:"unit_cost"`
+}
+
+// PackPricing holds the discount tiers of one pack size; the first tier must
+// start at one pack.
+type PackPricing struct {
+	Size  int            `json:"size"`
+	Tiers []DiscountTier `json:"tiers"`
+}
+
+// CostLine is the cost of the packs of one size in a tiered cost plan.
+type CostLine struct {
+	Size     int   `json:"size"`
+	Count    int   `json:"count"`
+	UnitCost int64 `json:"unit_cost"`
+	Cost     int64 `json:"cost"`
+}
+
+// TieredCostPlan is the cheapest way to ship the minimum-overfill total under
+// quantity discount tiers.
+type TieredCostPlan struct {
+	ItemsOrdered int        `json:"items_ordered"`
+	TotalItems   int        `json:"total_items"`
+	TotalPacks   int        `json:"total_packs"`
+	TotalCost    int64      `json:"total_cost"`
+	Lines        []CostLine `json:"lines"`
+}
+
+// OptimizeTieredCost ships the same total as OptimizeWith (minimum overfill
+// for the priced sizes) but picks, among the combinations reaching it, the one
+// with the lowest cost under the discount tiers instead of the fewest packs.
+//
+// Because a pack's cost depends on how many of its size are bought, the DP
+// works size by size over exact totals and tries every count of each size;
+// this is bounded to totals up to maxTieredCostTotal and maxTieredCostWork
+// count evaluations. Ties in cost go to fewer packs.
+func OptimizeTieredCost(itemsOrdered int, pricing []PackPricing) (TieredCostPlan, error) {
+	sizes, err := validatePricing(pricing)
+	if err != nil {
+		return TieredCostPlan{}, err
+	}
+
+	base, err := OptimizeWith(itemsOrdered, sizes)
+	if err != nil {
+		return TieredCostPlan{}, err
+	}
+	total := base.TotalItems
+	if total > maxTieredCostTotal {
+		return TieredCostPlan{}, fmt.Errorf("%w: total %d exceeds tiered cost max %d", ErrOptimizationTooLarge, total, maxTieredCostTotal)
+	}
+	work := 0
+	for _, price := range pricing {
+		work += (total/price.Size + 1) * (total + 1)
+	}
+	if work > maxTieredCostWork {
+		return TieredCostPlan{}, fmt.Errorf("%w: tiered cost needs %d steps (max %d)", ErrOptimizationTooLarge, work, maxTieredCostWork)
+	}
+
+	const unreachable = math.MaxInt64
+	// best[t] is the lowest cost (then fewest packs) reaching exactly t with
+	// the sizes processed so far; chosen[i][t] is the count of size i used.
+	best := make([]int64, total+1)
+	packs := make([]int, total+1)
+	for t := range best {
+		best[t] = unreachable
+	}
+	best[0] = 0
+
+	chosen := make([][]int, len(pricing))
+	for i, price := range pricing {
+		next := make([]int64, total+1)
+		nextPacks := make([]int, total+1)
+		chosen[i] = make([]int, total+1)
+		for t := range next {
+			next[t] = unreachable
+		}
+
+		for t := 0; t <= total; t++ {
+			for count := 0; count*price.Size <= t; count++ {
+				prev := t - count*price.Size
+				if best[prev] == unreachable {
+					continue
+				}
+				cost := best[prev] + int64(count)*price.unitCost(count)
+				candidatePacks := packs[prev] + count
+				if cost < next[t] || (cost == next[t] && candidatePacks < nextPacks[t]) {
+					next[t] = cost
+					nextPacks[t] = candidatePacks
+					chosen[i][t] = count
+				}
+			}
+		}
+		best, packs = next, nextPacks
+	}
+
+	plan := TieredCostPlan{
+		ItemsOrdered: itemsOrdered,
+		TotalItems:   total,
+		TotalPacks:   packs[total],
+		TotalCost:    best[total],
+	}
+	remaining := total
+	for i := len(pricing) - 1; i >= 0; i-- {
+		count := chosen[i][remaining]
+		remaining -= count * pricing[i].Size
+		if count == 0 {
+			continue
+		}
+		unit := pricing[i].unitCost(count)
+		plan.Lines = append(plan.Lines, CostLine{Size: pricing[i].Size, Count: count, UnitCost: unit, Cost: int64(count) * unit})
+	}
+	if remaining != 0 {
+		return TieredCostPlan{}, errReconstructPlan
+	}
+	slices.SortFunc(plan.Lines, func(a, b CostLine) int { return b.Size - a.Size })
+
+	return plan, nil
+}
+
+// unitCost returns the unit cost that applies when buying count packs.
+func (p PackPricing) unitCost(count int) int64 {
+	unit := p.Tiers[0].UnitCost
+	for _, tier := range p.Tiers {
+		if count >= tier.MinCount {
+			unit = tier.UnitCost
+		}
+	}
+	return unit
+}
+
+// validatePricing checks the pricing and returns its pack sizes.
+func validatePricing(pricing []PackPricing) ([]int, error) {
+	if len(pricing) == 0 {
+		return nil, ErrInvalidPricing
+	}
+
+	sizes := make([]int, 0, len(pricing))
+	for _, price := range pricing {
+		if slices.Contains(sizes, price.Size) {
+			return nil, fmt.Errorf("%w: size %d is priced twice", ErrInvalidPricing, price.Size)
+		}
+		if len(price.Tiers) == 0 || price.Tiers[0].MinCount != 1 {
+			return nil, fmt.Errorf("%w: size %d needs a first tier starting at 1 pack", ErrInvalidPricing, price.Size)
+		}
+		for i, tier := range price.Tiers {
+			if tier.UnitCost < 0 || tier.UnitCost > maxTierUnitCost {
+				return nil, fmt.Errorf("%w: size %d unit costs must be between 0 and %d", ErrInvalidPricing, price.Size, int64(maxTierUnitCost))
+			}
+			if i > 0 && tier.MinCount <= price.Tiers[i-1].MinCount {
+				return nil, fmt.Errorf("%w: size %d tiers must have increasing min_count", ErrInvalidPricing, price.Size)
+			}
+		}
+		sizes = append(sizes, price.Size)
+	}
+
+	if _, err := NormalizePackSizes(sizes); err != nil {
+		return nil, err
+	}
+	return sizes, nil
+}
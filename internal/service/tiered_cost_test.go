@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeTieredCost_DiscountTierChangesCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		small     []DiscountTier
+		wantLines []CostLine
+		wantCost  int64
+	}{
+		{
+			name:      "no discount keeps fewest packs",
+			small:     []DiscountTier{{MinCount: 1, UnitCost: 100}},
+			wantLines: []CostLine{{Size: 500, Count: 2, UnitCost: 150, Cost: 300}},
+			wantCost:  300,
+		},
+		{
+			name:      "bulk tier favors more small packs",
+			small:     []DiscountTier{{MinCount: 1, UnitCost: 100}, {MinCount: 4, UnitCost: 60}},
+			wantLines: []CostLine{{Size: 250, Count: 4, UnitCost: 60, Cost: 240}},
+			wantCost:  240,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pricing := []PackPricing{
+				{Size: 250, Tiers: tc.small},
+				{Size: 500, Tiers: []DiscountTier{{MinCount: 1, UnitCost: 150}}},
+			}
+
+			got, err := OptimizeTieredCost(1000, pricing)
+			if err != nil {
+				t.Fatalf("OptimizeTieredCost returned error: %v", err)
+			}
+			if got.TotalItems != 1000 || got.TotalCost != tc.wantCost {
+				t.Fatalf("plan = %+v, want 1000 items costing %d", got, tc.wantCost)
+			}
+			if !reflect.DeepEqual(got.Lines, tc.wantLines) {
+				t.Fatalf("Lines = %+v, want %+v", got.Lines, tc.wantLines)
+			}
+		})
+	}
+}
+
+func TestOptimizeTieredCost_KeepsMinimumOverfillTotal(t *testing.T) {
+	pricing := []PackPricing{
+		{Size: 250, Tiers: []DiscountTier{{MinCount: 1, UnitCost: 1}}},
+		{Size: 500, Tiers: []DiscountTier{{MinCount: 1, UnitCost: 1000}}},
+	}
+
+	got, err := OptimizeTieredCost(251, pricing)
+	if err != nil {
+		t.Fatalf("OptimizeTieredCost returned error: %v", err)
+	}
+	// Two cheap 250s ship the same 500 as one expensive 500.
+	want := TieredCostPlan{
+		ItemsOrdered: 251,
+		TotalItems:   500,
+		TotalPacks:   2,
+		TotalCost:    2,
+		Lines:        []CostLine{{Size: 250, Count: 2, UnitCost: 1, Cost: 2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("OptimizeTieredCost() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOptimizeTieredCost_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   int
+		pricing []PackPricing
+		wantErr error
+	}{
+		{name: "no pricing", order: 10, wantErr: ErrInvalidPricing},
+		{name: "duplicate size", order: 10, pricing: []PackPricing{
+			{Size: 5, Tiers: []DiscountTier{{MinCount: 1, UnitCost: 1}}},
+			{Size: 5, Tiers: []DiscountTier{{MinCount: 1, UnitCost: 1}}},
+		}, wantErr: ErrInvalidPricing},
+		{name: "first tier above one", order: 10, pricing: []PackPricing{
+			{Size: 5, Tiers: []DiscountTier{{MinCount: 2, UnitCost: 1}}},
+		}, wantErr: ErrInvalidPricing},
+		{name: "tiers out of order", order: 10, pricing: []PackPricing{
+			{Size: 5, Tiers: []DiscountTier{{MinCount: 1, UnitCost: 2}, {MinCount: 1, UnitCost: 1}}},
+		}, wantErr: ErrInvalidPricing},
+		{name: "invalid size", order: 10, pricing: []PackPricing{
+			{Size: 0, Tiers: []DiscountTier{{MinCount: 1, UnitCost: 1}}},
+		}, wantErr: ErrInvalidPackSizes},
+		{name: "total too large", order: maxTieredCostTotal + 1, pricing: []PackPricing{
+			{Size: 5, Tiers: []DiscountTier{{MinCount: 1, UnitCost: 1}}},
+		}, wantErr: ErrOptimizationTooLarge},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := OptimizeTieredCost(tc.order, tc.pricing); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
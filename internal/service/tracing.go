@@ -0,0 +1,15 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "gymshark/internal/service"
+
+// tracer resolves the service tracer from the global provider on every call so
+// a provider installed after startup (or in tests) is picked up. It is a no-op
+// until telemetry is configured.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
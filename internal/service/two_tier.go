@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrInvalidTwoTier = errors.New("filler_sizes must be non-empty and must not repeat a primary size")
+
+// OptimizeTwoTier packs itemsOrdered from a two-tier catalog: primary sizes
+// are the regular packs and filler sizes are only used to trim overfill.
+//
+// The shipped total is the minimum-overfill total over both tiers, so filler
+// packs never make the plan ship more. Among the combinations reaching that
+// total, the plan uses the fewest filler packs, then the fewest packs
+// overall; when primary packs alone reach it, no filler ships at all.
+func OptimizeTwoTier(itemsOrdered int, primarySizes, fillerSizes []int) (Plan, error) {
+	primary, err := NormalizePackSizes(primarySizes)
+	if err != nil {
+		return Plan{}, fmt.Errorf("primary sizes: %w", err)
+	}
+	if len(fillerSizes) == 0 {
+		return Plan{}, ErrInvalidTwoTier
+	}
+	filler, err := NormalizePackSizes(fillerSizes)
+	if err != nil {
+		return Plan{}, fmt.Errorf("filler sizes: %w", err)
+	}
+	for _, size := range filler {
+		if slices.Contains(primary, size) {
+			return Plan{}, fmt.Errorf("%w: %d", ErrInvalidTwoTier, size)
+		}
+	}
+
+	all := append(slices.Clone(primary), filler...)
+	slices.SortFunc(all, func(a, b int) int { return b - a })
+
+	plan, err := OptimizeWith(itemsOrdered, all)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	packs, fillerPacks, drivingSize, err := fewestFillerBreakdown(plan.TotalItems, all, filler)
+	if err != nil {
+		return Plan{}, err
+	}
+	plan.Packs = packs
+	plan.TotalPacks = TotalPhysicalPacks(packs)
+	plan.FillerPacks = fillerPacks
+	plan.DrivingSize = drivingSize
+	setOverfillMetrics(&plan)
+	return plan, nil
+}
+
+// fewestFillerBreakdown reconstructs total from sortedPackSizes minimizing
+// filler packs first and total packs second. It runs its own DP because the
+// shared table only tracks the pack count; total is already bounded by the
+// table that chose it.
+func fewestFillerBreakdown(total int, sortedPackSizes, filler []int) ([]PackBreakdown, int, int, error) {
+	fillerCount := make([]int, total+1)
+	packCount := make([]int, total+1)
+	prevPack := make([]int, total+1)
+	for t := 1; t <= total; t++ {
+		fillerCount[t] = -1
+		for _, size := range sortedPackSizes {
+			if size > t || fillerCount[t-size] < 0 {
+				continue
+			}
+			f := fillerCount[t-size]
+			if slices.Contains(filler, size) {
+				f++
+			}
+			p := packCount[t-size] + 1
+			if fillerCount[t] < 0 || f < fillerCount[t] || (f == fillerCount[t] && p < packCount[t]) {
+				fillerCount[t], packCount[t], prevPack[t] = f, p, size
+			}
+		}
+	}
+	if fillerCount[total] < 0 {
+		return nil, 0, 0, errReconstructPlan
+	}
+
+	counts := make(map[int]int)
+	for t := total; t > 0; t -= prevPack[t] {
+		counts[prevPack[t]]++
+	}
+	packs := make([]PackBreakdown, 0, len(counts))
+	for _, size := range sortedPackSizes {
+		if counts[size] > 0 {
+			packs = append(packs, PackBreakdown{Size: size, Count: counts[size]})
+		}
+	}
+	return packs, fillerCount[total], prevPack[total], nil
+}
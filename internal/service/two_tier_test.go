@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOptimizeTwoTier(t *testing.T) {
+	tests := []struct {
+		name        string
+		primary     []int
+		filler      []int
+		ordered     int
+		wantTotal   int
+		wantPacks   []PackBreakdown
+		wantFillers int
+	}{
+		{
+			name:        "filler trims overfill primary packs cannot",
+			primary:     []int{300, 200},
+			filler:      []int{50},
+			ordered:     510,
+			wantTotal:   550,
+			wantPacks:   []PackBreakdown{{Size: 300, Count: 1}, {Size: 200, Count: 1}, {Size: 50, Count: 1}},
+			wantFillers: 1,
+		},
+		{
+			name:        "primary packs preferred over fewer filler packs",
+			primary:     []int{300, 200},
+			filler:      []int{500},
+			ordered:     1000,
+			wantTotal:   1000,
+			wantPacks:   []PackBreakdown{{Size: 300, Count: 2}, {Size: 200, Count: 2}},
+			wantFillers: 0,
+		},
+		{
+			name:        "filler needed for every pack",
+			primary:     []int{250},
+			filler:      []int{400},
+			ordered:     800,
+			wantTotal:   800,
+			wantPacks:   []PackBreakdown{{Size: 400, Count: 2}},
+			wantFillers: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := OptimizeTwoTier(tt.ordered, tt.primary, tt.filler)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if plan.TotalItems != tt.wantTotal {
+				t.Fatalf("expected total %d, got %d", tt.wantTotal, plan.TotalItems)
+			}
+			if !reflect.DeepEqual(plan.Packs, tt.wantPacks) {
+				t.Fatalf("expected packs %v, got %v", tt.wantPacks, plan.Packs)
+			}
+			if plan.FillerPacks != tt.wantFillers {
+				t.Fatalf("expected %d filler packs, got %d", tt.wantFillers, plan.FillerPacks)
+			}
+			if plan.TotalPacks != TotalPhysicalPacks(tt.wantPacks) {
+				t.Fatalf("expected %d packs, got %d", TotalPhysicalPacks(tt.wantPacks), plan.TotalPacks)
+			}
+			if plan.Overfill != tt.wantTotal-tt.ordered {
+				t.Fatalf("expected overfill %d, got %d", tt.wantTotal-tt.ordered, plan.Overfill)
+			}
+		})
+	}
+}
+
+func TestOptimizeTwoTierPrimaryOnlyOverfills(t *testing.T) {
+	primaryOnly, err := OptimizeWith(510, []int{300, 200})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	twoTier, err := OptimizeTwoTier(510, []int{300, 200}, []int{50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if twoTier.Overfill >= primaryOnly.Overfill {
+		t.Fatalf("expected filler to trim overfill below %d, got %d", primaryOnly.Overfill, twoTier.Overfill)
+	}
+}
+
+func TestOptimizeTwoTierRejectsInvalidTiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		primary []int
+		filler  []int
+		wantErr error
+	}{
+		{name: "no filler", primary: []int{250}, filler: nil, wantErr: ErrInvalidTwoTier},
+		{name: "shared size", primary: []int{250, 500}, filler: []int{500}, wantErr: ErrInvalidTwoTier},
+		{name: "no primary", primary: nil, filler: []int{50}, wantErr: ErrInvalidPackSizes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := OptimizeTwoTier(100, tt.primary, tt.filler); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+var tableWarmup struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startTableWarmup precomputes, in the background, a packing table for
+// packSizes covering totals up to Config.WarmupCeiling, so the first
+// optimizations after a catalog change hit the cached table. Any warm-up still
+// running for a previous catalog is cancelled. It never blocks the caller.
+func startTableWarmup(packSizes []int) {
+	tableWarmup.mu.Lock()
+	defer tableWarmup.mu.Unlock()
+
+	if tableWarmup.cancel != nil {
+		tableWarmup.cancel()
+		tableWarmup.cancel = nil
+	}
+
+	normalized, err := NormalizePackSizes(packSizes)
+	if err != nil {
+		return
+	}
+	dropStaleCachedTable(normalized)
+
+	ceiling := currentConfig().WarmupCeiling
+	if ceiling <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	tableWarmup.cancel = cancel
+	tableWarmup.done = done
+
+	go func() {
+		defer close(done)
+		defer cancel()
+
+		table, err := newCoveringTable(normalized, ceiling)
+		if err != nil {
+			return
+		}
+		if err := table.buildOptimalPackingTableContext(ctx); err != nil {
+			return
+		}
+
+		tableWarmup.mu.Lock()
+		defer tableWarmup.mu.Unlock()
+		// A newer catalog may have cancelled this warm-up after the build finished.
+		if ctx.Err() == nil {
+			setCachedTable(&table)
+		}
+	}()
+}
+
+// waitForTableWarmup blocks until the most recent warm-up has finished.
+func waitForTableWarmup() {
+	tableWarmup.mu.Lock()
+	done := tableWarmup.done
+	tableWarmup.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+}
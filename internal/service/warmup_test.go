@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTableWarmup_BuildsTableAfterCatalogChange(t *testing.T) {
+	resetCachedTable(t)
+	setTestConfig(t, func(cfg *Config) { cfg.WarmupCeiling = 50_000 })
+	t.Cleanup(waitForTableWarmup)
+
+	setOptimizerPackSizes(t, []int{23, 31, 53})
+	waitForCachedTable(t, []int{53, 31, 23})
+
+	plan, err := Optimize(500)
+	if err != nil {
+		t.Fatalf("Optimize returned error: %v", err)
+	}
+	fresh, err := OptimizeWith(500, []int{23, 31, 53})
+	if err != nil {
+		t.Fatalf("OptimizeWith returned error: %v", err)
+	}
+	// Only plans for the configured catalog carry an ID.
+	plan.PlanID = ""
+	if !reflect.DeepEqual(plan, fresh) {
+		t.Fatalf("warm plan = %+v, fresh plan = %+v", plan, fresh)
+	}
+}
+
+func TestTableWarmup_InvalidatedByNextChange(t *testing.T) {
+	resetCachedTable(t)
+	setTestConfig(t, func(cfg *Config) { cfg.WarmupCeiling = 50_000 })
+	t.Cleanup(waitForTableWarmup)
+
+	setOptimizerPackSizes(t, []int{250, 500})
+	waitForCachedTable(t, []int{500, 250})
+
+	setOptimizerPackSizes(t, []int{6, 10})
+	if _, ok := cachedTableFor(300, []int{500, 250}); ok {
+		t.Fatal("expected the previous warm table to be invalidated")
+	}
+	waitForCachedTable(t, []int{10, 6})
+}
+
+func TestTableWarmup_DisabledByDefault(t *testing.T) {
+	resetCachedTable(t)
+	t.Cleanup(waitForTableWarmup)
+
+	setOptimizerPackSizes(t, []int{250, 500})
+	waitForTableWarmup()
+
+	if _, ok := cachedTableFor(300, []int{500, 250}); ok {
+		t.Fatal("expected no warm table when WarmupCeiling is zero")
+	}
+}
+
+func TestBuildOptimalPackingTableContext_Cancelled(t *testing.T) {
+	table, err := newPackingTable(ctxCheckInterval*2, []int{7, 3})
+	if err != nil {
+		t.Fatalf("newPackingTable returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := table.buildOptimalPackingTableContext(ctx); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func waitForCachedTable(t *testing.T, sortedPackSizes []int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := cachedTableFor(1, sortedPackSizes); ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("warm table for %v was not built in time", sortedPackSizes)
+}
@@ -36,7 +36,7 @@ async function apiFetch(path, options = {}) {
 
   const data = await response.json();
   if (!response.ok) {
-    throw new Error(data.error || "Request failed");
+    throw new Error((data.error && data.error.message) || "Request failed");
   }
 
   return data;
@@ -96,11 +96,16 @@ function updateQueryString(itemsOrdered) {
   window.history.replaceState({}, "", nextUrl);
 }
 
+// maxPackSizes is the server's limit on distinct pack sizes, learned from the
+// last catalog response so the form can reject longer lists before submitting.
+let maxPackSizes = null;
+
 async function fetchPackSizes() {
   const data = await apiFetch("/api/pack-sizes");
   if (!Array.isArray(data.pack_sizes)) {
     throw new Error("invalid pack_sizes response");
   }
+  maxPackSizes = data.max_pack_sizes ?? null;
 
   return parsePackSizes(data.pack_sizes.join(","));
 }
@@ -113,6 +118,7 @@ async function updatePackSizes(packSizes) {
   if (!Array.isArray(data.pack_sizes)) {
     throw new Error("invalid pack_sizes response");
   }
+  maxPackSizes = data.max_pack_sizes ?? null;
 
   return parsePackSizes(data.pack_sizes.join(","));
 }
@@ -165,6 +171,9 @@ updatePackSizesForm.addEventListener("submit", async (event) => {
 
   try {
     const packSizes = parsePackSizes(packSizesInput.value);
+    if (maxPackSizes !== null && packSizes.length > maxPackSizes) {
+      throw new Error(`pack_sizes must contain at most ${maxPackSizes} distinct sizes.`);
+    }
     const updatedPackSizes = await updatePackSizes(packSizes);
 
     packSizesInput.value = updatedPackSizes.join(",");